@@ -0,0 +1,163 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/openshift/osde2e/cmd/osde2e/common"
+	"github.com/openshift/osde2e/cmd/osde2e/helpers"
+	clusterutil "github.com/openshift/osde2e/pkg/common/cluster"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manages test clusters.",
+	Long:  "Manages clusters used for testing, e.g. listing the clusters osde2e owns, extending their expiry or hibernating long-lived clusters between runs.",
+	Args:  cobra.OnlyValidArgs,
+}
+
+var hibernateCmd = &cobra.Command{
+	Use:   "hibernate",
+	Short: "Hibernates a cluster.",
+	Long:  "Hibernates a ready cluster, shutting down its machines while keeping its state, and waits until it's hibernating.",
+	Args:  cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runHibernate(cmd.Context())
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resumes a hibernating cluster.",
+	Long:  "Resumes a hibernating cluster and waits until it's ready.",
+	Args:  cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runResume(cmd.Context())
+	},
+}
+
+var args struct {
+	configString    string
+	customConfig    string
+	secretLocations string
+	clusterID       string
+	environment     string
+	timeout         time.Duration
+}
+
+func init() {
+	pfs := Cmd.PersistentFlags()
+	pfs.StringVar(
+		&args.configString,
+		"configs",
+		"",
+		"A comma separated list of built in configs to use",
+	)
+	_ = Cmd.RegisterFlagCompletionFunc("configs", helpers.ConfigComplete)
+	pfs.StringVar(
+		&args.customConfig,
+		"custom-config",
+		"",
+		"Custom config file for osde2e",
+	)
+	pfs.StringVar(
+		&args.secretLocations,
+		"secret-locations",
+		"",
+		"A comma separated list of possible secret directory locations for loading secret configs.",
+	)
+	pfs.StringVarP(
+		&args.clusterID,
+		"cluster-id",
+		"i",
+		"",
+		"ID of the cluster to manage.",
+	)
+	pfs.StringVarP(
+		&args.environment,
+		"environment",
+		"e",
+		"",
+		"Cluster provider environment to use.",
+	)
+	for _, cmd := range []*cobra.Command{hibernateCmd, resumeCmd} {
+		cmd.Flags().DurationVar(
+			&args.timeout,
+			"timeout",
+			30*time.Minute,
+			"How long to wait for the cluster. 0 only starts the operation.",
+		)
+	}
+
+	_ = viper.BindPFlag(config.Cluster.ID, Cmd.PersistentFlags().Lookup("cluster-id"))
+	_ = viper.BindPFlag(ocmprovider.Env, Cmd.PersistentFlags().Lookup("environment"))
+
+	Cmd.AddCommand(hibernateCmd)
+	Cmd.AddCommand(resumeCmd)
+	addManageCommands()
+}
+
+// setup loads the config and returns the configured provider and the ID of the cluster to manage,
+// which may be empty unless it's required.
+func setup(requireClusterID bool) (spi.Provider, string, error) {
+	clusterID, err := loadConfigs(requireClusterID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	provider, err := providers.ClusterProvider()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting cluster provider: %w", err)
+	}
+	return provider, clusterID, nil
+}
+
+// loadConfigs loads the config and returns the ID of the cluster to manage, which may be empty
+// unless it's required.
+func loadConfigs(requireClusterID bool) (string, error) {
+	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
+		return "", fmt.Errorf("error loading initial state: %v", err)
+	}
+
+	clusterID := viper.GetString(config.Cluster.ID)
+	if clusterID == "" && requireClusterID {
+		return "", fmt.Errorf("a cluster ID is required, set --cluster-id or CLUSTER_ID")
+	}
+	return clusterID, nil
+}
+
+func runHibernate(ctx context.Context) error {
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterutil.HibernateCluster(ctx, provider, clusterID, args.timeout)
+	if err != nil {
+		return err
+	}
+	log.Printf("Cluster %s is %s", clusterID, cluster.State())
+	return nil
+}
+
+func runResume(ctx context.Context) error {
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterutil.ResumeCluster(ctx, provider, clusterID, args.timeout)
+	if err != nil {
+		return err
+	}
+	log.Printf("Cluster %s is %s", clusterID, cluster.State())
+	return nil
+}
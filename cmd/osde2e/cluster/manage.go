@@ -0,0 +1,322 @@
+package cluster
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/osde2e/cmd/osde2e/common"
+	clusterutil "github.com/openshift/osde2e/pkg/common/cluster"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/spf13/cobra"
+)
+
+// ownedQuery selects the clusters osde2e launched.
+const ownedQuery = "properties.MadeByOSDe2e='true'"
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the clusters owned by the current account.",
+	Long:  "Lists the clusters of the configured provider that match the search query, by default the ones osde2e launched. OCM and ROSA only list those of the current account.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runList()
+	},
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Shows the details of a cluster.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runGet()
+	},
+}
+
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Fetches the kubeconfig of a cluster.",
+	Long:  "Fetches the kubeconfig of a cluster and writes it to stdout or to a file.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runKubeconfig()
+	},
+}
+
+var extendCmd = &cobra.Command{
+	Use:   "extend",
+	Short: "Extends the expiry of a cluster.",
+	Long:  "Extends the expiry of a cluster by the given hours and minutes. Clusters in prod can't be extended.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runExtend()
+	},
+}
+
+var expireCmd = &cobra.Command{
+	Use:   "expire",
+	Short: "Sets a cluster to expire.",
+	Long:  "Sets a cluster to expire after the given duration, so it's deleted without waiting for its current expiry. Clusters in prod can't be expired.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runExpire()
+	},
+}
+
+var propertiesCmd = &cobra.Command{
+	Use:   "properties [name=value...]",
+	Short: "Shows or sets the properties of a cluster.",
+	Long:  "Shows the properties of a cluster, after setting the properties given as name=value arguments.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		_, err := parseProperties(args)
+		return err
+	},
+	RunE: func(cmd *cobra.Command, properties []string) error {
+		return runProperties(properties)
+	},
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a cluster.",
+	Long:  "Deletes a cluster. Asks for --yes, as the cluster can't be recovered.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runDelete()
+	},
+}
+
+var manageArgs struct {
+	output   string
+	query    string
+	file     string
+	hours    uint64
+	minutes  uint64
+	expireIn time.Duration
+	yes      bool
+}
+
+func addManageCommands() {
+	for _, cmd := range []*cobra.Command{listCmd, getCmd, propertiesCmd} {
+		cmd.Flags().StringVarP(
+			&manageArgs.output,
+			"output",
+			"o",
+			clusterutil.OutputTable,
+			"Output format, one of "+strings.Join(clusterutil.OutputFormats, ", "),
+		)
+		_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return clusterutil.OutputFormats, cobra.ShellCompDirectiveDefault
+		})
+	}
+	listCmd.Flags().StringVar(
+		&manageArgs.query,
+		"query",
+		ownedQuery,
+		"Search query the clusters have to match, in the syntax of the provider",
+	)
+	kubeconfigCmd.Flags().StringVarP(
+		&manageArgs.file,
+		"file",
+		"f",
+		"",
+		"File to write the kubeconfig to instead of stdout",
+	)
+	extendCmd.Flags().Uint64Var(
+		&manageArgs.hours,
+		"hours",
+		0,
+		"Hours to extend the expiry by",
+	)
+	extendCmd.Flags().Uint64Var(
+		&manageArgs.minutes,
+		"minutes",
+		0,
+		"Minutes to extend the expiry by",
+	)
+	expireCmd.Flags().DurationVar(
+		&manageArgs.expireIn,
+		"in",
+		0,
+		"How long from now the cluster expires. Accepts a sequence of decimal numbers with a unit suffix, such as '2h45m'",
+	)
+
+	deleteCmd.Flags().BoolVar(
+		&manageArgs.yes,
+		"yes",
+		false,
+		"Confirm deleting the cluster",
+	)
+
+	for _, cmd := range []*cobra.Command{listCmd, getCmd, kubeconfigCmd, propertiesCmd} {
+		common.WritesToStdout(cmd)
+	}
+
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(getCmd)
+	Cmd.AddCommand(kubeconfigCmd)
+	Cmd.AddCommand(extendCmd)
+	Cmd.AddCommand(expireCmd)
+	Cmd.AddCommand(propertiesCmd)
+	Cmd.AddCommand(deleteCmd)
+}
+
+// ownedClusterLister lists the clusters of the current account, which the OCM and ROSA providers can.
+type ownedClusterLister interface {
+	ListOwnedClusters(query string) ([]*spi.Cluster, error)
+}
+
+func runList() error {
+	provider, _, err := setup(false)
+	if err != nil {
+		return err
+	}
+
+	var clusters []*spi.Cluster
+	if lister, ok := spi.As[ownedClusterLister](provider); ok {
+		clusters, err = lister.ListOwnedClusters(manageArgs.query)
+	} else {
+		clusters, err = provider.ListClusters(manageArgs.query)
+	}
+	if err != nil {
+		return fmt.Errorf("error listing clusters: %w", err)
+	}
+	return clusterutil.WriteClusters(os.Stdout, manageArgs.output, clusters)
+}
+
+func runGet() error {
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := provider.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+	}
+	return clusterutil.WriteCluster(os.Stdout, manageArgs.output, cluster)
+}
+
+func runKubeconfig() error {
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	kubeconfig, err := provider.ClusterKubeconfig(clusterID)
+	if err != nil {
+		return fmt.Errorf("error getting the kubeconfig of cluster %s: %w", clusterID, err)
+	}
+
+	if manageArgs.file == "" {
+		_, err = os.Stdout.Write(kubeconfig)
+		return err
+	}
+	if err := os.WriteFile(manageArgs.file, kubeconfig, 0o600); err != nil {
+		return fmt.Errorf("error writing kubeconfig: %w", err)
+	}
+	log.Printf("Wrote the kubeconfig of cluster %s to %s", clusterID, manageArgs.file)
+	return nil
+}
+
+func runExtend() error {
+	if manageArgs.hours == 0 && manageArgs.minutes == 0 {
+		return fmt.Errorf("set --hours or --minutes to extend the expiry by")
+	}
+
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.ExtendExpiry(clusterID, manageArgs.hours, manageArgs.minutes, 0); err != nil {
+		return fmt.Errorf("error extending the expiry of cluster %s: %w", clusterID, err)
+	}
+	return nil
+}
+
+func runExpire() error {
+	if manageArgs.expireIn < 0 {
+		return fmt.Errorf("--in can't be negative")
+	}
+
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.Expire(clusterID, manageArgs.expireIn); err != nil {
+		return fmt.Errorf("error expiring cluster %s: %w", clusterID, err)
+	}
+	return nil
+}
+
+func runProperties(args []string) error {
+	properties, err := parseProperties(args)
+	if err != nil {
+		return err
+	}
+
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := provider.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+	}
+	for _, property := range properties {
+		if err := provider.AddProperty(cluster, property[0], property[1]); err != nil {
+			return fmt.Errorf("error setting property %s of cluster %s: %w", property[0], clusterID, err)
+		}
+	}
+
+	if len(properties) > 0 {
+		if cluster, err = provider.GetCluster(clusterID); err != nil {
+			return fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+		}
+	}
+	return clusterutil.WriteProperties(os.Stdout, manageArgs.output, cluster.Properties())
+}
+
+func runDelete() error {
+	provider, clusterID, err := setup(true)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := provider.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+	}
+	if !manageArgs.yes {
+		// Only OCM and ROSA mark the clusters they launch, so this is a warning rather than a refusal
+		if cluster.Properties()[clusterproperties.MadeByOSDe2e] != "true" {
+			return fmt.Errorf("cluster %s (%s) isn't marked as launched by osde2e, set --yes to delete it anyway", clusterID, cluster.Name())
+		}
+		return fmt.Errorf("set --yes to delete cluster %s (%s)", clusterID, cluster.Name())
+	}
+
+	if err := provider.DeleteCluster(clusterID); err != nil {
+		return fmt.Errorf("error deleting cluster %s: %w", clusterID, err)
+	}
+	log.Printf("Cluster %s is being deleted", clusterID)
+	return nil
+}
+
+// parseProperties splits name=value arguments into name and value pairs, in the given order.
+func parseProperties(args []string) ([][2]string, error) {
+	properties := make([][2]string, 0, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid property %q, expected name=value", arg)
+		}
+		properties = append(properties, [2]string{name, value})
+	}
+	return properties, nil
+}
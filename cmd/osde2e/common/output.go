@@ -0,0 +1,32 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// stdoutAnnotation marks the commands whose output is meant to be read from stdout.
+const stdoutAnnotation = "osde2e/stdout"
+
+// WritesToStdout marks the command as writing its output to stdout, e.g. JSON or a kubeconfig, so
+// logs are written to stderr instead and don't corrupt it.
+func WritesToStdout(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[stdoutAnnotation] = "true"
+}
+
+// LogsToStderr returns true if the command was marked with WritesToStdout.
+func LogsToStderr(cmd *cobra.Command) bool {
+	return cmd.Annotations[stdoutAnnotation] == "true"
+}
+
+// ExitCodeError makes osde2e exit with the code once the command returns. The command reports the
+// reason itself, so the error isn't logged again.
+type ExitCodeError int
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
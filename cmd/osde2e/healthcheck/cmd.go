@@ -1,10 +1,12 @@
 package healthcheck
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/openshift/osde2e/cmd/osde2e/common"
 	"github.com/openshift/osde2e/cmd/osde2e/helpers"
@@ -15,12 +17,25 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of the healthcheck, so scripts can tell why a cluster isn't healthy.
+const (
+	exitHealthy     = config.Success
+	exitUnhealthy   = 1
+	exitUnreachable = 2
+	exitConfigError = 3
+)
+
 var Cmd = &cobra.Command{
 	Use:   "healthcheck",
 	Short: "Runs a healthcheck.",
-	Long:  "Runs a healthcheck on a cluster using the provided arguments.",
-	Args:  cobra.OnlyValidArgs,
-	RunE:  run,
+	Long: `Runs the health checks that apply to a cluster using the provided arguments, once or
+continuously with --watch, and prints their status as a table or as JSON.
+
+Exits with 0 if the cluster is healthy, 1 if it's unhealthy, 2 if it's unreachable or its health
+is otherwise unknown and 3 if the checks or flags are misconfigured. In watch mode the exit code
+is that of the last run that completed.`,
+	Args: cobra.OnlyValidArgs,
+	RunE: run,
 }
 
 var args struct {
@@ -30,6 +45,9 @@ var args struct {
 	clusterID       string
 	environment     string
 	kubeConfig      string
+	watch           bool
+	interval        time.Duration
+	output          string
 }
 
 func init() {
@@ -76,25 +94,117 @@ func init() {
 		"Path to local Kube config for running tests against.",
 	)
 
+	pfs.BoolVarP(
+		&args.watch,
+		"watch",
+		"w",
+		false,
+		"Keep running the health checks every interval until interrupted.",
+	)
+	pfs.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"How long to wait between runs of the health checks in watch mode.",
+	)
+	pfs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		clusterutil.OutputTable,
+		"Output format, one of "+strings.Join(clusterutil.OutputFormats, ", "),
+	)
+	_ = Cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return clusterutil.OutputFormats, cobra.ShellCompDirectiveDefault
+	})
+
+	common.WritesToStdout(Cmd)
+
 	_ = viper.BindPFlag(config.Cluster.ID, Cmd.PersistentFlags().Lookup("cluster-id"))
 	_ = viper.BindPFlag(ocmprovider.Env, Cmd.PersistentFlags().Lookup("environment"))
 	_ = viper.BindPFlag(config.Kubeconfig.Path, Cmd.PersistentFlags().Lookup("kube-config"))
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if exitCode := healthcheck(cmd.Context()); exitCode != exitHealthy {
+		return common.ExitCodeError(exitCode)
+	}
+	return nil
+}
+
+// healthcheck runs the health checks once, or until the context is done in watch mode, and returns
+// the exit code of the last run.
+func healthcheck(ctx context.Context) int {
 	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
-		return fmt.Errorf("error loading initial state: %v", err)
+		log.Printf("error loading initial state: %v", err)
+		return exitConfigError
+	}
+	if err := clusterutil.ValidateHealthChecks(); err != nil {
+		log.Print(err)
+		return exitConfigError
+	}
+	if args.output != clusterutil.OutputTable && args.output != clusterutil.OutputJSON {
+		log.Printf("unknown output format %q, expected one of %s", args.output, strings.Join(clusterutil.OutputFormats, ", "))
+		return exitConfigError
+	}
+	if args.watch && args.interval <= 0 {
+		log.Printf("the interval must be positive")
+		return exitConfigError
 	}
-	logger := log.New(os.Stdout, "", log.LstdFlags)
-	isHealthy, failures, _ := clusterutil.PollClusterHealth(args.clusterID, logger)
-	if isHealthy {
-		log.Printf("Cluster %s is healthy!\n", args.clusterID)
-	} else {
-		log.Printf("Cluster %s is not healthy yet.\n", args.clusterID)
-		if len(failures) > 0 {
-			log.Printf("Currently failing %s health checks", strings.Join(failures, ", "))
+
+	// Logs already go to stderr, out of the way of the status
+	logger := log.New(log.Writer(), "", log.LstdFlags)
+
+	if !args.watch {
+		return checkOnce(ctx, logger)
+	}
+	// Until a run completes the health of the cluster is unknown
+	exitCode := exitUnreachable
+	for {
+		runExitCode := checkOnce(ctx, logger)
+		if ctx.Err() != nil {
+			// The checks of an interrupted run didn't finish, report the last run that did
+			return exitCode
+		}
+		exitCode = runExitCode
+		select {
+		case <-ctx.Done():
+			return exitCode
+		case <-time.After(args.interval):
 		}
-		return fmt.Errorf("healthcheck failed")
 	}
-	return nil
+}
+
+// checkOnce runs the health checks, prints their status and returns the matching exit code. The
+// status of a run interrupted in watch mode isn't printed.
+func checkOnce(ctx context.Context, logger *log.Logger) int {
+	results, err := clusterutil.CheckClusterHealth(ctx, args.clusterID, logger)
+	if args.watch && ctx.Err() != nil {
+		return exitUnreachable
+	}
+	status := clusterutil.NewHealthStatus(args.clusterID, results, err)
+
+	if args.watch && args.output == clusterutil.OutputTable && isTerminal(os.Stdout) {
+		// Redraw the table in place
+		os.Stdout.WriteString("\033[H\033[2J")
+	}
+	if err := clusterutil.WriteHealthStatus(os.Stdout, args.output, status); err != nil {
+		log.Printf("error writing health status: %v", err)
+	}
+
+	switch {
+	case errors.Is(err, clusterutil.ErrHealthCheckConfig):
+		return exitConfigError
+	case err != nil:
+		// The cluster couldn't be reached or looked up, so its health is unknown
+		return exitUnreachable
+	case !status.Healthy:
+		return exitUnhealthy
+	}
+	return exitHealthy
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/openshift/osde2e/cmd/osde2e/common"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/orchestrator"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
 	"github.com/openshift/osde2e/pkg/krknai"
 	"github.com/spf13/cobra"
@@ -118,35 +119,45 @@ func runKrknAI(ctx context.Context) int {
 		log.Printf("Failed to create KrknAI orchestrator: %v", err)
 		return config.Failure
 	}
+	orch = orchestrator.Timed(orch)
+	defer func() { orchestrator.WriteConfiguredMetricsFile(orch.Result()) }()
+
+	// Reporting and cleanup still run once the run has been canceled
+	finalizeCtx := context.WithoutCancel(ctx)
 
 	if err := orch.PreProcess(ctx); err != nil {
 		log.Printf("Pre-processing failed: %v", err)
-		return config.Failure
+		orch.Result().ExitCode = config.Failure
+		orch.Result().MarkAborted(ctx)
+		return orch.Result().ExitCode
 	}
 
 	if err := orch.Provision(ctx); err != nil {
 		log.Printf("Provision failed: %v", err)
-		return config.Failure
+		orch.Result().ExitCode = config.Failure
+		orch.Result().MarkAborted(ctx)
+		return orch.Result().ExitCode
 	}
 
 	testErr := orch.Execute(ctx)
 	log.Printf("krkn-ai execution failed: %v", testErr)
-	if viper.GetBool(config.LogAnalysis.EnableAnalysis) {
+	aborted := orch.Result().MarkAborted(ctx)
+	if viper.GetBool(config.LogAnalysis.EnableAnalysis) && !aborted {
 		if err := orch.AnalyzeLogs(ctx, testErr); err != nil {
 			log.Printf("Log analysis failed: %v", err)
 		}
 	}
 
 	// PostProcessCluster (must-gather) before Report so artifacts exist when report is generated
-	if err := orch.PostProcessCluster(ctx); err != nil {
+	if err := orch.PostProcessCluster(finalizeCtx); err != nil {
 		log.Printf("Post-processing errors: %v", err)
 	}
 
-	if err := orch.Report(ctx); err != nil {
+	if err := orch.Report(finalizeCtx); err != nil {
 		log.Printf("Report errors: %v", err)
 	}
 
-	if err := orch.Cleanup(ctx); err != nil {
+	if err := orch.Cleanup(finalizeCtx); err != nil {
 		log.Printf("Cleanup errors: %v", err)
 	}
 
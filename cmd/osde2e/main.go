@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -15,13 +18,19 @@ import (
 
 	"github.com/openshift/osde2e/cmd/osde2e/arguments"
 	"github.com/openshift/osde2e/cmd/osde2e/cleanup"
+	"github.com/openshift/osde2e/cmd/osde2e/cluster"
+	"github.com/openshift/osde2e/cmd/osde2e/common"
 	"github.com/openshift/osde2e/cmd/osde2e/completion"
 	"github.com/openshift/osde2e/cmd/osde2e/healthcheck"
 	"github.com/openshift/osde2e/cmd/osde2e/krknai"
+	"github.com/openshift/osde2e/cmd/osde2e/pool"
 	"github.com/openshift/osde2e/cmd/osde2e/provision"
 	"github.com/openshift/osde2e/cmd/osde2e/test"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/aroprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/hypershiftprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/localprovider"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
 	"github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
 	"github.com/openshift/osde2e/pkg/common/spi"
@@ -46,6 +55,8 @@ func init() {
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(cleanup.Cmd)
 	root.AddCommand(krknai.Cmd)
+	root.AddCommand(pool.Cmd)
+	root.AddCommand(cluster.Cmd)
 }
 
 func main() {
@@ -78,8 +89,6 @@ func main() {
 	}
 	defer logFile.Close()
 
-	mw := io.MultiWriter(os.Stdout, logFile)
-
 	// Tee stderr into the log file so that panics and subprocess
 	// error output are captured in the uploaded artifact.
 	var stderrW *os.File
@@ -96,9 +105,24 @@ func main() {
 		close(stderrDone)
 	}
 
+	// Keep stdout for the output of commands that print JSON or a kubeconfig
+	logOutput := io.Writer(os.Stdout)
+	if cmd, _, err := root.Find(os.Args[1:]); err == nil && common.LogsToStderr(cmd) {
+		logOutput = origStderr
+	}
+	mw := io.MultiWriter(logOutput, logFile)
+
 	cfg := textlogger.NewConfig(textlogger.Output(mw))
 	logger := textlogger.NewLogger(cfg)
 	ctx := logr.NewContext(context.Background(), logger)
+	// Cancel the run on Ctrl-C or when CI terminates the job, so in-flight provider calls and waits
+	// return and the run can still report and clean up before exiting.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		// Restore the default handling after the first signal, so a second one kills a hung cleanup
+		<-ctx.Done()
+		stop()
+	}()
 	root.SetContext(ctx)
 
 	log.SetOutput(mw)
@@ -108,12 +132,21 @@ func main() {
 	// Register providers
 	spi.RegisterProvider("rosa", func() (spi.Provider, error) { return rosaprovider.New(ctx) })
 	spi.RegisterProvider("ocm", func() (spi.Provider, error) { return ocmprovider.New() })
+	spi.RegisterLifecycleProvider("aro", func() (spi.Lifecycle, error) { return aroprovider.New() })
+	spi.RegisterLifecycleProvider("hypershift", func() (spi.Lifecycle, error) { return hypershiftprovider.New() })
+	spi.RegisterLifecycleProvider("local", func() (spi.Lifecycle, error) { return localprovider.New() })
 
 	exitCode := 0
 	if err := root.Execute(); err != nil {
-		logger.Error(err, "command execution failed")
-		exitCode = 1
+		var exitErr common.ExitCodeError
+		if errors.As(err, &exitErr) {
+			exitCode = int(exitErr)
+		} else {
+			logger.Error(err, "command execution failed")
+			exitCode = 1
+		}
 	}
+	stop()
 
 	// Flush stderr pipe before exit so all output reaches the log file.
 	if stderrW != nil {
@@ -0,0 +1,260 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/openshift/osde2e/cmd/osde2e/common"
+	"github.com/openshift/osde2e/cmd/osde2e/helpers"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/pool"
+	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Maintains pools of reserved clusters.",
+	Long: "Maintains pools of ready clusters that test jobs claim with USE_CLUSTER_RESERVE instead of provisioning their own.\n" +
+		"Pools are given as version/cloud/product[/region]=size, e.g. --pool 4.16/aws/rosa/us-east-1=3.",
+	Args: cobra.OnlyValidArgs,
+}
+
+var maintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Fills the pools and removes used, failed and expired clusters.",
+	Long: "Deletes used, failed and expired pool clusters, or recycles used ones with --recycle-used, " +
+		"launches clusters until every pool has its size in ready or installing clusters and prints the pool status.",
+	Args: cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runMaintain(cmd.Context())
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Prints the number of clusters in every pool.",
+	Args:  cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runStatus()
+	},
+}
+
+var args struct {
+	configString    string
+	customConfig    string
+	secretLocations string
+	environment     string
+	pools           []string
+	maxAge          time.Duration
+	recycleUsed     bool
+	dryRun          bool
+}
+
+func init() {
+	pfs := Cmd.PersistentFlags()
+	pfs.StringVar(
+		&args.configString,
+		"configs",
+		"",
+		"A comma separated list of built in configs to use",
+	)
+	_ = Cmd.RegisterFlagCompletionFunc("configs", helpers.ConfigComplete)
+	pfs.StringVar(
+		&args.customConfig,
+		"custom-config",
+		"",
+		"Custom config file for osde2e",
+	)
+	pfs.StringVar(
+		&args.secretLocations,
+		"secret-locations",
+		"",
+		"A comma separated list of possible secret directory locations for loading secret configs.",
+	)
+	pfs.StringVarP(
+		&args.environment,
+		"environment",
+		"e",
+		"",
+		"Cluster provider environment to use.",
+	)
+	pfs.StringArrayVar(
+		&args.pools,
+		"pool",
+		nil,
+		"A pool to maintain as version/cloud/product[/region]=size. Can be repeated.",
+	)
+	pfs.DurationVar(
+		&args.maxAge,
+		"max-age",
+		24*time.Hour,
+		"Delete pool clusters older than this duration, unless a test job holds a claim on them. 0 disables the limit.",
+	)
+	maintainCmd.Flags().BoolVar(
+		&args.recycleUsed,
+		"recycle-used",
+		false,
+		"Return ready used clusters to the pool instead of deleting them",
+	)
+	maintainCmd.Flags().BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Only log the clusters that would be launched, recycled or deleted",
+	)
+	_ = viper.BindPFlag(ocmprovider.Env, Cmd.PersistentFlags().Lookup("environment"))
+
+	Cmd.AddCommand(maintainCmd)
+	Cmd.AddCommand(statusCmd)
+}
+
+// newManager loads the config and creates a pool manager for the configured provider.
+func newManager() (*pool.Manager, error) {
+	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
+		return nil, fmt.Errorf("error loading initial state: %v", err)
+	}
+	if len(args.pools) == 0 {
+		return nil, fmt.Errorf("at least one --pool is required")
+	}
+
+	provider, err := providers.ClusterProvider()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster provider: %w", err)
+	}
+
+	specs := make([]pool.Spec, 0, len(args.pools))
+	for _, s := range args.pools {
+		spec, err := pool.ParseSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		if product := productOf(provider); spec.Product != product {
+			return nil, fmt.Errorf("pool %s can't be filled by the %s provider, which launches %s clusters", spec.Key, provider.Type(), product)
+		}
+		specs = append(specs, spec)
+	}
+
+	return pool.NewManager(provider, launcher(provider), specs, pool.Options{
+		MaxAge:      args.maxAge,
+		RecycleUsed: args.recycleUsed,
+		DryRun:      args.dryRun,
+	}), nil
+}
+
+func runMaintain(ctx context.Context) error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	actions, err := manager.Maintain(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failures []error
+	for _, action := range actions {
+		if action.Err != nil {
+			failures = append(failures, fmt.Errorf("%s %s in pool %s: %w", action.Type, action.ClusterID, action.Pool, action.Err))
+		}
+	}
+
+	statuses, err := manager.Status()
+	if err != nil {
+		return err
+	}
+	if err := pool.WriteStatus(os.Stdout, statuses); err != nil {
+		return err
+	}
+	return errors.Join(failures...)
+}
+
+func runStatus() error {
+	manager, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := manager.Status()
+	if err != nil {
+		return err
+	}
+	return pool.WriteStatus(os.Stdout, statuses)
+}
+
+// productOf returns the product of the clusters the provider launches.
+func productOf(provider spi.Provider) string {
+	if provider.Type() == "ocm" {
+		return "osd"
+	}
+	return provider.Type()
+}
+
+// launcher launches reserved clusters of a pool with the provider, configured for the pool's
+// version, cloud provider and region.
+func launcher(provider spi.Provider) pool.Launcher {
+	return func(ctx context.Context, key pool.Key) (string, error) {
+		versions, err := provider.Versions()
+		if err != nil {
+			return "", fmt.Errorf("error getting versions: %w", err)
+		}
+		version, err := pool.ResolveVersion(versions, key.Version)
+		if err != nil {
+			return "", err
+		}
+
+		viper.Set(config.Cluster.Version, version)
+		viper.Set(config.CloudProvider.CloudProviderID, key.CloudProvider)
+		if key.Region != "" {
+			viper.Set(config.CloudProvider.Region, key.Region)
+		}
+		viper.Set(config.Cluster.Reserve, true)
+		viper.Set(config.Cluster.UseClusterReserve, false)
+
+		name, err := clusterName(provider)
+		if err != nil {
+			return "", err
+		}
+		clusterID, err := provider.LaunchCluster(name)
+		if err != nil {
+			return "", fmt.Errorf("could not launch cluster: %w", err)
+		}
+		log.Printf("Launched cluster %s (%s) for pool %s", name, clusterID, key)
+
+		// OCM and ROSA reserve clusters when launching them, the other providers are reserved here
+		if _, ok := spi.As[spi.PropertyStore](provider); ok {
+			if err := pool.SetProperties(provider, clusterID, map[string]string{
+				clusterproperties.MadeByOSDe2e: "true",
+				clusterproperties.Availability: clusterproperties.Reserved,
+			}); err != nil {
+				return clusterID, fmt.Errorf("error reserving cluster: %w", err)
+			}
+		}
+		return clusterID, nil
+	}
+}
+
+// clusterName returns a random cluster name that isn't taken yet.
+func clusterName(provider spi.Provider) (string, error) {
+	const attemptLimit = 10
+	for attempt := 1; attempt <= attemptLimit; attempt++ {
+		name := "osde2e-" + util.RandomStr(5)
+		valid, err := provider.IsValidClusterName(name)
+		if err != nil {
+			log.Printf("an error occurred validating the cluster name %v", err)
+		} else if valid {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a free cluster name after %d attempts", attemptLimit)
+}
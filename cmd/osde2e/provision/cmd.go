@@ -91,7 +91,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("error getting cluster provider: %s", err.Error())
 	}
 
-	_, err = clusterutil.Provision(provider)
+	_, err = clusterutil.Provision(cmd.Context(), provider)
 	if err != nil && !errors.Is(err, clusterutil.ErrReserveFull) {
 		return err
 	}
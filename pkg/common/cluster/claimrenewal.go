@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+)
+
+// claimRenewal renews the lease of the claim this run holds on a cluster from the reserve, so the
+// pool manager doesn't take the cluster back from a run that outlasts the lease.
+var claimRenewal struct {
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startClaimRenewal renews the lease of the claim of the token on the cluster every third of the
+// lease until stopClaimRenewal is called or the claim is lost.
+func startClaimRenewal(ocmProvider *ocmprovider.OCMProvider, clusterID, token string) {
+	lease := time.Duration(viper.GetInt(config.Cluster.ClaimLeaseInMinutes)) * time.Minute
+	if lease <= 0 {
+		return
+	}
+	stopClaimRenewal()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	claimRenewal.mutex.Lock()
+	claimRenewal.cancel, claimRenewal.done = cancel, done
+	claimRenewal.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := ocmProvider.RenewClaim(clusterID, token, lease); err != nil {
+				log.Printf("Error renewing the claim on cluster %s: %v", clusterID, err)
+				if errors.Is(err, ocmprovider.ErrClaimLost) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// stopClaimRenewal stops renewing the lease and waits for a renewal in flight, so that it can't
+// write the claim back after it is released.
+func stopClaimRenewal() {
+	claimRenewal.mutex.Lock()
+	cancel, done := claimRenewal.cancel, claimRenewal.done
+	claimRenewal.cancel, claimRenewal.done = nil, nil
+	claimRenewal.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
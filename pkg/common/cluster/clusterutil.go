@@ -22,16 +22,17 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/logging"
+	"github.com/openshift/osde2e/pkg/common/phase"
 	"github.com/openshift/osde2e/pkg/common/providers"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
 	"github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
 	"github.com/openshift/osde2e/pkg/common/spi"
 	"github.com/openshift/osde2e/pkg/common/util"
 	"github.com/openshift/osde2e/pkg/common/versions"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -51,8 +52,18 @@ var podErrorTracker healthchecks.PodErrorTracker
 // ErrReserveFull is returned for early exit from provisioner
 var ErrReserveFull = errors.New("reserve full")
 
+// ErrHealthCheckConfig is returned when the health checks can't be set up, e.g. without a
+// kubeconfig for the cluster or when an unknown check is included.
+var ErrHealthCheckConfig = errors.New("health check configuration error")
+
+// ErrClusterUnreachable is returned when the API server of the cluster can't be reached to check it.
+var ErrClusterUnreachable = errors.New("cluster unreachable")
+
+// provisioningPollInterval is how often WaitForOCMProvisioning checks the cluster state.
+var provisioningPollInterval = 30 * time.Second
+
 // GetClusterVersion will get the current cluster version for the cluster.
-func GetClusterVersion(provider spi.Provider, clusterID string) (*semver.Version, error) {
+func GetClusterVersion(ctx context.Context, provider spi.Provider, clusterID string) (*semver.Version, error) {
 	restConfig, err := getRestConfig(provider, clusterID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting rest config: %v", err)
@@ -63,7 +74,7 @@ func GetClusterVersion(provider spi.Provider, clusterID string) (*semver.Version
 		return nil, fmt.Errorf("error generating OpenShift Clientset: %v", err)
 	}
 
-	cvo, err := healthchecks.GetClusterVersionObject(oscfg.ConfigV1())
+	cvo, err := healthchecks.GetClusterVersionObject(ctx, oscfg.ConfigV1())
 	if err != nil {
 		return nil, fmt.Errorf("error getting cluster version object: %v", err)
 	}
@@ -78,7 +89,8 @@ func GetClusterVersion(provider spi.Provider, clusterID string) (*semver.Version
 
 // WaitForClusterReadyPostInstall blocks until the cluster is ready for testing using mechanisms appropriate
 // for a newly-installed cluster.
-func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error {
+func WaitForClusterReadyPostInstall(ctx context.Context, clusterID string, logger *log.Logger) (err error) {
+	defer recordHealthCheck(phase.InstallPhase, time.Now(), &err)
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
 	provider, err := providers.ClusterProvider()
@@ -86,7 +98,7 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 		return fmt.Errorf("error getting cluster provisioning client: %v", err)
 	}
 
-	cluster, err := provider.GetCluster(clusterID)
+	cluster, err := spi.WithContext(provider).GetCluster(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("failed getting cluster from provider: %w", err)
 	}
@@ -101,9 +113,9 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 		return fmt.Errorf("error generating Kube Clientset: %w", err)
 	}
 
-	if viper.GetBool(config.Hypershift) {
+	if isHCP(provider.Type()) {
 		logger.Println("Waiting for nodes to be ready (up to 10 minutes)")
-		err := wait.PollUntilContextTimeout(context.TODO(), 30*time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
+		err := wait.PollUntilContextTimeout(ctx, 30*time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
 			nodes, err := kubeClient.CoreV1().Nodes().List(ctx, v1.ListOptions{})
 			if err != nil {
 				// if the api server is not ready yet
@@ -125,13 +137,14 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 		if err != nil {
 			return fmt.Errorf("HyperShift healthcheck: %w", err)
 		}
-		return nil
+		// The osd-cluster-ready job doesn't run on hosted clusters, they get their own health profile
+		return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, false, false)
 	}
 
 	if viper.GetBool(config.Tests.OnlyHealthCheckNodes) {
 		logger.Println("Waiting up to 30 minutes for all nodes to be ready")
-		err := wait.PollUntilContextTimeout(context.TODO(), 30*time.Second, 30*time.Minute, true, func(_ context.Context) (bool, error) {
-			return healthchecks.CheckNodeHealth(kubeClient.CoreV1(), logger)
+		err := wait.PollUntilContextTimeout(ctx, 30*time.Second, 30*time.Minute, true, func(ctx context.Context) (bool, error) {
+			return healthchecks.CheckNodeHealth(ctx, kubeClient.CoreV1(), logger)
 		})
 		if err != nil {
 			return fmt.Errorf("node health check failed: %w", err)
@@ -139,12 +152,18 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 		return nil
 	}
 
-	err = healthchecks.CheckHealthcheckJob(context.Background(), clusterConfig, logger)
+	if !runsHealthcheckJob(provider.Type()) {
+		// The osd-cluster-ready job only runs on OSD and ROSA clusters, the others are checked with
+		// the health checks that apply to them
+		return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, false, false)
+	}
+
+	err = healthchecks.CheckHealthcheckJob(ctx, clusterConfig, logger)
 	if err != nil {
 		return fmt.Errorf("cluster failed health check: %w", err)
 	}
 
-	if err := provider.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusHealthy); err != nil {
+	if err := spi.WithContext(provider).AddProperty(ctx, cluster, clusterproperties.Status, clusterproperties.StatusHealthy); err != nil {
 		return fmt.Errorf("error trying to add healthy property to cluster ID %s: %w", cluster.ID(), err)
 	}
 	return nil
@@ -152,14 +171,31 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 
 // WaitForClusterReadyPostUpgrade blocks until the cluster is ready for testing using healthcheck mechanisms appropriate
 // for after a cluster version upgrade.
-func WaitForClusterReadyPostUpgrade(clusterID string, logger *log.Logger) error {
+func WaitForClusterReadyPostUpgrade(ctx context.Context, clusterID string, logger *log.Logger) (err error) {
+	defer recordHealthCheck(phase.UpgradePhase, time.Now(), &err)
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
-	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID, logger, true, false)
+	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, true, false)
+}
+
+// recordHealthCheck records the outcome of waiting for the cluster to become healthy for the run manifest
+func recordHealthCheck(testPhase string, startedAt time.Time, err *error) {
+	check := runmanifest.HealthCheck{
+		Phase:           testPhase,
+		StartedAt:       startedAt,
+		DurationSeconds: time.Since(startedAt).Seconds(),
+		Passed:          *err == nil,
+	}
+	if *err != nil {
+		check.Error = (*err).Error()
+	}
+	runmanifest.RecordHealthCheck(check)
 }
 
-func WaitForOCMProvisioning(provider spi.Provider, clusterID string, logger *log.Logger, isUpgrade bool) (becameReadyAt time.Time, err error) {
+func WaitForOCMProvisioning(ctx context.Context, provider spi.Provider, clusterID string, logger *log.Logger, isUpgrade bool) (becameReadyAt time.Time, err error) {
+	contextProvider := spi.WithContext(provider)
+
 	installTimeout := viper.GetInt64(config.Cluster.InstallTimeout)
-	if viper.GetBool(config.Hypershift) {
+	if isHCP(provider.Type()) {
 		// Install timeout 30 minutes for hypershift
 		installTimeout = 30
 	}
@@ -172,22 +208,29 @@ func WaitForOCMProvisioning(provider spi.Provider, clusterID string, logger *log
 	var readinessStarted time.Time
 
 	healthcheckStatus := clusterproperties.StatusHealthCheck
+	watchName := "provisioning"
 	if isUpgrade {
 		healthcheckStatus = clusterproperties.StatusUpgradeHealthCheck
+		watchName = "upgrade-provisioning"
 	}
 
-	return readinessStarted, wait.PollUntilContextTimeout(context.TODO(), 30*time.Second, time.Duration(installTimeout)*time.Minute, true, func(_ context.Context) (bool, error) {
-		cluster, err := provider.GetCluster(clusterID)
+	watcher := watchProvisioning(ctx, provider, clusterID, watchName, logger)
+	defer func() { watcher.stop(err) }()
+
+	return readinessStarted, wait.PollUntilContextTimeout(ctx, provisioningPollInterval, time.Duration(installTimeout)*time.Minute, true, func(ctx context.Context) (bool, error) {
+		cluster, err := contextProvider.GetCluster(ctx, clusterID)
 		if err != nil {
 			logger.Printf("Error fetching cluster details from provider: %s", err)
 			return false, nil
 		}
 
+		watcher.observe(cluster)
+
 		properties := cluster.Properties()
 		currentStatus := properties[clusterproperties.Status]
 
 		if currentStatus == clusterproperties.StatusProvisioning && !readinessSet {
-			err = provider.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusWaitingForReady)
+			err = contextProvider.AddProperty(ctx, cluster, clusterproperties.Status, clusterproperties.StatusWaitingForReady)
 			if err != nil {
 				logger.Printf("Error adding property to cluster: %s", err.Error())
 				return false, nil
@@ -195,7 +238,7 @@ func WaitForOCMProvisioning(provider spi.Provider, clusterID string, logger *log
 		}
 
 		if cluster.State() == spi.ClusterStateReady {
-			if err := provider.AddProperty(cluster, clusterproperties.Status, healthcheckStatus); err != nil {
+			if err := contextProvider.AddProperty(ctx, cluster, clusterproperties.Status, healthcheckStatus); err != nil {
 				logger.Printf("error trying to add health-check property to cluster ID %s: %v", cluster.ID(), err)
 				return false, nil
 			}
@@ -203,14 +246,19 @@ func WaitForOCMProvisioning(provider spi.Provider, clusterID string, logger *log
 			readinessStarted = time.Now()
 			return true, nil
 		} else if cluster.State() == spi.ClusterStateError {
-			logger.Print("cluster is in error state, check cloud provider for more details")
+			reason := cluster.ProvisionError()
+			if reason == "" {
+				reason = "check cloud provider for more details"
+			}
+			logger.Printf("cluster is in error state: %s", reason)
+			return false, fmt.Errorf("%w: %s", ErrClusterInstallFailed, reason)
 		}
 		logger.Printf("cluster is not ready, state is: %v", cluster.State())
 		return false, nil
 	})
 }
 
-func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, logger *log.Logger, isUpgrade, overrideSkipCheck bool) error {
+func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx context.Context, clusterID string, logger *log.Logger, isUpgrade, overrideSkipCheck bool) error {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	if viper.GetBool(config.Tests.SkipClusterHealthChecks) && !overrideSkipCheck {
 		logger.Println("Skipping health checks...")
@@ -234,17 +282,27 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 	cleanRuns := 0
 	errRuns := 0
 
-	_, err = WaitForOCMProvisioning(provider, clusterID, logger, isUpgrade)
+	_, err = WaitForOCMProvisioning(ctx, provider, clusterID, logger, isUpgrade)
 	if err != nil {
 		return fmt.Errorf("OCM never became ready: %w", err)
 	}
 
-	cluster, err := provider.GetCluster(clusterID)
+	contextProvider := spi.WithContext(provider)
+	cluster, err := contextProvider.GetCluster(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("error fetching cluster details from provider: %w", err)
 	}
 
-	if pollErr := wait.PollUntilContextTimeout(context.TODO(), 30*time.Second, time.Duration(installTimeout)*time.Minute, true, func(_ context.Context) (bool, error) {
+	healthReport := newHealthRecorder(clusterID, isUpgrade)
+	healthy := false
+	defer func() {
+		healthReport.finish(healthy)
+		if err := healthReport.write(); err != nil {
+			logger.Printf("Error writing the health report: %v", err)
+		}
+	}()
+
+	if pollErr := wait.PollUntilContextTimeout(ctx, 30*time.Second, time.Duration(installTimeout)*time.Minute, true, func(ctx context.Context) (bool, error) {
 		if cluster.State() != spi.ClusterStateReady {
 			logger.Printf("Cluster is not ready, current status '%s'.", cluster.State())
 			return false, nil
@@ -253,7 +311,18 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 		properties := cluster.Properties()
 		currentStatus := properties[clusterproperties.Status]
 
-		if success, failures, err := PollClusterHealth(clusterID, logger); success {
+		results, err := CheckClusterHealth(ctx, clusterID, logger)
+		healthReport.record(results, err)
+		if errors.Is(err, ErrHealthCheckConfig) {
+			// Checking again won't fix the configuration
+			return false, err
+		}
+		var success bool
+		var failures []string
+		if err == nil {
+			success, failures, err = summarizeHealth(results)
+		}
+		if success {
 			cleanRuns++
 			logger.Printf("Clean run %d/%d...", cleanRuns, cleanRunsNeeded)
 			errRuns = 0
@@ -264,9 +333,9 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 		} else {
 			if err != nil {
 				errRuns++
-				logger.Printf("Error in PollClusterHealth: %v", err)
+				logger.Printf("Error checking cluster health: %v", err)
 				if errRuns >= errorWindow {
-					if err := provider.AddProperty(cluster, clusterproperties.Status, unhealthyStatus); err != nil {
+					if err := contextProvider.AddProperty(ctx, cluster, clusterproperties.Status, unhealthyStatus); err != nil {
 						log.Printf("error trying to add unhealthy property to cluster ID %s: %v", clusterID, err)
 					}
 					return false, nil
@@ -276,7 +345,7 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 
 			failureString := strings.Join(failures, ",")
 			if currentStatus != failureString {
-				err = provider.AddProperty(cluster, clusterproperties.Status, failureString)
+				err = contextProvider.AddProperty(ctx, cluster, clusterproperties.Status, failureString)
 				if err != nil {
 					log.Printf("error trying to add property to cluster ID %s: %v", clusterID, err)
 				}
@@ -285,10 +354,11 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 			return false, nil
 		}
 	}); pollErr != nil {
-		return fmt.Errorf("failed polling for cluster health: %w", err)
+		return fmt.Errorf("failed polling for cluster health: %w", pollErr)
 	}
+	healthy = true
 
-	if err := provider.AddProperty(cluster, clusterproperties.Status, healthyStatus); err != nil {
+	if err := contextProvider.AddProperty(ctx, cluster, clusterproperties.Status, healthyStatus); err != nil {
 		return fmt.Errorf("error trying to add healthy property to cluster ID %s: %w", cluster.ID(), err)
 	}
 	return nil
@@ -324,10 +394,26 @@ func ClusterConfig(clusterID string) (restConfig *rest.Config, providerType stri
 	return
 }
 
-// PollClusterHealth looks at CVO data to determine if a cluster is alive/healthy or not
+// PollClusterHealth runs the registered health checks that apply to the cluster, limited by the
+// include and exclude lists, to determine if a cluster is alive/healthy or not
+// param clusterID: If specified, Provider will be discovered through OCM. If the empty string,
+// assume we are running in a cluster and use in-cluster REST config instead.
+func PollClusterHealth(ctx context.Context, clusterID string, logger *log.Logger) (status bool, failures []string, err error) {
+	results, err := CheckClusterHealth(ctx, clusterID, logger)
+	if err != nil {
+		return false, nil, err
+	}
+	status, failures, err = summarizeHealth(results)
+	return status, failures, err
+}
+
+// CheckClusterHealth runs the registered health checks that apply to the cluster, limited by the
+// include and exclude lists, and returns their results. It fails with ErrHealthCheckConfig if the
+// checks are misconfigured and with ErrClusterUnreachable or another error if they can't be run
+// right now.
 // param clusterID: If specified, Provider will be discovered through OCM. If the empty string,
 // assume we are running in a cluster and use in-cluster REST config instead.
-func PollClusterHealth(clusterID string, logger *log.Logger) (status bool, failures []string, err error) {
+func CheckClusterHealth(ctx context.Context, clusterID string, logger *log.Logger) ([]healthchecks.Result, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	logger.Print("Polling Cluster Health...\n")
@@ -335,81 +421,72 @@ func PollClusterHealth(clusterID string, logger *log.Logger) (status bool, failu
 	restConfig, providerType, err := ClusterConfig(clusterID)
 	if err != nil {
 		logger.Printf("Error getting cluster config: %v\n", err)
-		return false, nil, nil
+		return nil, fmt.Errorf("error getting cluster config: %w", err)
 	}
 
-	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	// Hosted control plane clusters get their own health profile
+	target, err := healthchecks.NewTarget(restConfig, providerType, viper.GetString(config.CloudProvider.CloudProviderID), isHCP(providerType))
 	if err != nil {
-		logger.Printf("Error generating Kube Clientset: %v\n", err)
-		return false, nil, nil
+		logger.Printf("Error creating health check clients: %v\n", err)
+		return nil, fmt.Errorf("%w: %v", ErrHealthCheckConfig, err)
 	}
+	target.Environment = viper.GetString(ocmprovider.Env)
 
-	oscfg, err := osconfig.NewForConfig(restConfig)
+	include, exclude := healthCheckLists()
+	checks, err := healthchecks.Select(target, include, exclude)
 	if err != nil {
-		logger.Printf("Error generating OpenShift Clientset: %v\n", err)
-		return false, nil, nil
+		return nil, fmt.Errorf("%w: %v", ErrHealthCheckConfig, err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		logger.Printf("Error generating Dynamic Clientset: %v\n", err)
-		return false, nil, nil
+	// Tell an unreachable cluster apart from one whose checks fail
+	if _, err := target.Kube.Discovery().ServerVersion(); err != nil {
+		logger.Printf("Error reaching the cluster: %v\n", err)
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnreachable, err)
+	}
+	if len(checks) == 0 {
+		logger.Printf("No health checks apply to %q clusters", providerType)
 	}
 
-	clusterHealthy := true
-
-	var healthErr *multierror.Error
-	switch providerType {
-	case "rosa":
-		fallthrough
-	case "ocm":
-		if check, err := healthchecks.CheckCVOReadiness(oscfg.ConfigV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "cvo")
-			clusterHealthy = false
-		}
-
-		if check, err := healthchecks.CheckNodeHealth(kubeClient.CoreV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "node")
-			clusterHealthy = false
-		}
+	return healthchecks.Run(ctx, checks, target, logger), nil
+}
 
-		if check, err := healthchecks.CheckMachinesObjectState(dynamicClient, logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "machine")
-			clusterHealthy = false
-		}
+// ValidateHealthChecks fails with ErrHealthCheckConfig if HEALTH_CHECKS_INCLUDE or
+// HEALTH_CHECKS_EXCLUDE names a check that isn't registered, so that a run can fail before waiting
+// on the cluster.
+func ValidateHealthChecks() error {
+	if err := healthchecks.Validate(healthCheckLists()); err != nil {
+		return fmt.Errorf("%w: %v", ErrHealthCheckConfig, err)
+	}
+	return nil
+}
 
-		if check, err := healthchecks.CheckOperatorReadiness(oscfg.ConfigV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "operator")
-			clusterHealthy = false
-		}
+// healthCheckLists returns the configured include and exclude lists of health checks.
+func healthCheckLists() (include, exclude []string) {
+	return healthchecks.ParseList(viper.GetString(config.Tests.HealthChecksInclude)),
+		healthchecks.ParseList(viper.GetString(config.Tests.HealthChecksExclude))
+}
 
-		if check, err := healthchecks.CheckCerts(kubeClient.CoreV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "cert")
-			clusterHealthy = false
-		}
+// isHCP returns whether clusters of the provider type have a hosted control plane, either ROSA
+// clusters created with HYPERSHIFT or those of the HyperShift provider.
+func isHCP(providerType string) bool {
+	return viper.GetBool(config.Hypershift) || providerType == "hypershift"
+}
 
-		if check, err := healthchecks.CheckReplicaCountForDaemonSets(kubeClient.AppsV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "daemonset")
-			clusterHealthy = false
-		}
+// runsHealthcheckJob returns whether clusters of the provider type run the osd-cluster-ready job.
+func runsHealthcheckJob(providerType string) bool {
+	return providerType == "ocm" || providerType == "rosa"
+}
 
-		if check, err := healthchecks.CheckReplicaCountForReplicaSets(kubeClient.AppsV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "replicaset")
-			clusterHealthy = false
+// summarizeHealth returns whether all checks passed, the names of the ones that didn't and their errors.
+func summarizeHealth(results []healthchecks.Result) (healthy bool, failures []string, err error) {
+	var healthErr *multierror.Error
+	for _, result := range results {
+		if !result.Passed {
+			healthErr = multierror.Append(healthErr, result.Err)
+			failures = append(failures, result.Name)
 		}
-
-	default:
-		logger.Printf("No provisioner-specific logic for %q", providerType)
 	}
-
-	return clusterHealthy, failures, healthErr.ErrorOrNil()
+	return len(failures) == 0, failures, healthErr.ErrorOrNil()
 }
 
 func getRestConfig(provider spi.Provider, clusterID string) (*rest.Config, error) {
@@ -452,13 +529,22 @@ func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
 	var cluster *spi.Cluster
 	// get cluster ID from env
 	clusterID := viper.GetString(config.Cluster.ID)
-	ocmProvider, err := ocmprovider.NewWithEnv(viper.GetString(ocmprovider.Env))
-	if err != nil {
-		return nil, fmt.Errorf("could not setup ocm provider: %v", err)
-	}
 	// Only enable cluster reserve claiming for ROSA STS classic for now
-	if viper.GetBool(config.Cluster.UseClusterReserve) && viper.GetString(config.Provider) == "rosa" && !viper.GetBool(config.Hypershift) && viper.GetBool(rosaprovider.STS) {
+	claimFromReserve := viper.GetBool(config.Cluster.UseClusterReserve) && viper.GetString(config.Provider) == "rosa" && !viper.GetBool(config.Hypershift) && viper.GetBool(rosaprovider.STS)
+
+	// The cluster reserve lives in OCM, so only connect to it when the reserve is used.
+	var ocmProvider *ocmprovider.OCMProvider
+	if claimFromReserve || viper.GetBool(config.Cluster.Reserve) {
+		ocmProvider, err = ocmprovider.NewWithEnv(viper.GetString(ocmprovider.Env))
+		if err != nil {
+			return nil, fmt.Errorf("could not setup ocm provider: %v", err)
+		}
+	}
+	if claimFromReserve {
 		clusterID = ocmProvider.ClaimClusterFromReserve(viper.GetString(config.Cluster.Version), "aws", "rosa")
+		if clusterID != "" {
+			startClaimRenewal(ocmProvider, clusterID, viper.GetString(config.Cluster.ClaimToken))
+		}
 	}
 	if viper.GetBool(config.Cluster.Reserve) {
 		logger.Printf("Cluster reserve provisioning requested, querying reserve")
@@ -503,7 +589,7 @@ func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
 			viper.Set(config.Cluster.ID, clusterID)
 		}
 		if err != nil {
-			return nil, fmt.Errorf("could not launch cluster: %v", err)
+			return nil, fmt.Errorf("could not launch cluster: %w", err)
 		}
 
 		if cluster, err = provider.GetCluster(clusterID); err != nil {
@@ -561,24 +647,14 @@ func clusterName() string {
 	return "osde2e-" + suffix
 }
 
-func Provision(provider spi.Provider) (*spi.Cluster, error) {
-	status, err := ConfigureVersion(provider)
+func Provision(ctx context.Context, provider spi.Provider) (*spi.Cluster, error) {
+	status, err := ConfigureVersion(ctx, provider)
 	if status != config.Success {
 		return nil, fmt.Errorf("failed configure cluster version: %v", err)
 	}
-	cluster, err := ProvisionCluster(nil)
-	if err != nil {
-		if errors.Is(err, ErrReserveFull) {
-			log.Printf("Reserve full, exiting without provisioning")
-		}
-		return nil, fmt.Errorf("failed to set up or retrieve cluster: %w", err)
-	}
-
-	viper.Set(config.Cluster.ID, cluster.ID())
-	log.Printf("CLUSTER_ID set to %s from OCM.", viper.GetString(config.Cluster.ID))
-	_, err = WaitForOCMProvisioning(provider, viper.GetString(config.Cluster.ID), nil, false)
+	cluster, err := provisionWithRetries(ctx, provider)
 	if err != nil {
-		return nil, fmt.Errorf("cluster never became ready: %v", err)
+		return nil, err
 	}
 	log.Printf("Cluster status is ready")
 
@@ -602,7 +678,7 @@ func Provision(provider spi.Provider) (*spi.Cluster, error) {
 
 	if !viper.GetBool(config.Tests.SkipClusterHealthChecks) {
 		// If this is a new cluster, we should check the OSD Ready job unless skipped
-		err = WaitForClusterReadyPostInstall(cluster.ID(), nil)
+		err = WaitForClusterReadyPostInstall(ctx, cluster.ID(), nil)
 		if err != nil {
 			log.Println("*******************")
 			log.Printf("Cluster failed health check: %v", err)
@@ -615,8 +691,8 @@ func Provision(provider spi.Provider) (*spi.Cluster, error) {
 	}
 
 	var kubeconfigBytes []byte
-	clusterConfigerr := wait.PollUntilContextTimeout(context.Background(), 2*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
-		kubeconfigBytes, err = provider.ClusterKubeconfig(viper.GetString(config.Cluster.ID))
+	clusterConfigerr := wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
+		kubeconfigBytes, err = spi.WithContext(provider).ClusterKubeconfig(ctx, viper.GetString(config.Cluster.ID))
 		if err != nil {
 			log.Printf("Failed to retrieve kubeconfig: %v\nWaiting two seconds before retrying", err)
 			return false, nil
@@ -660,10 +736,10 @@ func SetClusterIntoViperConfig(cluster *spi.Cluster) {
 	log.Printf("CLOUD_PROVIDER_REGION set to %s from OCM.", viper.GetString(config.CloudProvider.Region))
 }
 
-func ConfigureVersion(provider spi.Provider) (int, error) {
+func ConfigureVersion(ctx context.Context, provider spi.Provider) (int, error) {
 	// configure cluster and upgrade versions
 	versionSelector := versions.VersionSelector{Provider: provider}
-	if err := versionSelector.SelectClusterVersions(); err != nil {
+	if err := versionSelector.SelectClusterVersions(ctx); err != nil {
 		// If we can't find a version to use, exit with an error code.
 		return config.Failure, err
 	}
@@ -690,13 +766,13 @@ func ConfigureVersion(provider spi.Provider) (int, error) {
 
 // ProvisionOrReuseCluster either provisions a new cluster or retrieves an existing one
 // based on whether a kubeconfig is already available.
-func ProvisionOrReuseCluster(provider spi.Provider) (*spi.Cluster, error) {
+func ProvisionOrReuseCluster(ctx context.Context, provider spi.Provider) (*spi.Cluster, error) {
 	var cluster *spi.Cluster
 	var err error
 
 	if viper.GetString(config.Kubeconfig.Contents) == "" {
 		// Provision new cluster
-		cluster, err = Provision(provider)
+		cluster, err = Provision(ctx, provider)
 		if err != nil {
 			return nil, fmt.Errorf("cluster provisioning failed: %w", err)
 		}
@@ -704,10 +780,16 @@ func ProvisionOrReuseCluster(provider spi.Provider) (*spi.Cluster, error) {
 		// Reuse existing cluster
 		log.Println("Using provided kubeconfig")
 		clusterID := viper.GetString(config.Cluster.ID)
-		cluster, err = provider.GetCluster(clusterID)
+		cluster, err = spi.WithContext(provider).GetCluster(ctx, clusterID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get cluster %s: %w", clusterID, err)
 		}
+
+		if IsHibernated(cluster.State()) {
+			if cluster, err = resumeReusedCluster(ctx, provider, cluster); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Set cluster into viper config for downstream usage
@@ -715,9 +797,27 @@ func ProvisionOrReuseCluster(provider spi.Provider) (*spi.Cluster, error) {
 	return cluster, nil
 }
 
+// resumeReusedCluster resumes a hibernated cluster that is reused for testing and waits until it's healthy.
+func resumeReusedCluster(ctx context.Context, provider spi.Provider, cluster *spi.Cluster) (*spi.Cluster, error) {
+	log.Printf("Cluster %s is %s, resuming it", cluster.ID(), cluster.State())
+	resumed, err := ResumeCluster(ctx, provider, cluster.ID(), time.Duration(viper.GetInt64(config.Cluster.InstallTimeout))*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume cluster %s: %w", cluster.ID(), err)
+	}
+
+	if viper.GetBool(config.Tests.SkipClusterHealthChecks) {
+		log.Println("Skipping health checks as requested")
+		return resumed, nil
+	}
+	if err := WaitForClusterReadyPostInstall(ctx, resumed.ID(), nil); err != nil {
+		return nil, fmt.Errorf("resumed cluster %s failed health check: %w", resumed.ID(), err)
+	}
+	return resumed, nil
+}
+
 // InstallAddonsIfConfigured installs addons on the cluster if configured in viper.
 // Returns true if addons were installed, false otherwise.
-func InstallAddonsIfConfigured(provider spi.Provider, clusterID string) (bool, error) {
+func InstallAddonsIfConfigured(ctx context.Context, provider spi.Provider, clusterID string) (bool, error) {
 	addonIDsStr := viper.GetString(config.Addons.IDs)
 	if len(addonIDsStr) == 0 {
 		return false, nil
@@ -735,14 +835,14 @@ func InstallAddonsIfConfigured(provider spi.Provider, clusterID string) (bool, e
 	}
 
 	// Install addons
-	num, err := provider.InstallAddons(clusterID, addonIDs, params)
+	num, err := spi.WithContext(provider).InstallAddons(ctx, clusterID, addonIDs, params)
 	if err != nil {
 		return false, fmt.Errorf("failed to install addons: %w", err)
 	}
 
 	// Wait for cluster to be ready after addon installation
 	if num > 0 {
-		if err := WaitForClusterReadyPostInstall(clusterID, nil); err != nil {
+		if err := WaitForClusterReadyPostInstall(ctx, clusterID, nil); err != nil {
 			return false, fmt.Errorf("cluster not ready after addon installation: %w", err)
 		}
 	}
@@ -778,6 +878,9 @@ func DeleteCluster(provider spi.Provider) error {
 
 // UpdateClusterProperties updates cluster metadata in the provider.
 func UpdateClusterProperties(provider spi.Provider, status string) error {
+	// The claim, if any, is released below
+	stopClaimRenewal()
+
 	clusterID := viper.GetString(config.Cluster.ID)
 	cluster, err := provider.GetCluster(clusterID)
 	if err != nil {
@@ -797,6 +900,17 @@ func UpdateClusterProperties(provider spi.Provider, status string) error {
 		clusterproperties.Availability: clusterproperties.Used,
 	}
 
+	// Release the claim on a cluster from the reserve, but only if this run still holds it
+	if viper.GetBool(config.Cluster.ClaimedFromReserve) {
+		if holder := cluster.Properties()[clusterproperties.ClaimToken]; holder != viper.GetString(config.Cluster.ClaimToken) {
+			log.Printf("Not releasing cluster %s, it is claimed by %q", clusterID, holder)
+			delete(properties, clusterproperties.Availability)
+		} else {
+			properties[clusterproperties.ClaimToken] = ""
+			properties[clusterproperties.ClaimLeaseExpiry] = ""
+		}
+	}
+
 	for key, value := range properties {
 		if err := provider.AddProperty(cluster, key, value); err != nil {
 			return fmt.Errorf("failed to set property %s: %w", key, err)
@@ -812,10 +926,15 @@ func HandleExpirationExtension(provider spi.Provider) error {
 	if clusterID == "" || !viper.GetBool(config.Cluster.SkipDestroyCluster) {
 		return nil
 	}
+	expiry, ok := spi.As[spi.ExpiryManager](provider)
+	if !ok {
+		log.Printf("Not extending cluster expiration: %v", spi.NewNotSupportedError(provider.Type(), spi.CapabilityExpiry))
+		return nil
+	}
 
 	// Extend expiration for nightly builds
 	if viper.GetString(config.Cluster.InstallSpecificNightly) != "" || viper.GetString(config.Cluster.ReleaseImageLatest) != "" {
-		if err := provider.Expire(clusterID, 30*time.Minute); err != nil {
+		if err := expiry.Expire(clusterID, 30*time.Minute); err != nil {
 			return err
 		}
 	}
@@ -828,7 +947,7 @@ func HandleExpirationExtension(provider spi.Provider) error {
 		}
 
 		if !cluster.ExpirationTimestamp().Add(6 * time.Hour).After(cluster.CreationTimestamp().Add(24 * time.Hour)) {
-			if err := provider.ExtendExpiry(clusterID, 6, 0, 0); err != nil {
+			if err := expiry.ExtendExpiry(clusterID, 6, 0, 0); err != nil {
 				return err
 			}
 		}
@@ -1,10 +1,17 @@
 package cluster
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/fakeprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
 )
 
 func TestRandomClusterName(t *testing.T) {
@@ -17,3 +24,127 @@ func TestRandomClusterName(t *testing.T) {
 		}
 	}
 }
+
+func setupFakeProvider(t *testing.T) *fakeprovider.FakeProvider {
+	t.Helper()
+
+	viper.Reset()
+	viper.Set(config.Provider, fakeprovider.Name)
+	viper.Set(config.Cluster.InstallTimeout, 1)
+	viper.Set(config.Cluster.EnoughVersionsForOldestOrMiddleTest, true)
+	viper.Set(config.Cluster.PreviousVersionFromDefaultFound, true)
+	viper.Set(config.Tests.SkipClusterHealthChecks, true)
+	t.Cleanup(viper.Reset)
+
+	interval := provisioningPollInterval
+	provisioningPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { provisioningPollInterval = interval })
+
+	provider := fakeprovider.New().WithVersions("4.17.1", "4.16.3")
+	fakeprovider.Install(provider)
+	return provider
+}
+
+func TestProvisionOrReuseClusterProvisions(t *testing.T) {
+	provider := setupFakeProvider(t)
+
+	cluster, err := ProvisionOrReuseCluster(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("unexpected error provisioning cluster: %v", err)
+	}
+
+	if cluster.ID() != viper.GetString(config.Cluster.ID) {
+		t.Errorf("expected cluster ID %s in config, got %s", cluster.ID(), viper.GetString(config.Cluster.ID))
+	}
+	if viper.GetString(config.Cluster.Version) != "4.17.1" {
+		t.Errorf("expected default version 4.17.1 to be selected, got %s", viper.GetString(config.Cluster.Version))
+	}
+	if viper.GetString(config.Kubeconfig.Contents) != fakeprovider.DefaultKubeconfig {
+		t.Errorf("expected kubeconfig to be set from provider")
+	}
+
+	status, err := provider.GetProperty(cluster.ID(), clusterproperties.Status)
+	if err != nil || status != clusterproperties.StatusHealthCheck {
+		t.Errorf("expected status property %s, got %q (%v)", clusterproperties.StatusHealthCheck, status, err)
+	}
+}
+
+func TestProvisionOrReuseClusterReuses(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("existing", "existing", "4.16.3", spi.ClusterStateReady)
+	viper.Set(config.Cluster.ID, "existing")
+	viper.Set(config.Kubeconfig.Contents, fakeprovider.DefaultKubeconfig)
+
+	cluster, err := ProvisionOrReuseCluster(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("unexpected error reusing cluster: %v", err)
+	}
+
+	if cluster.ID() != "existing" {
+		t.Errorf("expected existing cluster, got %s", cluster.ID())
+	}
+	if provider.Called("LaunchCluster") {
+		t.Errorf("expected no cluster to be launched")
+	}
+	if viper.GetString(config.Cluster.Version) != "4.16.3" {
+		t.Errorf("expected version of existing cluster in config, got %s", viper.GetString(config.Cluster.Version))
+	}
+}
+
+func TestProvisionOrReuseClusterLaunchFailure(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.FailOn("LaunchCluster", errors.New("out of capacity"))
+
+	if _, err := ProvisionOrReuseCluster(context.Background(), provider); err == nil || !strings.Contains(err.Error(), "out of capacity") {
+		t.Errorf("expected launch failure to be returned, got %v", err)
+	}
+}
+
+func TestWaitForOCMProvisioningCanceled(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("installing", "installing", "4.16.3", spi.ClusterStateInstalling)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := WaitForOCMProvisioning(ctx, provider, "installing", nil, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to be canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the wait to stop when canceled, took %v", elapsed)
+	}
+}
+
+func TestIsHCP(t *testing.T) {
+	tests := []struct {
+		providerType string
+		hypershift   bool
+		expected     bool
+	}{
+		{"rosa", false, false},
+		{"rosa", true, true},
+		{"hypershift", false, true},
+		{"aro", false, false},
+	}
+	for _, test := range tests {
+		viper.Set(config.Hypershift, test.hypershift)
+		if hcp := isHCP(test.providerType); hcp != test.expected {
+			t.Errorf("expected %q clusters with %s=%t to be HCP=%t, got %t", test.providerType, config.Hypershift, test.hypershift, test.expected, hcp)
+		}
+	}
+	viper.Set(config.Hypershift, false)
+}
+
+func TestValidateHealthChecks(t *testing.T) {
+	t.Cleanup(func() { viper.Set(config.Tests.HealthChecksExclude, "") })
+
+	viper.Set(config.Tests.HealthChecksExclude, "cert, node")
+	if err := ValidateHealthChecks(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	viper.Set(config.Tests.HealthChecksExclude, "cert,nodes")
+	if err := ValidateHealthChecks(); !errors.Is(err, ErrHealthCheckConfig) || !strings.Contains(err.Error(), "nodes") {
+		t.Errorf("expected a configuration error naming the unknown check, got %v", err)
+	}
+}
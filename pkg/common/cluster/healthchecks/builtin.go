@@ -0,0 +1,51 @@
+package healthchecks
+
+import (
+	"context"
+	"log"
+)
+
+// The built in checks run in this order. Those that rely on the machine API or certman only run
+// against classic OCM and ROSA clusters, hosted control plane clusters get the node pool,
+// konnectivity and API checks instead. ARO clusters get the API, node and operator checks. The SLO
+// check runs last, once the cluster is up enough to scrape.
+func init() {
+	hcp := ForHCP(true)
+	aro := ForProviders("aro")
+	managed := AnyOf(ForProviders("ocm", "rosa"), hcp)
+	classic := AllOf(ForProviders("ocm", "rosa"), ForHCP(false))
+
+	Register(NewCheck("cvo", managed, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckCVOReadiness(ctx, target.Config.ConfigV1(), logger)
+	}))
+	Register(NewCheck("node", AnyOf(managed, aro), func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckNodeHealth(ctx, target.Kube.CoreV1(), logger)
+	}))
+	Register(NewCheck("machine", classic, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckMachinesObjectState(ctx, target.Dynamic, logger)
+	}))
+	Register(NewCheck("operator", AnyOf(managed, aro), func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckOperatorReadiness(ctx, target.Config.ConfigV1(), logger)
+	}))
+	Register(NewCheck("cert", classic, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckCerts(ctx, target.Kube.CoreV1(), logger)
+	}))
+	Register(NewCheck("daemonset", managed, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckReplicaCountForDaemonSets(ctx, target.Kube.AppsV1(), logger)
+	}))
+	Register(NewCheck("replicaset", managed, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckReplicaCountForReplicaSets(ctx, target.Kube.AppsV1(), logger)
+	}))
+	Register(NewCheck("nodepool", hcp, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckNodePools(ctx, target.Kube.CoreV1(), logger)
+	}))
+	Register(NewCheck("konnectivity", hcp, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckKonnectivity(ctx, target.Kube, logger)
+	}))
+	Register(NewCheck("api", AnyOf(hcp, aro), func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckAPIReachability(ctx, target.Kube, logger)
+	}))
+	Register(NewCheck("slo", managed, func(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+		return CheckSLOs(ctx, target, logger)
+	}))
+}
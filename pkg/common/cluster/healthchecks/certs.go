@@ -23,7 +23,7 @@ var certCheck = certCheckData{
 }
 
 // CheckCerts will check for the presence of a cert issued by certman
-func CheckCerts(secretClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
+func CheckCerts(ctx context.Context, secretClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	if !certCheck.checkStarted {
@@ -34,7 +34,7 @@ func CheckCerts(secretClient v1.CoreV1Interface, logger *log.Logger) (bool, erro
 	listOpts := metav1.ListOptions{
 		LabelSelector: "certificate_request",
 	}
-	secrets, err := secretClient.Secrets("openshift-config").List(context.TODO(), listOpts)
+	secrets, err := secretClient.Secrets("openshift-config").List(ctx, listOpts)
 	if err != nil {
 		return false, fmt.Errorf("error trying to find issued certificate(s): %v", err)
 	}
@@ -1,6 +1,7 @@
 package healthchecks
 
 import (
+	"context"
 	"testing"
 
 	"github.com/openshift/osde2e/pkg/common/util"
@@ -55,7 +56,7 @@ func TestCerts(t *testing.T) {
 
 	for _, test := range tests {
 		kubeClient := kubernetes.NewSimpleClientset(test.objs...)
-		state, err := CheckCerts(kubeClient.CoreV1(), nil)
+		state, err := CheckCerts(context.Background(), kubeClient.CoreV1(), nil)
 		if err != nil {
 			t.Errorf("Unexpected error: %s", err)
 			return
@@ -2,6 +2,7 @@ package healthchecks
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	v1 "github.com/openshift/api/config/v1"
@@ -11,19 +12,19 @@ import (
 )
 
 // GetClusterVersionObject wlil get the cluster version object for the cluster.
-func GetClusterVersionObject(configClient configclient.ConfigV1Interface) (*v1.ClusterVersion, error) {
+func GetClusterVersionObject(ctx context.Context, configClient configclient.ConfigV1Interface) (*v1.ClusterVersion, error) {
 	getOpts := metav1.GetOptions{}
-	return configClient.ClusterVersions().Get(context.TODO(), "version", getOpts)
+	return configClient.ClusterVersions().Get(ctx, "version", getOpts)
 }
 
 // CheckCVOReadiness attempts to look at the state of the ClusterVersionOperator and returns true if things are healthy.
-func CheckCVOReadiness(configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
+func CheckCVOReadiness(ctx context.Context, configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	success := true
 	logger.Print("Checking that CVO says the cluster is healthy...")
 
-	cvInfo, err := GetClusterVersionObject(configClient)
+	cvInfo, err := GetClusterVersionObject(ctx, configClient)
 	if err != nil {
 		return false, err
 	}
@@ -46,6 +47,7 @@ func CheckCVOReadiness(configClient configclient.ConfigV1Interface, logger *log.
 			}
 		}
 		logger.Printf("CVO State not complete: %v: %v %v", v.Type, v.Status, v.Message)
+		reportObject(ctx, "ClusterVersion", "", cvInfo.Name, fmt.Sprintf("%v=%v: %v", v.Type, v.Status, v.Message))
 		success = false
 	}
 
@@ -1,6 +1,7 @@
 package healthchecks
 
 import (
+	"context"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -91,7 +92,7 @@ func TestCheckCVOReadiness(t *testing.T) {
 
 	for _, test := range tests {
 		cfgClient := fakeConfig.NewSimpleClientset(test.objs...)
-		state, err := CheckCVOReadiness(cfgClient.ConfigV1(), nil)
+		state, err := CheckCVOReadiness(context.Background(), cfgClient.ConfigV1(), nil)
 
 		if err != nil && !test.expectedError {
 			t.Errorf("Unexpected error: %s", err)
@@ -0,0 +1,123 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// nodePoolLabel names the node pool the nodes of a hosted cluster belong to.
+	nodePoolLabel = "hypershift.openshift.io/nodePool"
+
+	konnectivityNamespace = "kube-system"
+	konnectivityAgent     = "konnectivity-agent"
+)
+
+// CheckNodePools checks that every node pool of a hosted cluster has nodes and that they're all ready
+// and schedulable.
+func CheckNodePools(ctx context.Context, nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+	logger.Print("Checking that all NodePools have ready nodes...")
+
+	list, err := nodeClient.Nodes().List(ctx, metav1.ListOptions{LabelSelector: nodePoolLabel})
+	if err != nil {
+		return false, fmt.Errorf("error getting node list: %v", err)
+	}
+	if len(list.Items) == 0 {
+		return false, fmt.Errorf("no nodes belong to a NodePool")
+	}
+
+	notReady := map[string]int{}
+	nodes := map[string]int{}
+	for _, node := range list.Items {
+		pool := node.Labels[nodePoolLabel]
+		nodes[pool]++
+		if !isNodeReady(node) || node.Spec.Unschedulable {
+			logger.Printf("Node (%v) of NodePool %v is not ready or not schedulable", node.Name, pool)
+			notReady[pool]++
+		}
+	}
+
+	pools := make([]string, 0, len(nodes))
+	for pool := range nodes {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		if notReady[pool] > 0 {
+			reportObject(ctx, "NodePool", "", pool, fmt.Sprintf("%d out of %d nodes ready", nodes[pool]-notReady[pool], nodes[pool]))
+		}
+	}
+
+	return len(notReady) == 0, nil
+}
+
+// CheckKonnectivity checks that the konnectivity agents run on all nodes of a hosted cluster and that
+// the hosted API server reaches a kubelet through them.
+func CheckKonnectivity(ctx context.Context, kubeClient kubernetes.Interface, logger *log.Logger) (bool, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+	logger.Print("Checking that the API server reaches the nodes through konnectivity...")
+
+	ds, err := kubeClient.AppsV1().DaemonSets(konnectivityNamespace).Get(ctx, konnectivityAgent, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			reportObject(ctx, "DaemonSet", konnectivityNamespace, konnectivityAgent, "not found")
+		}
+		return false, fmt.Errorf("error getting the konnectivity agents: %v", err)
+	}
+	if ds.Status.DesiredNumberScheduled == 0 || ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
+		reason := fmt.Sprintf("%d out of %d agents ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+		logger.Printf("Konnectivity %s", reason)
+		reportObject(ctx, "DaemonSet", konnectivityNamespace, konnectivityAgent, reason)
+		return false, nil
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error getting node list: %v", err)
+	}
+	for _, node := range nodes.Items {
+		if !isNodeReady(node) {
+			continue
+		}
+		// Requests the API server proxies to a kubelet go through konnectivity on hosted clusters
+		_, err := kubeClient.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes", node.Name, "proxy", "healthz").DoRaw(ctx)
+		if err != nil {
+			logger.Printf("API server can't reach the kubelet of node (%v): %v", node.Name, err)
+			reportObject(ctx, "Node", "", node.Name, fmt.Sprintf("kubelet unreachable through konnectivity: %v", err))
+			return false, nil
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("no ready node to reach through konnectivity")
+}
+
+// CheckAPIReachability checks that the hosted API server is reachable and ready.
+func CheckAPIReachability(ctx context.Context, kubeClient kubernetes.Interface, logger *log.Logger) (bool, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+	logger.Print("Checking that the API server is reachable and ready...")
+
+	body, err := kubeClient.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+	if err != nil {
+		return false, fmt.Errorf("API server isn't ready: %v: %s", err, body)
+	}
+	return true, nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package healthchecks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func poolNode(name, pool string, ready bool) *v1.Node {
+	status := v1.ConditionTrue
+	if !ready {
+		status = v1.ConditionFalse
+	}
+	return &v1.Node{
+		TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{nodePoolLabel: pool}},
+		Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: status}}},
+	}
+}
+
+func TestCheckNodePools(t *testing.T) {
+	tests := []struct {
+		description   string
+		expected      bool
+		expectedError bool
+		objs          []runtime.Object
+	}{
+		{"no node pools", false, true, nil},
+		{"all ready", true, false, []runtime.Object{poolNode("a", "workers", true), poolNode("b", "infra", true)}},
+		{"one not ready", false, false, []runtime.Object{poolNode("a", "workers", true), poolNode("b", "workers", false)}},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ctx, recorder := withObjectRecorder(context.Background())
+			state, err := CheckNodePools(ctx, fake.NewSimpleClientset(test.objs...).CoreV1(), nil)
+			if state != test.expected || (err != nil) != test.expectedError {
+				t.Errorf("expected %t (error: %t), got %t (%v)", test.expected, test.expectedError, state, err)
+			}
+			if !state && err == nil && (len(recorder.reported()) != 1 || recorder.reported()[0].Name != "workers") {
+				t.Errorf("expected the workers NodePool to be reported, got %+v", recorder.reported())
+			}
+		})
+	}
+}
+
+// hostedAPIServer serves the konnectivity agents, a node and the endpoints the HCP checks request.
+func hostedAPIServer(t *testing.T, agentsReady int32, kubeletStatus int) kubernetes.Interface {
+	t.Helper()
+	agents := &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: konnectivityAgent, Namespace: konnectivityNamespace},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: agentsReady},
+	}
+	nodes := &v1.NodeList{
+		TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"},
+		Items:    []v1.Node{*poolNode("a", "workers", true)},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apis/apps/v1/namespaces/kube-system/daemonsets/konnectivity-agent", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(agents)
+	})
+	mux.HandleFunc("/api/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(nodes)
+	})
+	mux.HandleFunc("/api/v1/nodes/a/proxy/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(kubeletStatus)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestCheckKonnectivity(t *testing.T) {
+	tests := []struct {
+		description   string
+		agentsReady   int32
+		kubeletStatus int
+		expected      bool
+	}{
+		{"reachable", 2, http.StatusOK, true},
+		{"agents not ready", 1, http.StatusOK, false},
+		{"kubelet unreachable", 2, http.StatusServiceUnavailable, false},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ctx, recorder := withObjectRecorder(context.Background())
+			state, err := CheckKonnectivity(ctx, hostedAPIServer(t, test.agentsReady, test.kubeletStatus), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, state)
+			}
+			if !state && len(recorder.reported()) != 1 {
+				t.Errorf("expected the unhealthy object to be reported, got %+v", recorder.reported())
+			}
+		})
+	}
+}
+
+func TestCheckAPIReachability(t *testing.T) {
+	if state, err := CheckAPIReachability(context.Background(), hostedAPIServer(t, 2, http.StatusOK), nil); !state || err != nil {
+		t.Errorf("expected the API server to be ready, got %t (%v)", state, err)
+	}
+
+	unreachable, err := kubernetes.NewForConfig(&rest.Config{Host: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state, err := CheckAPIReachability(context.Background(), unreachable, nil); state || err == nil {
+		t.Errorf("expected an unreachable API server to fail, got %t (%v)", state, err)
+	}
+}
@@ -19,7 +19,7 @@ const (
 )
 
 // CheckMachinesObjectState lists all openshift machines and validates that they are "Running"
-func CheckMachinesObjectState(dynamicClient dynamic.Interface, logger *log.Logger) (bool, error) {
+func CheckMachinesObjectState(ctx context.Context, dynamicClient dynamic.Interface, logger *log.Logger) (bool, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	logger.Print("Checking that machines are healthy...")
@@ -27,7 +27,7 @@ func CheckMachinesObjectState(dynamicClient dynamic.Interface, logger *log.Logge
 	mc := dynamicClient.
 		Resource(schema.GroupVersionResource{Group: "machine.openshift.io", Resource: "machines", Version: "v1beta1"}).
 		Namespace(machinesNamespace)
-	obj, err := mc.List(context.TODO(), metav1.ListOptions{})
+	obj, err := mc.List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, err
 	}
@@ -13,14 +13,14 @@ import (
 )
 
 // CheckNodeHealth attempts to look at the state of all operator and returns true if things are healthy.
-func CheckNodeHealth(nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
+func CheckNodeHealth(ctx context.Context, nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	success := true
 	logger.Print("Checking that all Nodes are running or completed...")
 
 	listOpts := metav1.ListOptions{}
-	list, err := nodeClient.Nodes().List(context.TODO(), listOpts)
+	list, err := nodeClient.Nodes().List(ctx, listOpts)
 	if err != nil {
 		if openshift.IsRetryableAPIError(err) {
 			logger.Printf("error listing nodes: %v", err)
@@ -37,9 +37,11 @@ func CheckNodeHealth(nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, e
 		for _, ns := range node.Status.Conditions {
 			if ns.Type != corev1.NodeReady && ns.Status == corev1.ConditionTrue {
 				logger.Printf("Node (%v) issue: %v=%v %v\n", node.Name, ns.Type, ns.Status, ns.Message)
+				reportObject(ctx, "Node", "", node.Name, fmt.Sprintf("%v=%v: %v", ns.Type, ns.Status, ns.Message))
 				success = false
 			} else if ns.Type == corev1.NodeReady && ns.Status != corev1.ConditionTrue {
 				logger.Printf("Node (%v) not ready: %v=%v %v\n", node.Name, ns.Type, ns.Status, ns.Message)
+				reportObject(ctx, "Node", "", node.Name, fmt.Sprintf("%v=%v: %v", ns.Type, ns.Status, ns.Message))
 				success = false
 			}
 		}
@@ -47,6 +49,7 @@ func CheckNodeHealth(nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, e
 		for _, nt := range node.Spec.Taints {
 			if nt.Effect == corev1.TaintEffectNoSchedule && nt.Key == corev1.TaintNodeUnschedulable {
 				logger.Printf("Node (%v) not schedulable with taint: %v=%v\n", node.Name, nt.Key, nt.Effect)
+				reportObject(ctx, "Node", "", node.Name, fmt.Sprintf("not schedulable with taint %v=%v", nt.Key, nt.Effect))
 				success = false
 			}
 		}
@@ -0,0 +1,46 @@
+package healthchecks
+
+import (
+	"context"
+	"sync"
+)
+
+// Object is a cluster object a check found unhealthy.
+type Object struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+type objectRecorderKey struct{}
+
+// objectRecorder collects the unhealthy objects reported while a check runs.
+type objectRecorder struct {
+	mutex   sync.Mutex
+	objects []Object
+}
+
+// withObjectRecorder returns a context the unhealthy objects found by a check can be reported to.
+func withObjectRecorder(ctx context.Context) (context.Context, *objectRecorder) {
+	recorder := &objectRecorder{}
+	return context.WithValue(ctx, objectRecorderKey{}, recorder), recorder
+}
+
+// reportObject adds an unhealthy object to the result of the check running with the context. It
+// does nothing when the check function is called directly instead of through a Check.
+func reportObject(ctx context.Context, kind, namespace, name, reason string) {
+	recorder, ok := ctx.Value(objectRecorderKey{}).(*objectRecorder)
+	if !ok {
+		return
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.objects = append(recorder.objects, Object{Kind: kind, Namespace: namespace, Name: name, Reason: reason})
+}
+
+func (r *objectRecorder) reported() []Object {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.objects
+}
@@ -14,14 +14,14 @@ import (
 )
 
 // CheckOperatorReadiness attempts to look at the state of all operator and returns true if things are healthy.
-func CheckOperatorReadiness(configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
+func CheckOperatorReadiness(ctx context.Context, configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	success := true
 	logger.Print("Checking that all Operators are running or completed...")
 
 	listOpts := metav1.ListOptions{}
-	list, err := configClient.ClusterOperators().List(context.TODO(), listOpts)
+	list, err := configClient.ClusterOperators().List(ctx, listOpts)
 	if err != nil {
 		return false, fmt.Errorf("error getting cluster operator list: %v", err)
 	}
@@ -45,16 +45,19 @@ func CheckOperatorReadiness(configClient configclient.ConfigV1Interface, logger
 			for _, cos := range co.Status.Conditions {
 				if cos.Type == "Available" && cos.Status == "False" {
 					logger.Printf("Operator %v not available: Condition %v has status %v: %v", co.Name, cos.Type, cos.Status, cos.Message)
+					reportObject(ctx, "ClusterOperator", "", co.Name, fmt.Sprintf("%v=%v: %v", cos.Type, cos.Status, cos.Message))
 					success = false
 				}
 
 				if cos.Type == "Progressing" && cos.Status == "True" {
 					logger.Printf("Operator %v is progressing: Condition %v has status %v: %v", co.Name, cos.Type, cos.Status, cos.Message)
+					reportObject(ctx, "ClusterOperator", "", co.Name, fmt.Sprintf("%v=%v: %v", cos.Type, cos.Status, cos.Message))
 					success = false
 				}
 
 				if cos.Type == "Degraded" && cos.Status == "True" {
 					logger.Printf("Operator %v is degraded: Condition %v has status %v: %v", co.Name, cos.Type, cos.Status, cos.Message)
+					reportObject(ctx, "ClusterOperator", "", co.Name, fmt.Sprintf("%v=%v: %v", cos.Type, cos.Status, cos.Message))
 					success = false
 				}
 			}
@@ -1,6 +1,7 @@
 package healthchecks
 
 import (
+	"context"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -104,7 +105,7 @@ func TestCheckOperatorReadiness(t *testing.T) {
 		viper.Reset()
 		cfgClient := fakeConfig.NewSimpleClientset(test.objs...)
 		viper.Set(config.Tests.OperatorSkip, test.skip)
-		state, err := CheckOperatorReadiness(cfgClient.ConfigV1(), nil)
+		state, err := CheckOperatorReadiness(context.Background(), cfgClient.ConfigV1(), nil)
 
 		if err != nil && !test.expectedError {
 			t.Errorf("Unexpected error: %s", err)
@@ -28,7 +28,7 @@ func (p *PodErrorTracker) NewPodErrorTracker(threshold int) *PodErrorTracker {
 }
 
 // CheckPodHealth attempts to look at the state of all pods and returns true if things are healthy.
-func CheckPodHealth(podClient v1.CoreV1Interface, logger *log.Logger, ns string, podPrefixes ...string) (bool, error) {
+func CheckPodHealth(ctx context.Context, podClient v1.CoreV1Interface, logger *log.Logger, ns string, podPrefixes ...string) (bool, error) {
 	filters := []PodPredicate{
 		IsOlderThan(1 * time.Minute),
 		MatchesNamespace(ns),
@@ -38,7 +38,7 @@ func CheckPodHealth(podClient v1.CoreV1Interface, logger *log.Logger, ns string,
 		IsNotCompleted,
 		IsNotControlledByJob,
 	}
-	podlist, err := checkPods(podClient, logger, filters...)
+	podlist, err := checkPods(ctx, podClient, logger, filters...)
 	if err != nil {
 		return false, err
 	}
@@ -46,12 +46,12 @@ func CheckPodHealth(podClient v1.CoreV1Interface, logger *log.Logger, ns string,
 }
 
 // checkPods looks for pods matching the supplied predicates and returns the list of pods (pending pods) if any are found
-func checkPods(podClient v1.CoreV1Interface, logger *log.Logger, filters ...PodPredicate) ([]kubev1.Pod, error) {
+func checkPods(ctx context.Context, podClient v1.CoreV1Interface, logger *log.Logger, filters ...PodPredicate) ([]kubev1.Pod, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	logger.Print("Checking that all Pods are running or completed...")
 
-	list, err := podClient.Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	list, err := podClient.Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("error getting pod list: %v", err)
 	}
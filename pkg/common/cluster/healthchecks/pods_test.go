@@ -1,6 +1,7 @@
 package healthchecks
 
 import (
+	"context"
 	"strconv"
 	"strings"
 	"testing"
@@ -104,7 +105,7 @@ func TestCheckPodHealth(t *testing.T) {
 
 	for _, test := range tests {
 		kubeClient := kubernetes.NewSimpleClientset(test.objs...)
-		state, err := CheckPodHealth(kubeClient.CoreV1(), nil, ns1, "")
+		state, err := CheckPodHealth(context.Background(), kubeClient.CoreV1(), nil, ns1, "")
 
 		// Length of the pending pods list is validated here. The list may have multiple pending pods even if the error is for one pending pod.
 		if state != test.isHealthy {
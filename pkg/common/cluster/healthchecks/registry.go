@@ -0,0 +1,264 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	osconfig "github.com/openshift/client-go/config/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Target is the cluster health checks run against.
+type Target struct {
+	// ProviderType is the type of the provider of the cluster, e.g. ocm or rosa
+	ProviderType string
+	// CloudProvider is the cloud the cluster runs in, e.g. aws or gcp
+	CloudProvider string
+	// Environment is the environment of the provider, e.g. int or prod
+	Environment string
+	// HCP is set for clusters with a hosted control plane
+	HCP bool
+
+	RestConfig *rest.Config
+	Kube       kubernetes.Interface
+	Config     osconfig.Interface
+	Dynamic    dynamic.Interface
+}
+
+// NewTarget creates the clients for checking the health of the cluster behind the rest config.
+func NewTarget(restConfig *rest.Config, providerType, cloudProvider string, hcp bool) (*Target, error) {
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Kube Clientset: %w", err)
+	}
+	configClient, err := osconfig.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating OpenShift Clientset: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Dynamic Clientset: %w", err)
+	}
+
+	return &Target{
+		ProviderType:  providerType,
+		CloudProvider: cloudProvider,
+		HCP:           hcp,
+		RestConfig:    restConfig,
+		Kube:          kubeClient,
+		Config:        configClient,
+		Dynamic:       dynamicClient,
+	}, nil
+}
+
+// Result is the outcome of running a check.
+type Result struct {
+	// Name is the name of the check
+	Name string
+	// Passed is set if the cluster is healthy as far as the check is concerned
+	Passed bool
+	// Message describes why the check failed if there's no error to tell
+	Message string
+	// Err is the error the check failed with
+	Err error
+	// Objects are the cluster objects the check found unhealthy
+	Objects []Object
+	// Duration is how long the check took
+	Duration time.Duration
+}
+
+// Check verifies one aspect of the health of a cluster.
+type Check interface {
+	// Name identifies the check in logs, results and the include and exclude lists
+	Name() string
+	// Applies reports whether the check can run against the target, e.g. only against some providers
+	Applies(target *Target) bool
+	// Run checks the health of the target. A failed result must have an error or a message set.
+	Run(ctx context.Context, target *Target, logger *log.Logger) Result
+}
+
+// Predicate decides whether a check applies to a target.
+type Predicate func(target *Target) bool
+
+// Always applies a check to every target.
+func Always(*Target) bool { return true }
+
+// ForProviders applies a check to targets of the provider types.
+func ForProviders(providerTypes ...string) Predicate {
+	return func(target *Target) bool { return slices.Contains(providerTypes, target.ProviderType) }
+}
+
+// ForClouds applies a check to targets running in the clouds.
+func ForClouds(clouds ...string) Predicate {
+	return func(target *Target) bool { return slices.Contains(clouds, target.CloudProvider) }
+}
+
+// ForHCP applies a check to targets with, or without, a hosted control plane.
+func ForHCP(hcp bool) Predicate {
+	return func(target *Target) bool { return target.HCP == hcp }
+}
+
+// AnyOf applies a check to targets any of the predicates apply to.
+func AnyOf(predicates ...Predicate) Predicate {
+	return func(target *Target) bool {
+		for _, applies := range predicates {
+			if applies(target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf applies a check to targets all the predicates apply to.
+func AllOf(predicates ...Predicate) Predicate {
+	return func(target *Target) bool {
+		for _, applies := range predicates {
+			if !applies(target) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// CheckFunc checks the health of a target the way the Check functions of this package do, returning
+// whether it's healthy and the error that made it unhealthy. The unhealthy objects the Check
+// functions of this package find are added to the result.
+type CheckFunc func(ctx context.Context, target *Target, logger *log.Logger) (bool, error)
+
+type funcCheck struct {
+	name    string
+	applies Predicate
+	run     CheckFunc
+}
+
+// NewCheck creates a check from a function and the predicate deciding which targets it applies to.
+func NewCheck(name string, applies Predicate, run CheckFunc) Check {
+	return &funcCheck{name: name, applies: applies, run: run}
+}
+
+func (c *funcCheck) Name() string { return c.name }
+
+func (c *funcCheck) Applies(target *Target) bool { return c.applies(target) }
+
+func (c *funcCheck) Run(ctx context.Context, target *Target, logger *log.Logger) Result {
+	ctx, recorder := withObjectRecorder(ctx)
+	passed, err := c.run(ctx, target, logger)
+	result := Result{Passed: passed && err == nil, Err: err, Objects: recorder.reported()}
+	if !result.Passed && err == nil {
+		result.Message = fmt.Sprintf("%s check failed", c.name)
+	}
+	return result
+}
+
+// registry holds checks in the order they were registered, which is the order they run in.
+type registry struct {
+	checks []Check
+}
+
+// defaultRegistry holds the built in checks and those registered by other packages.
+var defaultRegistry = &registry{}
+
+func (r *registry) register(check Check) {
+	for _, registered := range r.checks {
+		if registered.Name() == check.Name() {
+			panic(fmt.Sprintf("Duplicate health check name %s!", check.Name()))
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// selectChecks returns the checks that apply to the target, limited to the included ones if any
+// are, without the excluded ones. Naming a check that isn't registered is an error.
+func (r *registry) selectChecks(target *Target, include, exclude []string) ([]Check, error) {
+	if err := r.validate(include, exclude); err != nil {
+		return nil, err
+	}
+
+	var selected []Check
+	for _, check := range r.checks {
+		if len(include) > 0 && !slices.Contains(include, check.Name()) {
+			continue
+		}
+		if slices.Contains(exclude, check.Name()) || !check.Applies(target) {
+			continue
+		}
+		selected = append(selected, check)
+	}
+	return selected, nil
+}
+
+// validate fails if the include or exclude list names a check that isn't registered.
+func (r *registry) validate(include, exclude []string) error {
+	var unknown []string
+	for _, name := range append(slices.Clone(include), exclude...) {
+		if !slices.ContainsFunc(r.checks, func(check Check) bool { return check.Name() == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown health checks %s, expected some of %s", strings.Join(unknown, ", "), strings.Join(r.names(), ", "))
+	}
+	return nil
+}
+
+func (r *registry) names() []string {
+	names := make([]string, 0, len(r.checks))
+	for _, check := range r.checks {
+		names = append(names, check.Name())
+	}
+	return names
+}
+
+// Register adds a health check. It must be called before clusters are checked, e.g. from an init
+// function, and panics if a check with the same name is registered.
+func Register(check Check) {
+	defaultRegistry.register(check)
+}
+
+// Names returns the names of the registered checks in the order they run in.
+func Names() []string {
+	return defaultRegistry.names()
+}
+
+// Select returns the registered checks that apply to the target, limited to the included ones if
+// any are, without the excluded ones.
+func Select(target *Target, include, exclude []string) ([]Check, error) {
+	return defaultRegistry.selectChecks(target, include, exclude)
+}
+
+// Validate fails if the include or exclude list names a check that isn't registered.
+func Validate(include, exclude []string) error {
+	return defaultRegistry.validate(include, exclude)
+}
+
+// Run runs the checks in order against the target and returns their results.
+func Run(ctx context.Context, checks []Check, target *Target, logger *log.Logger) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		result := check.Run(ctx, target, logger)
+		result.Name = check.Name()
+		result.Duration = time.Since(start)
+		results = append(results, result)
+	}
+	return results
+}
+
+// ParseList splits a comma-delimited list of check names, ignoring blanks.
+func ParseList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
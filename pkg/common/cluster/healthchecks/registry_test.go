@@ -0,0 +1,142 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"testing"
+
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func staticCheck(name string, applies Predicate, passed bool, err error) Check {
+	return NewCheck(name, applies, func(context.Context, *Target, *log.Logger) (bool, error) {
+		return passed, err
+	})
+}
+
+func testRegistry() *registry {
+	r := &registry{}
+	r.register(staticCheck("always", Always, true, nil))
+	r.register(staticCheck("rosa", ForProviders("rosa"), true, nil))
+	r.register(staticCheck("hcp-gcp", AllOf(ForHCP(true), ForClouds("gcp")), true, nil))
+	r.register(staticCheck("classic", ForHCP(false), true, nil))
+	return r
+}
+
+func names(checks []Check) []string {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	return names
+}
+
+func TestSelectChecks(t *testing.T) {
+	tests := []struct {
+		description string
+		target      Target
+		include     []string
+		exclude     []string
+		expected    []string
+	}{
+		{"applicable to ocm classic", Target{ProviderType: "ocm", CloudProvider: "aws"}, nil, nil, []string{"always", "classic"}},
+		{"applicable to rosa hcp on gcp", Target{ProviderType: "rosa", CloudProvider: "gcp", HCP: true}, nil, nil, []string{"always", "rosa", "hcp-gcp"}},
+		{"included", Target{ProviderType: "rosa"}, []string{"rosa", "hcp-gcp"}, nil, []string{"rosa"}},
+		{"excluded", Target{ProviderType: "rosa"}, nil, []string{"always"}, []string{"rosa", "classic"}},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			checks, err := testRegistry().selectChecks(&test.target, test.include, test.exclude)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if selected := names(checks); !slices.Equal(selected, test.expected) {
+				t.Errorf("expected checks %v, got %v", test.expected, selected)
+			}
+		})
+	}
+}
+
+func TestSelectUnknownChecks(t *testing.T) {
+	if _, err := testRegistry().selectChecks(&Target{}, []string{"always"}, []string{"etcd"}); err == nil {
+		t.Errorf("expected an error for an unknown check")
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering a duplicate check to panic")
+		}
+	}()
+	testRegistry().register(staticCheck("always", Always, true, nil))
+}
+
+func TestRun(t *testing.T) {
+	errUnhealthy := errors.New("unhealthy")
+	checks := []Check{
+		staticCheck("passed", Always, true, nil),
+		staticCheck("failed", Always, false, nil),
+		staticCheck("errored", Always, true, errUnhealthy),
+	}
+
+	results := Run(context.Background(), checks, &Target{}, nil)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	if !results[0].Passed || results[0].Name != "passed" {
+		t.Errorf("expected the first check to pass, got %+v", results[0])
+	}
+	if results[1].Passed || results[1].Message == "" {
+		t.Errorf("expected the second check to fail with a message, got %+v", results[1])
+	}
+	if results[2].Passed || !errors.Is(results[2].Err, errUnhealthy) {
+		t.Errorf("expected the third check to fail with its error, got %+v", results[2])
+	}
+}
+
+func TestRunReportsObjects(t *testing.T) {
+	check := NewCheck("operator", Always, func(ctx context.Context, _ *Target, _ *log.Logger) (bool, error) {
+		reportObject(ctx, "ClusterOperator", "", "ingress", "Degraded=True")
+		return false, nil
+	})
+
+	results := Run(context.Background(), []Check{check}, &Target{}, nil)
+	if len(results[0].Objects) != 1 || results[0].Objects[0].Name != "ingress" {
+		t.Errorf("expected the degraded operator in the result, got %+v", results[0])
+	}
+}
+
+func TestBuiltinChecks(t *testing.T) {
+	profiles := []struct {
+		target   Target
+		expected []string
+	}{
+		{Target{ProviderType: "mock"}, nil},
+		{Target{ProviderType: "rosa"}, []string{"cvo", "node", "machine", "operator", "cert", "daemonset", "replicaset", "slo"}},
+		{Target{ProviderType: "rosa", HCP: true}, []string{"cvo", "node", "operator", "daemonset", "replicaset", "nodepool", "konnectivity", "api", "slo"}},
+		{Target{ProviderType: "hypershift", HCP: true}, []string{"cvo", "node", "operator", "daemonset", "replicaset", "nodepool", "konnectivity", "api", "slo"}},
+		{Target{ProviderType: "aro"}, []string{"node", "operator", "api"}},
+	}
+	for _, profile := range profiles {
+		checks, err := Select(&profile.target, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if selected := names(checks); !slices.Equal(selected, profile.expected) {
+			t.Errorf("expected the checks %v to apply to %+v, got %v", profile.expected, profile.target, selected)
+		}
+	}
+
+	target := &Target{ProviderType: "rosa", Kube: kubernetes.NewSimpleClientset()}
+	checks, err := Select(target, []string{"node"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results := Run(context.Background(), checks, target, nil)
+	if len(results) != 1 || results[0].Passed || results[0].Err == nil {
+		t.Errorf("expected the node check to fail without nodes, got %+v", results)
+	}
+}
@@ -13,12 +13,12 @@ import (
 )
 
 // CheckReplicaCountForDaemonSets checks if all the daemonsets running on the cluster have expected replicas
-func CheckReplicaCountForDaemonSets(dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
+func CheckReplicaCountForDaemonSets(ctx context.Context, dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
 	allErrors := &multierror.Error{}
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	logger.Print("Checking that all Daemonsets are running with expected replicas...")
 
-	dsList, err := dsClient.DaemonSets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	dsList, err := dsClient.DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, multierror.Append(allErrors, err)
 	}
@@ -36,6 +36,7 @@ func CheckReplicaCountForDaemonSets(dsClient appsv1.AppsV1Interface, logger *log
 			}
 			if ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
 				err = fmt.Errorf("daemonset %s has %d out of %d replicas ready", ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+				reportObject(ctx, "DaemonSet", ds.Namespace, ds.Name, err.Error())
 				allErrors = multierror.Append(allErrors, err)
 			}
 		}
@@ -48,12 +49,12 @@ func CheckReplicaCountForDaemonSets(dsClient appsv1.AppsV1Interface, logger *log
 }
 
 // CheckReplicaCountForReplicaSets checks if all the replicasets running on the cluster have expected replicas
-func CheckReplicaCountForReplicaSets(dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
+func CheckReplicaCountForReplicaSets(ctx context.Context, dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
 	allErrors := &multierror.Error{}
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	logger.Print("Checking that all Replicasets are running with expected replicas...")
 
-	rsList, err := dsClient.ReplicaSets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	rsList, err := dsClient.ReplicaSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, multierror.Append(allErrors, err)
 	}
@@ -71,6 +72,7 @@ func CheckReplicaCountForReplicaSets(dsClient appsv1.AppsV1Interface, logger *lo
 			}
 			if rs.Status.ReadyReplicas != rs.Status.Replicas {
 				err = fmt.Errorf("replicaset %s has %d out of %d replicas ready", rs.Name, rs.Status.ReadyReplicas, rs.Status.Replicas)
+				reportObject(ctx, "ReplicaSet", rs.Namespace, rs.Name, err.Error())
 				allErrors = multierror.Append(allErrors, err)
 			}
 		}
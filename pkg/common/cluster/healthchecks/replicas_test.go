@@ -1,6 +1,7 @@
 package healthchecks
 
 import (
+	"context"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -49,7 +50,7 @@ func TestCheckReplicaCountForDaemonSets(t *testing.T) {
 	}
 	for _, test := range tests {
 		kubeClient := kubernetes.NewSimpleClientset(test.objs...)
-		state, err := CheckReplicaCountForDaemonSets(kubeClient.AppsV1(), nil)
+		state, err := CheckReplicaCountForDaemonSets(context.Background(), kubeClient.AppsV1(), nil)
 		if err != nil {
 			if !test.expectedError {
 				t.Errorf("Unexpected error: %s", err)
@@ -84,7 +85,7 @@ func TestCheckReplicaCountForReplicaSets(t *testing.T) {
 	}
 	for _, test := range tests {
 		kubeClient := kubernetes.NewSimpleClientset(test.objs...)
-		state, err := CheckReplicaCountForReplicaSets(kubeClient.AppsV1(), nil)
+		state, err := CheckReplicaCountForReplicaSets(context.Background(), kubeClient.AppsV1(), nil)
 		if err != nil {
 			if !test.expectedError {
 				t.Errorf("Unexpected error: %s", err)
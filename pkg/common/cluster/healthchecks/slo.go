@@ -0,0 +1,49 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	"github.com/openshift/osde2e/pkg/common/prometheus"
+	"github.com/openshift/osde2e/pkg/common/slo"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// CheckSLOs checks that the cluster meets the SLO assertions of the health stage, as queried from
+// its Prometheus.
+func CheckSLOs(ctx context.Context, target *Target, logger *log.Logger) (bool, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	sloConfig, err := slo.Load(viper.GetString(config.Tests.SLOConfig))
+	if err != nil {
+		return false, err
+	}
+	cluster := slo.Cluster{Provider: target.ProviderType, Environment: target.Environment, HCP: target.HCP}
+	if !sloConfig.Applies(cluster, slo.StageHealth) {
+		return true, nil
+	}
+
+	logger.Print("Checking that the cluster meets its SLOs...")
+	client, err := prometheus.CreateClusterClient(ctx, target.RestConfig)
+	if err != nil {
+		return false, fmt.Errorf("error creating the Prometheus client: %w", err)
+	}
+	return checkSLOs(ctx, v1.NewAPI(client), sloConfig, cluster, logger)
+}
+
+func checkSLOs(ctx context.Context, querier slo.Querier, sloConfig *slo.Config, cluster slo.Cluster, logger *log.Logger) (bool, error) {
+	results := slo.Evaluate(ctx, querier, sloConfig, cluster, slo.StageHealth, logger)
+	for _, result := range results {
+		for _, violation := range result.Violations {
+			reportObject(ctx, "SLO", "", result.Name, fmt.Sprintf("%s: %s", violation.Series, violation.Reason))
+		}
+	}
+	if slo.Passed(results) {
+		return true, nil
+	}
+	return false, fmt.Errorf("SLOs not met: %s", slo.Summary(results))
+}
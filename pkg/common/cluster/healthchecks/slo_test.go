@@ -0,0 +1,62 @@
+package healthchecks
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/slo"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+type fakeQuerier map[string]model.Value
+
+func (f fakeQuerier) Query(_ context.Context, query string, _ time.Time, _ ...v1.Option) (model.Value, v1.Warnings, error) {
+	return f[query], nil, nil
+}
+
+func TestCheckSLOs(t *testing.T) {
+	sloConfig, err := slo.Parse([]byte(`
+assertions:
+- name: error-rate
+  query: errors
+  max: 0.01
+  stages: [health]
+- name: restarts
+  query: restarts
+  max: 10
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		errorRate   float64
+		expected    bool
+	}{
+		{"within bounds", 0.001, true},
+		{"out of bounds", 0.2, false},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			querier := fakeQuerier{
+				"errors":   &model.Scalar{Value: model.SampleValue(test.errorRate)},
+				"restarts": model.Vector{{Metric: model.Metric{}, Value: 100}},
+			}
+			check := NewCheck("slo", Always, func(ctx context.Context, _ *Target, logger *log.Logger) (bool, error) {
+				return checkSLOs(ctx, querier, sloConfig, slo.Cluster{Provider: "rosa"}, logger)
+			})
+
+			results := Run(context.Background(), []Check{check}, &Target{}, nil)
+			if results[0].Passed != test.expected {
+				t.Errorf("expected passed to be %v, got %+v", test.expected, results[0])
+			}
+			if !test.expected && (len(results[0].Objects) != 1 || results[0].Objects[0].Name != "error-rate") {
+				t.Errorf("expected the error rate SLO in the result, got %+v", results[0].Objects)
+			}
+		})
+	}
+}
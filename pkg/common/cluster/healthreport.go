@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+const (
+	// healthReportFile is the name of the health report written to the report directory.
+	healthReportFile = "health-report.json"
+
+	// upgradeHealthReportFile is the name of the health report of an upgraded cluster.
+	upgradeHealthReportFile = "upgrade-health-report.json"
+)
+
+// HealthReport is the outcome of waiting for a cluster to become healthy, with the outcome of
+// every check in every poll.
+type HealthReport struct {
+	ClusterID string    `json:"clusterID"`
+	Upgrade   bool      `json:"upgrade"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	Healthy   bool      `json:"healthy"`
+	// TimeToHealthySeconds is how long it took from the first poll until the cluster was healthy
+	TimeToHealthySeconds float64 `json:"timeToHealthySeconds,omitempty"`
+	Polls                int     `json:"polls"`
+	// Errors are the polls the checks couldn't run in
+	Errors []HealthPollError    `json:"errors,omitempty"`
+	Checks []*HealthCheckReport `json:"checks"`
+}
+
+// HealthPollError is the reason the checks couldn't run in a poll.
+type HealthPollError struct {
+	Poll  int       `json:"poll"`
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// HealthCheckReport is the outcome of a check across polls.
+type HealthCheckReport struct {
+	Name       string            `json:"name"`
+	Passed     int               `json:"passed"`
+	Failed     int               `json:"failed"`
+	LastPassed bool              `json:"lastPassed"`
+	Polls      []HealthCheckPoll `json:"polls"`
+}
+
+// HealthCheckPoll is the outcome of a check in one poll.
+type HealthCheckPoll struct {
+	Poll            int                   `json:"poll"`
+	Time            time.Time             `json:"time"`
+	Passed          bool                  `json:"passed"`
+	DurationSeconds float64               `json:"durationSeconds"`
+	Message         string                `json:"message,omitempty"`
+	Error           string                `json:"error,omitempty"`
+	Objects         []healthchecks.Object `json:"objects,omitempty"`
+}
+
+// healthRecorder builds the health report of a cluster poll by poll.
+type healthRecorder struct {
+	report HealthReport
+}
+
+func newHealthRecorder(clusterID string, upgrade bool) *healthRecorder {
+	return &healthRecorder{report: HealthReport{
+		ClusterID: clusterID,
+		Upgrade:   upgrade,
+		StartedAt: time.Now().UTC(),
+		Checks:    []*HealthCheckReport{},
+	}}
+}
+
+// record adds the results of a poll, or the error the checks couldn't run with.
+func (r *healthRecorder) record(results []healthchecks.Result, err error) {
+	r.report.Polls++
+	now := time.Now().UTC()
+	if err != nil {
+		r.report.Errors = append(r.report.Errors, HealthPollError{Poll: r.report.Polls, Time: now, Error: err.Error()})
+		return
+	}
+
+	for _, result := range results {
+		poll := HealthCheckPoll{
+			Poll:            r.report.Polls,
+			Time:            now,
+			Passed:          result.Passed,
+			DurationSeconds: result.Duration.Seconds(),
+			Message:         result.Message,
+			Objects:         result.Objects,
+		}
+		if result.Err != nil {
+			poll.Error = result.Err.Error()
+		}
+
+		check := r.check(result.Name)
+		if result.Passed {
+			check.Passed++
+		} else {
+			check.Failed++
+		}
+		check.LastPassed = result.Passed
+		check.Polls = append(check.Polls, poll)
+	}
+}
+
+func (r *healthRecorder) check(name string) *HealthCheckReport {
+	for _, check := range r.report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	check := &HealthCheckReport{Name: name}
+	r.report.Checks = append(r.report.Checks, check)
+	return check
+}
+
+// finish ends the report with whether the cluster became healthy.
+func (r *healthRecorder) finish(healthy bool) {
+	r.report.EndedAt = time.Now().UTC()
+	r.report.Healthy = healthy
+	if healthy {
+		r.report.TimeToHealthySeconds = r.report.EndedAt.Sub(r.report.StartedAt).Seconds()
+	}
+}
+
+// write writes the report as JSON and its polls as JUnit testcases to the report directory, if one
+// is configured.
+func (r *healthRecorder) write() error {
+	reportDir := viper.GetString(config.ReportDir)
+	if reportDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(reportDir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating report directory: %w", err)
+	}
+
+	name := healthReportFile
+	if r.report.Upgrade {
+		name = upgradeHealthReportFile
+	}
+	data, err := json.MarshalIndent(r.report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding health report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, name), data, 0o644); err != nil {
+		return fmt.Errorf("error writing health report: %w", err)
+	}
+
+	data, err = xml.MarshalIndent(r.junit(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding health check junit: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, HealthJUnitFile(r.report.Upgrade)), append([]byte(xml.Header), data...), 0o644); err != nil {
+		return fmt.Errorf("error writing health check junit: %w", err)
+	}
+	return nil
+}
+
+// HealthJUnitFile is the name of the JUnit file the health checks of a cluster are written to.
+func HealthJUnitFile(upgrade bool) string {
+	name := "health"
+	if upgrade {
+		name = "upgrade-health"
+	}
+	return fmt.Sprintf("junit_%s_%s.xml", name, viper.GetString(config.Suffix))
+}
+
+type healthJUnitSuite struct {
+	XMLName   xml.Name          `xml:"testsuite"`
+	Name      string            `xml:"name,attr"`
+	Tests     int               `xml:"tests,attr"`
+	Failures  int               `xml:"failures,attr"`
+	Skipped   int               `xml:"skipped,attr,omitempty"`
+	Time      string            `xml:"time,attr"`
+	TestCases []healthJUnitCase `xml:"testcase"`
+}
+
+type healthJUnitCase struct {
+	Name      string              `xml:"name,attr"`
+	Classname string              `xml:"classname,attr"`
+	Time      string              `xml:"time,attr"`
+	Failure   *healthJUnitFailure `xml:"failure,omitempty"`
+	Skipped   *healthJUnitFailure `xml:"skipped,omitempty"`
+	SystemOut string              `xml:"system-out,omitempty"`
+}
+
+type healthJUnitFailure struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// junit returns a testcase per check per poll. The testcases of a check share its name, so a check
+// that failed before it passed shows as flaky rather than failed.
+func (r *healthRecorder) junit() healthJUnitSuite {
+	suite := healthJUnitSuite{
+		Name: "cluster health checks",
+		Time: fmt.Sprintf("%.3f", r.report.EndedAt.Sub(r.report.StartedAt).Seconds()),
+	}
+	if r.report.Upgrade {
+		suite.Name = "upgraded cluster health checks"
+	}
+
+	for _, check := range r.report.Checks {
+		for _, poll := range check.Polls {
+			testCase := healthJUnitCase{
+				Name:      fmt.Sprintf("[health] %s", check.Name),
+				Classname: "healthchecks",
+				Time:      fmt.Sprintf("%.3f", poll.DurationSeconds),
+				SystemOut: fmt.Sprintf("poll %d at %s", poll.Poll, poll.Time.Format(time.RFC3339)),
+			}
+			if !poll.Passed {
+				testCase.Failure = &healthJUnitFailure{Message: poll.Message, Body: pollFailure(poll)}
+				if poll.Error != "" {
+					testCase.Failure.Message = poll.Error
+				}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+	}
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+// pollFailure lists the unhealthy objects of a failed poll.
+func pollFailure(poll HealthCheckPoll) string {
+	var lines []string
+	for _, object := range poll.Objects {
+		name := object.Name
+		if object.Namespace != "" {
+			name = object.Namespace + "/" + name
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s", object.Kind, name, object.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
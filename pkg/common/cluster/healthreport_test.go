@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+func TestHealthReport(t *testing.T) {
+	viper.Reset()
+	reportDir := t.TempDir()
+	viper.Set(config.ReportDir, reportDir)
+	viper.Set(config.Suffix, "abc")
+
+	recorder := newHealthRecorder("cluster-1", false)
+	recorder.record(nil, errors.New("no kubeconfig yet"))
+	recorder.record([]healthchecks.Result{
+		{Name: "node", Passed: true, Duration: time.Second},
+		{Name: "operator", Err: errors.New("operators not ready"), Objects: []healthchecks.Object{
+			{Kind: "ClusterOperator", Name: "ingress", Reason: "Degraded=True: no routers"},
+		}},
+	}, nil)
+	recorder.record([]healthchecks.Result{
+		{Name: "node", Passed: true},
+		{Name: "operator", Passed: true},
+	}, nil)
+	recorder.finish(true)
+	if err := recorder.write(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportDir, healthReportFile))
+	if err != nil {
+		t.Fatalf("expected a health report: %v", err)
+	}
+	var report HealthReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("expected JSON, got %v", err)
+	}
+	if !report.Healthy || report.Polls != 3 || len(report.Errors) != 1 || report.TimeToHealthySeconds <= 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if len(report.Checks) != 2 {
+		t.Fatalf("expected 2 checks, got %+v", report.Checks)
+	}
+	operator := report.Checks[1]
+	if operator.Name != "operator" || operator.Failed != 1 || operator.Passed != 1 || !operator.LastPassed {
+		t.Errorf("unexpected operator check %+v", operator)
+	}
+	if failed := operator.Polls[0]; failed.Poll != 2 || failed.Error != "operators not ready" || len(failed.Objects) != 1 || failed.Objects[0].Name != "ingress" {
+		t.Errorf("expected the failed poll with the degraded operator, got %+v", failed)
+	}
+
+	junit, err := os.ReadFile(filepath.Join(reportDir, "junit_health_abc.xml"))
+	if err != nil {
+		t.Fatalf("expected a junit file: %v", err)
+	}
+	if strings.Count(string(junit), "<testcase ") != 4 || strings.Count(string(junit), "<failure ") != 1 {
+		t.Errorf("expected a testcase per check per poll and one failure, got:\n%s", junit)
+	}
+	if !strings.Contains(string(junit), "ClusterOperator ingress: Degraded=True: no routers") {
+		t.Errorf("expected the failure to list the degraded operator, got:\n%s", junit)
+	}
+}
+
+func TestHealthReportWithoutReportDir(t *testing.T) {
+	viper.Reset()
+	recorder := newHealthRecorder("cluster-1", true)
+	recorder.finish(false)
+	if err := recorder.write(); err != nil {
+		t.Errorf("expected nothing to be written, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
+)
+
+// HealthStatus is the outcome of running the health checks of a cluster once.
+type HealthStatus struct {
+	ClusterID string    `json:"clusterID"`
+	Time      time.Time `json:"time"`
+	Healthy   bool      `json:"healthy"`
+	// Error is why the checks couldn't run
+	Error  string              `json:"error,omitempty"`
+	Checks []HealthCheckStatus `json:"checks"`
+}
+
+// HealthCheckStatus is the outcome of one check.
+type HealthCheckStatus struct {
+	Name            string                `json:"name"`
+	Passed          bool                  `json:"passed"`
+	DurationSeconds float64               `json:"durationSeconds"`
+	Message         string                `json:"message,omitempty"`
+	Error           string                `json:"error,omitempty"`
+	Objects         []healthchecks.Object `json:"objects,omitempty"`
+}
+
+// NewHealthStatus returns the status of the cluster from the results of its checks, or the error
+// they couldn't run with.
+func NewHealthStatus(clusterID string, results []healthchecks.Result, err error) HealthStatus {
+	status := HealthStatus{
+		ClusterID: clusterID,
+		Time:      time.Now().UTC(),
+		Checks:    []HealthCheckStatus{},
+	}
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Healthy, _, _ = summarizeHealth(results)
+	for _, result := range results {
+		check := HealthCheckStatus{
+			Name:            result.Name,
+			Passed:          result.Passed,
+			DurationSeconds: result.Duration.Seconds(),
+			Message:         result.Message,
+			Objects:         result.Objects,
+		}
+		if result.Err != nil {
+			check.Error = result.Err.Error()
+		}
+		status.Checks = append(status.Checks, check)
+	}
+	return status
+}
+
+// WriteHealthStatus writes the status of the cluster in the output format. The table lists every
+// check with the first object it found unhealthy.
+func WriteHealthStatus(w io.Writer, format string, status HealthStatus) error {
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, status)
+	case OutputTable:
+		outcome := "healthy"
+		if status.Error != "" {
+			outcome = "error: " + status.Error
+		} else if !status.Healthy {
+			outcome = "unhealthy"
+		}
+		fmt.Fprintf(w, "Cluster %s at %s: %s\n", orNone(status.ClusterID), status.Time.Format(time.RFC3339), outcome)
+		if len(status.Checks) == 0 {
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CHECK\tSTATUS\tDURATION\tDETAILS")
+		for _, check := range status.Checks {
+			state := "pass"
+			if !check.Passed {
+				state = "FAIL"
+			}
+			duration := time.Duration(check.DurationSeconds * float64(time.Second)).Round(time.Millisecond)
+			fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", check.Name, state, duration, checkDetails(check))
+		}
+		return tw.Flush()
+	}
+	return unknownFormat(format)
+}
+
+// checkDetails summarizes why a check failed on a single line.
+func checkDetails(check HealthCheckStatus) string {
+	if check.Passed {
+		return ""
+	}
+	details := check.Message
+	if check.Error != "" {
+		details = check.Error
+	}
+	if len(check.Objects) > 0 {
+		object := check.Objects[0]
+		name := object.Name
+		if object.Namespace != "" {
+			name = object.Namespace + "/" + name
+		}
+		details = fmt.Sprintf("%s %s: %s", object.Kind, name, object.Reason)
+		if len(check.Objects) > 1 {
+			details += fmt.Sprintf(" (and %d more)", len(check.Objects)-1)
+		}
+	}
+	return strings.Join(strings.Fields(details), " ")
+}
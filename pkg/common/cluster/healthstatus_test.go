@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
+)
+
+func TestWriteHealthStatus(t *testing.T) {
+	status := NewHealthStatus("cluster-1", []healthchecks.Result{
+		{Name: "node", Passed: true, Duration: time.Second},
+		{Name: "operator", Err: errors.New("operators not ready"), Objects: []healthchecks.Object{
+			{Kind: "ClusterOperator", Name: "ingress", Reason: "Degraded=True"},
+			{Kind: "ClusterOperator", Name: "dns", Reason: "Available=False"},
+		}},
+	}, nil)
+	if status.Healthy || len(status.Checks) != 2 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	var table bytes.Buffer
+	if err := WriteHealthStatus(&table, OutputTable, status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Cluster cluster-1", "unhealthy", "node ", "pass", "FAIL", "ClusterOperator ingress: Degraded=True (and 1 more)"} {
+		if !strings.Contains(table.String(), expected) {
+			t.Errorf("expected %q in the table, got:\n%s", expected, table.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteHealthStatus(&out, OutputJSON, status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded HealthStatus
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected JSON, got %v", err)
+	}
+	if decoded.ClusterID != "cluster-1" || decoded.Checks[1].Error != "operators not ready" || len(decoded.Checks[1].Objects) != 2 {
+		t.Errorf("unexpected decoded status %+v", decoded)
+	}
+}
+
+func TestWriteHealthStatusError(t *testing.T) {
+	status := NewHealthStatus("cluster-1", nil, ErrClusterUnreachable)
+	if status.Healthy || status.Error == "" {
+		t.Fatalf("expected an error status, got %+v", status)
+	}
+
+	var table bytes.Buffer
+	if err := WriteHealthStatus(&table, OutputTable, status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(table.String(), "error: "+ErrClusterUnreachable.Error()) {
+		t.Errorf("expected the error in the table, got:\n%s", table.String())
+	}
+
+	if err := WriteHealthStatus(&table, "yaml", status); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
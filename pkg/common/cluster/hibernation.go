@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// IsHibernated returns true if the cluster is hibernating or on its way into or out of hibernation.
+func IsHibernated(state spi.ClusterState) bool {
+	switch state {
+	case spi.ClusterStatePoweringDown, spi.ClusterStateHibernating, spi.ClusterStateResuming:
+		return true
+	}
+	return false
+}
+
+// hibernator returns the hibernation capability of the provider.
+func hibernator(provider spi.Provider) (spi.Hibernator, error) {
+	h, ok := spi.As[spi.Hibernator](provider)
+	if !ok {
+		return nil, spi.NewNotSupportedError(provider.Type(), spi.CapabilityHibernation)
+	}
+	return h, nil
+}
+
+// HibernateCluster hibernates a ready cluster and waits up to the timeout until it's hibernating.
+// A timeout of zero only starts the hibernation.
+func HibernateCluster(ctx context.Context, provider spi.Provider, clusterID string, timeout time.Duration) (*spi.Cluster, error) {
+	h, err := hibernator(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := spi.WithContext(provider).GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+	}
+	switch cluster.State() {
+	case spi.ClusterStateHibernating:
+		log.Printf("Cluster %s is already hibernating", clusterID)
+		return cluster, nil
+	case spi.ClusterStatePoweringDown:
+		// Already on its way
+	default:
+		if err := h.Hibernate(clusterID); err != nil {
+			return nil, fmt.Errorf("error hibernating cluster %s: %w", clusterID, err)
+		}
+	}
+
+	if timeout == 0 {
+		return cluster, nil
+	}
+	return WaitForClusterState(ctx, provider, clusterID, spi.ClusterStateHibernating, timeout)
+}
+
+// ResumeCluster resumes a hibernated cluster and waits up to the timeout until it's ready. A cluster
+// that is still powering down is resumed once it's hibernating. A timeout of zero only starts resuming.
+func ResumeCluster(ctx context.Context, provider spi.Provider, clusterID string, timeout time.Duration) (*spi.Cluster, error) {
+	h, err := hibernator(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := spi.WithContext(provider).GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+	}
+	switch cluster.State() {
+	case spi.ClusterStateReady:
+		log.Printf("Cluster %s is already ready", clusterID)
+		return cluster, nil
+	case spi.ClusterStateResuming:
+		// Already on its way
+	case spi.ClusterStatePoweringDown:
+		if timeout == 0 {
+			return nil, fmt.Errorf("cluster %s is powering down and can only be resumed once it's hibernating", clusterID)
+		}
+		log.Printf("Cluster %s is powering down, waiting until it's hibernating to resume it", clusterID)
+		if _, err := WaitForClusterState(ctx, provider, clusterID, spi.ClusterStateHibernating, timeout); err != nil {
+			return nil, err
+		}
+		fallthrough
+	default:
+		if err := h.Resume(clusterID); err != nil {
+			return nil, fmt.Errorf("error resuming cluster %s: %w", clusterID, err)
+		}
+	}
+
+	if timeout == 0 {
+		return cluster, nil
+	}
+	return WaitForClusterState(ctx, provider, clusterID, spi.ClusterStateReady, timeout)
+}
+
+// WaitForClusterState waits up to the timeout until the cluster is in the state, and fails early if
+// the cluster is in the error state.
+func WaitForClusterState(ctx context.Context, provider spi.Provider, clusterID string, state spi.ClusterState, timeout time.Duration) (*spi.Cluster, error) {
+	log.Printf("Waiting %v for cluster %s to be %s...", timeout, clusterID, state)
+
+	var cluster *spi.Cluster
+	err := wait.PollUntilContextTimeout(ctx, provisioningPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		cluster, err = spi.WithContext(provider).GetCluster(ctx, clusterID)
+		if err != nil {
+			log.Printf("Error fetching cluster details from provider: %s", err)
+			return false, nil
+		}
+		if cluster.State() == spi.ClusterStateError {
+			return false, fmt.Errorf("cluster %s is in error state", clusterID)
+		}
+		return cluster.State() == state, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s never became %s: %w", clusterID, state, err)
+	}
+	return cluster, nil
+}
@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/fakeprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+func TestHibernateAndResumeCluster(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("long-lived", "long-lived", "4.16.3", spi.ClusterStateReady)
+
+	cluster, err := HibernateCluster(context.Background(), provider, "long-lived", time.Second)
+	if err != nil || cluster.State() != spi.ClusterStateHibernating {
+		t.Fatalf("expected the cluster to be hibernating, got %v (%v)", cluster, err)
+	}
+
+	cluster, err = ResumeCluster(context.Background(), provider, "long-lived", time.Second)
+	if err != nil || cluster.State() != spi.ClusterStateReady {
+		t.Fatalf("expected the cluster to be ready, got %v (%v)", cluster, err)
+	}
+	if !provider.Called("Hibernate") || !provider.Called("Resume") {
+		t.Errorf("expected the cluster to be hibernated and resumed, got calls %v", provider.Calls())
+	}
+}
+
+func TestResumeClusterPoweringDown(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("powering-down", "powering-down", "4.16.3", spi.ClusterStatePoweringDown)
+	if err := provider.Script("powering-down", spi.ClusterStatePoweringDown, spi.ClusterStateHibernating); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := ResumeCluster(context.Background(), provider, "powering-down", 0); err == nil {
+		t.Errorf("expected a cluster that is powering down not to be resumed without waiting")
+	}
+	cluster, err := ResumeCluster(context.Background(), provider, "powering-down", time.Second)
+	if err != nil || cluster.State() != spi.ClusterStateReady {
+		t.Fatalf("expected the cluster to be resumed once hibernating, got %v (%v)", cluster, err)
+	}
+}
+
+func TestHibernateNotSupported(t *testing.T) {
+	provider := spi.Complete(&lifecycleOnly{setupFakeProvider(t)})
+
+	if _, err := HibernateCluster(context.Background(), provider, "any", 0); !errors.Is(err, spi.ErrNotSupported) {
+		t.Errorf("expected hibernation to be not supported, got %v", err)
+	}
+}
+
+// lifecycleOnly hides every capability of the fake provider but Lifecycle.
+type lifecycleOnly struct {
+	spi.Lifecycle
+}
+
+func TestProvisionOrReuseClusterResumesHibernated(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("hibernating", "hibernating", "4.16.3", spi.ClusterStateHibernating)
+	viper.Set(config.Cluster.ID, "hibernating")
+	viper.Set(config.Kubeconfig.Contents, fakeprovider.DefaultKubeconfig)
+
+	cluster, err := ProvisionOrReuseCluster(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("unexpected error reusing cluster: %v", err)
+	}
+	if cluster.State() != spi.ClusterStateReady || !provider.Called("Resume") {
+		t.Errorf("expected the hibernating cluster to be resumed, got %s", cluster.State())
+	}
+}
@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+const (
+	// OutputTable writes clusters as human readable tables.
+	OutputTable = "table"
+
+	// OutputJSON writes clusters as indented JSON.
+	OutputJSON = "json"
+)
+
+// OutputFormats are the formats clusters can be written in.
+var OutputFormats = []string{OutputTable, OutputJSON}
+
+// Summary is the serializable view of a cluster.
+type Summary struct {
+	ID                  string            `json:"id"`
+	Name                string            `json:"name"`
+	State               spi.ClusterState  `json:"state"`
+	Version             string            `json:"version"`
+	Product             string            `json:"product"`
+	CloudProvider       string            `json:"cloudProvider"`
+	Region              string            `json:"region"`
+	Flavour             string            `json:"flavour,omitempty"`
+	NumComputeNodes     int               `json:"numComputeNodes"`
+	CreationTimestamp   time.Time         `json:"creationTimestamp"`
+	ExpirationTimestamp *time.Time        `json:"expirationTimestamp,omitempty"`
+	Addons              []string          `json:"addons,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+	ProvisionError      string            `json:"provisionError,omitempty"`
+}
+
+// Summarize returns the serializable view of the cluster.
+func Summarize(cluster *spi.Cluster) Summary {
+	summary := Summary{
+		ID:                cluster.ID(),
+		Name:              cluster.Name(),
+		State:             cluster.State(),
+		Version:           cluster.Version(),
+		Product:           cluster.Product(),
+		CloudProvider:     cluster.CloudProvider(),
+		Region:            cluster.Region(),
+		Flavour:           cluster.Flavour(),
+		NumComputeNodes:   cluster.NumComputeNodes(),
+		CreationTimestamp: cluster.CreationTimestamp(),
+		Addons:            cluster.Addons(),
+		Properties:        cluster.Properties(),
+		ProvisionError:    cluster.ProvisionError(),
+	}
+	// Clusters without an expiration have a zero or epoch timestamp
+	if expiration := cluster.ExpirationTimestamp(); expiration.Year() >= 2000 {
+		summary.ExpirationTimestamp = &expiration
+	}
+	return summary
+}
+
+// WriteClusters writes one line per cluster, sorted by name, in the output format.
+func WriteClusters(w io.Writer, format string, clusters []*spi.Cluster) error {
+	summaries := make([]Summary, 0, len(clusters))
+	for _, cluster := range clusters {
+		summaries = append(summaries, Summarize(cluster))
+	}
+	sort.SliceStable(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, summaries)
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tSTATE\tVERSION\tPRODUCT\tCLOUD\tREGION\tEXPIRES")
+		for _, s := range summaries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.Name, s.State, s.Version, s.Product, s.CloudProvider, s.Region, expires(s))
+		}
+		return tw.Flush()
+	}
+	return unknownFormat(format)
+}
+
+// WriteCluster writes the details of a cluster in the output format.
+func WriteCluster(w io.Writer, format string, cluster *spi.Cluster) error {
+	s := Summarize(cluster)
+
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, s)
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "ID:\t%s\n", s.ID)
+		fmt.Fprintf(tw, "Name:\t%s\n", s.Name)
+		fmt.Fprintf(tw, "State:\t%s\n", s.State)
+		fmt.Fprintf(tw, "Version:\t%s\n", s.Version)
+		fmt.Fprintf(tw, "Product:\t%s\n", s.Product)
+		fmt.Fprintf(tw, "Cloud provider:\t%s\n", s.CloudProvider)
+		fmt.Fprintf(tw, "Region:\t%s\n", s.Region)
+		if s.Flavour != "" {
+			fmt.Fprintf(tw, "Flavour:\t%s\n", s.Flavour)
+		}
+		fmt.Fprintf(tw, "Compute nodes:\t%d\n", s.NumComputeNodes)
+		fmt.Fprintf(tw, "Created:\t%s\n", s.CreationTimestamp.UTC().Format(time.RFC3339))
+		fmt.Fprintf(tw, "Expires:\t%s\n", expires(s))
+		if len(s.Addons) > 0 {
+			fmt.Fprintf(tw, "Addons:\t%s\n", strings.Join(s.Addons, ", "))
+		}
+		if s.ProvisionError != "" {
+			fmt.Fprintf(tw, "Provision error:\t%s\n", s.ProvisionError)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if len(s.Properties) == 0 {
+			return nil
+		}
+		fmt.Fprintln(w, "Properties:")
+		return writePropertiesTable(w, s.Properties)
+	}
+	return unknownFormat(format)
+}
+
+// WriteProperties writes the properties of a cluster, sorted by name, in the output format.
+func WriteProperties(w io.Writer, format string, properties map[string]string) error {
+	if properties == nil {
+		properties = map[string]string{}
+	}
+
+	switch format {
+	case OutputJSON:
+		return writeJSON(w, properties)
+	case OutputTable:
+		return writePropertiesTable(w, properties)
+	}
+	return unknownFormat(format)
+}
+
+func writePropertiesTable(w io.Writer, properties map[string]string) error {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROPERTY\tVALUE")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\n", name, properties[name])
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func expires(s Summary) string {
+	if s.ExpirationTimestamp == nil {
+		return "never"
+	}
+	return s.ExpirationTimestamp.UTC().Format(time.RFC3339)
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(OutputFormats, ", "))
+}
@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+func outputClusters() []*spi.Cluster {
+	expiration := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	return []*spi.Cluster{
+		spi.NewClusterBuilder().ID("2").Name("osde2e-b").State(spi.ClusterStateHibernating).Version("4.16.3").
+			Product("osd").CloudProvider("aws").Region("us-east-1").Build(),
+		spi.NewClusterBuilder().ID("1").Name("osde2e-a").State(spi.ClusterStateReady).Version("4.17.0").
+			Product("rosa").CloudProvider("aws").Region("us-west-2").ExpirationTimestamp(expiration).
+			Properties(map[string]string{"MadeByOSDe2e": "true", "Status": "healthy"}).Build(),
+	}
+}
+
+func TestWriteClustersTable(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteClusters(&out, OutputTable, outputClusters()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 clusters, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "osde2e-a") || !strings.Contains(lines[2], "osde2e-b") {
+		t.Errorf("expected the clusters sorted by name, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "2026-10-19T12:00:00Z") || !strings.Contains(lines[2], "never") {
+		t.Errorf("expected the expirations, got:\n%s", out.String())
+	}
+}
+
+func TestWriteClustersJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteClusters(&out, OutputJSON, outputClusters()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var summaries []Summary
+	if err := json.Unmarshal(out.Bytes(), &summaries); err != nil {
+		t.Fatalf("expected JSON, got %v:\n%s", err, out.String())
+	}
+	if len(summaries) != 2 || summaries[0].ID != "1" || summaries[0].Properties["Status"] != "healthy" {
+		t.Errorf("unexpected summaries %+v", summaries)
+	}
+	if summaries[0].ExpirationTimestamp == nil || summaries[1].ExpirationTimestamp != nil {
+		t.Errorf("expected only the first cluster to expire, got %+v", summaries)
+	}
+}
+
+func TestWriteClusterTable(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteCluster(&out, OutputTable, outputClusters()[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Name:", "osde2e-a", "Properties:", "MadeByOSDe2e", "healthy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the details to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := WriteProperties(&bytes.Buffer{}, "yaml", nil); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,331 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// FailureCategory is the kind of a provisioning failure.
+type FailureCategory string
+
+const (
+	// FailurePermissions is a failure of the credentials or roles used to install the cluster.
+	FailurePermissions FailureCategory = "permissions"
+	// FailureThrottling is a failure of a cloud or provider API rate limiting its callers.
+	FailureThrottling FailureCategory = "throttling"
+	// FailureQuota is a failure of a lack of quota, e.g. of the organization or the cloud account.
+	FailureQuota FailureCategory = "quota"
+	// FailureCapacity is a failure of a lack of capacity for the machine type in the region.
+	FailureCapacity FailureCategory = "capacity"
+	// FailureInvalidConfig is a failure of an invalid install configuration.
+	FailureInvalidConfig FailureCategory = "invalid-config"
+	// FailureTimeout is an install that didn't finish in time.
+	FailureTimeout FailureCategory = "timeout"
+	// FailureUnknown is any other failure.
+	FailureUnknown FailureCategory = "unknown"
+)
+
+// ErrClusterInstallFailed is returned by WaitForOCMProvisioning when the provider reports the install failed.
+var ErrClusterInstallFailed = errors.New("cluster install failed")
+
+// provisionRetryDelay is how long to wait before provisioning again after a transient failure.
+var provisionRetryDelay = time.Minute
+
+// ProvisionError is a provisioning failure classified by ClassifyProvisionFailure.
+type ProvisionError struct {
+	Category FailureCategory
+	// Retryable is whether the failure is transient, so that provisioning again may succeed.
+	Retryable bool
+	// Evidence is what the failure was classified by, e.g. a provider error code or a line of the install logs.
+	Evidence string
+	Err      error
+}
+
+func (e *ProvisionError) Error() string {
+	return fmt.Sprintf("%v (%s failure)", e.Err, e.Category)
+}
+
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// failureRule classifies the failures that mention one of its patterns, matched case-insensitively.
+type failureRule struct {
+	category  FailureCategory
+	retryable bool
+	patterns  []string
+}
+
+// failureRules are checked in order. Permission failures come first since no retry fixes them,
+// whatever else went wrong, and throttling comes before quota since AWS reports it as RequestLimitExceeded.
+// Quota and capacity failures are only matched by the error codes of the clouds, as their wording
+// also shows up in logs of installs that went fine.
+var failureRules = []failureRule{
+	{FailurePermissions, false, []string{"AccessDenied", "UnauthorizedOperation", "AuthFailure", "AuthorizationFailed", "InvalidClientTokenId", "not authorized to perform"}},
+	{FailureThrottling, true, []string{"Throttling", "RequestLimitExceeded", "TooManyRequests", "Rate exceeded", "rateLimitExceeded", "'429'"}},
+	{FailureQuota, true, []string{"QuotaExceeded", "QUOTA_EXCEEDED", "VcpuLimitExceeded", "InstanceLimitExceeded", "AddressLimitExceeded", "VpcLimitExceeded", "NatGatewayLimitExceeded"}},
+	{FailureCapacity, true, []string{"InsufficientInstanceCapacity", "ZONE_RESOURCE_POOL_EXHAUSTED", "SkuNotAvailable", "AllocationFailed"}},
+	{FailureInvalidConfig, false, []string{"InvalidParameter", "ValidationError", "is not a valid", "CLUSTERS-MGMT-400"}},
+}
+
+// ClassifyProvisionFailure classifies a provisioning error by the error codes and messages of the
+// provider in the error and the install logs. Failures that match no rule are not retryable.
+func ClassifyProvisionFailure(err error, logs map[string][]byte) *ProvisionError {
+	var classified *ProvisionError
+	if errors.As(err, &classified) {
+		return classified
+	}
+	if errors.Is(err, ocmprovider.ErrNotEnoughQuota) {
+		return &ProvisionError{Category: FailureQuota, Retryable: true, Evidence: "quota check", Err: err}
+	}
+
+	names := make([]string, 0, len(logs))
+	for name := range logs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, rule := range failureRules {
+		for _, pattern := range rule.patterns {
+			if containsFold(err.Error(), pattern) {
+				return &ProvisionError{Category: rule.category, Retryable: rule.retryable, Evidence: pattern, Err: err}
+			}
+			for _, name := range names {
+				if line, found := findLine(logs[name], pattern); found {
+					return &ProvisionError{Category: rule.category, Retryable: rule.retryable, Evidence: name + ": " + line, Err: err}
+				}
+			}
+		}
+	}
+
+	if !errors.Is(err, context.Canceled) && wait.Interrupted(err) {
+		return &ProvisionError{Category: FailureTimeout, Err: err}
+	}
+	return &ProvisionError{Category: FailureUnknown, Err: err}
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// findLine returns the first line of the log that contains the pattern, shortened to a readable length.
+func findLine(log []byte, pattern string) (string, bool) {
+	const maxLength = 200
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); containsFold(line, pattern) {
+			if len(line) > maxLength {
+				line = line[:maxLength] + "..."
+			}
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// provisionWithRetries launches the cluster and waits for it to be provisioned. Failures classified
+// as transient are retried with a new cluster up to Cluster.ProvisionRetries times, and every attempt
+// is recorded for the run manifest.
+func provisionWithRetries(ctx context.Context, provider spi.Provider) (*spi.Cluster, error) {
+	retries := viper.GetInt(config.Cluster.ProvisionRetries)
+	if viper.GetString(config.Cluster.ID) != "" {
+		// A cluster that was given rather than launched can't be provisioned again
+		retries = 0
+	}
+
+	tried := &placements{regions: map[string]bool{}, machineTypes: map[string]bool{}}
+	for attempt := 1; ; attempt++ {
+		record := runmanifest.Provision{
+			Attempt:     attempt,
+			StartedAt:   time.Now(),
+			Region:      viper.GetString(config.CloudProvider.Region),
+			MachineType: machineType(provider),
+		}
+		tried.regions[record.Region] = true
+		tried.machineTypes[record.MachineType] = true
+
+		cluster, err := provisionAttempt(ctx, provider)
+		record.ClusterID = viper.GetString(config.Cluster.ID)
+		record.DurationSeconds = time.Since(record.StartedAt).Seconds()
+		if errors.Is(err, ErrReserveFull) {
+			return nil, err
+		}
+		if err == nil {
+			record.Passed = true
+			runmanifest.RecordProvision(record)
+			return cluster, nil
+		}
+
+		failure := ClassifyProvisionFailure(err, installLogs(provider, record.ClusterID))
+		record.FailureCategory = string(failure.Category)
+		record.Retryable = failure.Retryable
+		record.Error = err.Error()
+		runmanifest.RecordProvision(record)
+		log.Printf("Provisioning attempt %d failed with a %s failure (retryable: %t, evidence: %q): %v", attempt, failure.Category, failure.Retryable, failure.Evidence, err)
+
+		if !failure.Retryable || attempt > retries || ctx.Err() != nil {
+			return nil, failure
+		}
+
+		discardFailedCluster(provider, record.ClusterID)
+		if failure.Category == FailureQuota || failure.Category == FailureCapacity {
+			rotatePlacement(provider, tried)
+		}
+		log.Printf("Retrying provisioning in %v (retry %d of %d)", provisionRetryDelay, attempt, retries)
+		if err := util.Sleep(ctx, provisionRetryDelay); err != nil {
+			return nil, failure
+		}
+	}
+}
+
+// provisionAttempt launches or claims a cluster and waits until it's provisioned.
+func provisionAttempt(ctx context.Context, provider spi.Provider) (*spi.Cluster, error) {
+	cluster, err := ProvisionCluster(nil)
+	if err != nil {
+		if errors.Is(err, ErrReserveFull) {
+			log.Printf("Reserve full, exiting without provisioning")
+		}
+		return nil, fmt.Errorf("failed to set up or retrieve cluster: %w", err)
+	}
+
+	viper.Set(config.Cluster.ID, cluster.ID())
+	log.Printf("CLUSTER_ID set to %s from OCM.", viper.GetString(config.Cluster.ID))
+	_, err = WaitForOCMProvisioning(ctx, provider, viper.GetString(config.Cluster.ID), nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("cluster never became ready: %w", err)
+	}
+	return cluster, nil
+}
+
+// installLogs returns the install logs of the cluster for classifying its failure, if there are any.
+func installLogs(provider spi.Provider, clusterID string) map[string][]byte {
+	if clusterID == "" {
+		return nil
+	}
+	logs, err := provider.Logs(clusterID)
+	if err != nil {
+		log.Printf("Error getting the install logs of cluster %s: %v", clusterID, err)
+	}
+	return logs
+}
+
+// discardFailedCluster deletes the cluster of a failed attempt and picks a new name for the next one,
+// since the failed cluster keeps its name until it's deleted.
+func discardFailedCluster(provider spi.Provider, clusterID string) {
+	if clusterID != "" {
+		if err := provider.DeleteCluster(clusterID); err != nil {
+			log.Printf("Error deleting failed cluster %s: %v", clusterID, err)
+		} else {
+			log.Printf("Deleting failed cluster %s", clusterID)
+		}
+	}
+	viper.Set(config.Cluster.ID, "")
+	viper.Set(config.Cluster.Name, "osde2e-"+util.RandomStr(5))
+}
+
+// placements are the regions and machine types that provisioning was attempted with.
+type placements struct {
+	regions      map[string]bool
+	machineTypes map[string]bool
+}
+
+// rotationAttempts is how many random regions or machine types are picked looking for an untried one.
+const rotationAttempts = 5
+
+// machineTypeKeys are the config keys the providers read the compute machine type of a new cluster from.
+var machineTypeKeys = map[string]string{
+	"ocm":  ocmprovider.ComputeMachineType,
+	"rosa": rosaprovider.ComputeMachineType,
+}
+
+// machineType returns the compute machine type new clusters of the provider are launched with.
+func machineType(provider spi.Provider) string {
+	if key, ok := machineTypeKeys[provider.Type()]; ok {
+		return viper.GetString(key)
+	}
+	return ""
+}
+
+// rotatePlacement picks an untried region or machine type for the next attempt, as configured with
+// Cluster.ProvisionRetryRotate, after a failure that another placement may avoid.
+func rotatePlacement(provider spi.Provider, tried *placements) {
+	cloudProvider := viper.GetString(config.CloudProvider.CloudProviderID)
+	for _, rotation := range strings.Split(viper.GetString(config.Cluster.ProvisionRetryRotate), ",") {
+		switch strings.TrimSpace(rotation) {
+		case "":
+		case "region":
+			chooser, ok := spi.As[spi.RegionChooser](provider)
+			if !ok {
+				log.Printf("The %s provider doesn't support choosing another region", provider.Type())
+				continue
+			}
+			previous := viper.GetString(config.CloudProvider.Region)
+			region, err := pickUntried(tried.regions, func() (string, error) {
+				viper.Set(config.CloudProvider.Region, "random")
+				return chooser.DetermineRegion(cloudProvider)
+			})
+			if err != nil {
+				log.Printf("Keeping region %s: %v", previous, err)
+				region = previous
+			} else {
+				log.Printf("Retrying in region %s instead of %s", region, previous)
+			}
+			viper.Set(config.CloudProvider.Region, region)
+		case "machineType":
+			key, ok := machineTypeKeys[provider.Type()]
+			if !ok {
+				log.Printf("The %s provider doesn't support choosing another machine type", provider.Type())
+				continue
+			}
+			previous := viper.GetString(key)
+			// The ROSA provider picks machine types through the OCM one, so the pick goes through the
+			// OCM key, which is restored afterwards
+			ocmMachineType := viper.GetString(ocmprovider.ComputeMachineType)
+			machineType, err := pickUntried(tried.machineTypes, func() (string, error) {
+				viper.Set(ocmprovider.ComputeMachineType, "random")
+				return provider.DetermineMachineType(cloudProvider)
+			})
+			viper.Set(ocmprovider.ComputeMachineType, ocmMachineType)
+			if err != nil {
+				log.Printf("Keeping machine type %q: %v", previous, err)
+				machineType = previous
+			} else {
+				log.Printf("Retrying with machine type %q instead of %q", machineType, previous)
+			}
+			viper.Set(key, machineType)
+		default:
+			log.Printf("Unknown provisioning retry rotation %q", rotation)
+		}
+	}
+}
+
+// pickUntried picks values until one isn't in tried.
+func pickUntried(tried map[string]bool, pick func() (string, error)) (string, error) {
+	for i := 0; i < rotationAttempts; i++ {
+		value, err := pick()
+		if err != nil {
+			return "", err
+		}
+		if !tried[value] {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("no untried choice found in %d picks", rotationAttempts)
+}
@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/fakeprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+func TestClassifyProvisionFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		logs      map[string][]byte
+		category  FailureCategory
+		retryable bool
+	}{
+		{"quota", fmt.Errorf("could not launch cluster: %w", ocmprovider.ErrNotEnoughQuota), nil, FailureQuota, true},
+		{"quota code", errors.New("api error: VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit"), nil, FailureQuota, true},
+		{"quota wording", ErrClusterInstallFailed, map[string][]byte{
+			"install": []byte("level=info msg=Checking quota exceeded thresholds\nlevel=info msg=Install complete\n"),
+		}, FailureUnknown, false},
+		{"throttling", errors.New("api error: RequestLimitExceeded: Request limit exceeded."), nil, FailureThrottling, true},
+		{"capacity in logs", ErrClusterInstallFailed, map[string][]byte{
+			"install": []byte("level=info msg=Creating infrastructure\nlevel=error msg=InsufficientInstanceCapacity: We currently do not have sufficient m5.xlarge capacity\n"),
+		}, FailureCapacity, true},
+		{"permissions before throttling", errors.New("Throttling: AccessDenied for role"), nil, FailurePermissions, false},
+		{"invalid config", errors.New("api error: Version 'openshift-v3.11' is not a valid version"), nil, FailureInvalidConfig, false},
+		{"timeout", fmt.Errorf("cluster never became ready: %w", context.DeadlineExceeded), nil, FailureTimeout, false},
+		{"canceled", fmt.Errorf("cluster never became ready: %w", context.Canceled), nil, FailureUnknown, false},
+		{"unknown", errors.New("something broke"), nil, FailureUnknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := ClassifyProvisionFailure(tt.err, tt.logs)
+			if failure.Category != tt.category || failure.Retryable != tt.retryable {
+				t.Errorf("expected a %s failure (retryable: %t), got %s (retryable: %t)", tt.category, tt.retryable, failure.Category, failure.Retryable)
+			}
+			if !errors.Is(failure, tt.err) {
+				t.Errorf("expected the failure to wrap %v", tt.err)
+			}
+		})
+	}
+}
+
+func setupProvisionRetries(t *testing.T, retries int) *fakeprovider.FakeProvider {
+	t.Helper()
+	provider := setupFakeProvider(t)
+	viper.Set(config.Cluster.ProvisionRetries, retries)
+
+	delay := provisionRetryDelay
+	provisionRetryDelay = time.Millisecond
+	t.Cleanup(func() { provisionRetryDelay = delay })
+	runmanifest.Reset()
+	t.Cleanup(runmanifest.Reset)
+	return provider
+}
+
+func launches(provider *fakeprovider.FakeProvider) int {
+	count := 0
+	for _, call := range provider.Calls() {
+		if call == "LaunchCluster" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestProvisionRetriesTransientFailure(t *testing.T) {
+	provider := setupProvisionRetries(t, 1)
+	provider.FailNext("LaunchCluster", errors.New("api error: Throttling: Rate exceeded"))
+
+	if _, err := ProvisionOrReuseCluster(context.Background(), provider); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+
+	provisions := runmanifest.Provisions()
+	if len(provisions) != 2 {
+		t.Fatalf("expected 2 provisioning attempts to be recorded, got %+v", provisions)
+	}
+	if provisions[0].Passed || provisions[0].FailureCategory != string(FailureThrottling) || !provisions[0].Retryable {
+		t.Errorf("expected the first attempt to fail with throttling, got %+v", provisions[0])
+	}
+	if !provisions[1].Passed || provisions[1].Attempt != 2 {
+		t.Errorf("expected the second attempt to pass, got %+v", provisions[1])
+	}
+}
+
+func TestProvisionRetriesExhausted(t *testing.T) {
+	provider := setupProvisionRetries(t, 1)
+	provider.WithLaunchScript(spi.ClusterStateInstalling, spi.ClusterStateError).
+		WithProvisionError("OCM3055: InsufficientInstanceCapacity in us-east-1a")
+
+	_, err := ProvisionOrReuseCluster(context.Background(), provider)
+	var failure *ProvisionError
+	if !errors.As(err, &failure) || failure.Category != FailureCapacity {
+		t.Fatalf("expected a capacity failure, got %v", err)
+	}
+	if !errors.Is(err, ErrClusterInstallFailed) {
+		t.Errorf("expected the install failure to be wrapped, got %v", err)
+	}
+	if launches(provider) != 2 || !provider.Called("DeleteCluster") {
+		t.Errorf("expected a second cluster after deleting the failed one, got calls %v", provider.Calls())
+	}
+	if len(runmanifest.Provisions()) != 2 {
+		t.Errorf("expected both attempts to be recorded, got %+v", runmanifest.Provisions())
+	}
+}
+
+func TestProvisionDoesNotRetryPermanentFailure(t *testing.T) {
+	provider := setupProvisionRetries(t, 2)
+	provider.WithLaunchScript(spi.ClusterStateInstalling, spi.ClusterStateError).
+		WithLogs(map[string][]byte{"install": []byte("level=fatal msg=UnauthorizedOperation: You are not authorized to perform this operation\n")})
+
+	_, err := ProvisionOrReuseCluster(context.Background(), provider)
+	var failure *ProvisionError
+	if !errors.As(err, &failure) || failure.Category != FailurePermissions || failure.Retryable {
+		t.Fatalf("expected a permissions failure, got %v", err)
+	}
+	if launches(provider) != 1 {
+		t.Errorf("expected no retry, got calls %v", provider.Calls())
+	}
+}
+
+// rosaProvider is a fake provider posing as the ROSA one.
+type rosaProvider struct {
+	*fakeprovider.FakeProvider
+}
+
+func (p *rosaProvider) Type() string {
+	return "rosa"
+}
+
+func TestRotatePlacementMachineType(t *testing.T) {
+	provider := &rosaProvider{setupFakeProvider(t)}
+	viper.Set(config.Cluster.ProvisionRetryRotate, "machineType")
+	viper.Set(rosaprovider.ComputeMachineType, "m5.xlarge")
+	viper.Set(ocmprovider.ComputeMachineType, "")
+	t.Cleanup(func() {
+		viper.Set(config.Cluster.ProvisionRetryRotate, "")
+		viper.Set(rosaprovider.ComputeMachineType, "")
+	})
+
+	rotatePlacement(provider, &placements{regions: map[string]bool{}, machineTypes: map[string]bool{"m5.xlarge": true}})
+	if machineType := viper.GetString(rosaprovider.ComputeMachineType); machineType != "fake.large" {
+		t.Errorf("expected the ROSA machine type to be rotated, got %q", machineType)
+	}
+	if machineType := viper.GetString(ocmprovider.ComputeMachineType); machineType != "" {
+		t.Errorf("expected the OCM machine type to be left alone, got %q", machineType)
+	}
+}
@@ -0,0 +1,263 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+const (
+	// timelineFile is the name of the file the transitions of a provisioning cluster are written to.
+	timelineFile = "timeline.log"
+
+	// snapshotTimeFormat prefixes the names of the snapshot files, so they sort in the order they were taken.
+	snapshotTimeFormat = "20060102T150405.000Z"
+)
+
+// provisioningWatcher captures the progression of a provisioning cluster to the report directory, so
+// a failed install can be followed from the start instead of only from the final logs. It keeps a
+// timeline of the cluster state, status and inflight check transitions, appends what each install
+// log gained to a file per log, indexed by the timeline, and snapshots the inflight checks each time
+// they change.
+//
+// The methods of a nil watcher do nothing, so callers don't need to check whether watching is enabled.
+type provisioningWatcher struct {
+	provider  spi.Provider
+	clusterID string
+	dir       string
+	logger    *log.Logger
+
+	mutex    sync.Mutex
+	timeline *os.File
+	state    spi.ClusterState
+	status   string
+	logs     map[string]string
+	// logSizes is how much of each install log file is written
+	logSizes map[string]int
+	checks   map[string]string
+	errors   map[string]string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// watchProvisioning starts capturing the progression of the cluster into the named directory of the
+// report directory every interval. It returns nil if no report directory or interval is configured.
+func watchProvisioning(ctx context.Context, provider spi.Provider, clusterID, name string, logger *log.Logger) *provisioningWatcher {
+	reportDir := viper.GetString(config.ReportDir)
+	interval := time.Duration(viper.GetInt(config.Cluster.ProvisioningWatchIntervalInSeconds)) * time.Second
+	if reportDir == "" || interval <= 0 {
+		return nil
+	}
+
+	dir := filepath.Join(reportDir, name)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		logger.Printf("Not capturing the provisioning progression: %v", err)
+		return nil
+	}
+	timeline, err := os.OpenFile(filepath.Join(dir, timelineFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		logger.Printf("Not capturing the provisioning progression: %v", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &provisioningWatcher{
+		provider:  provider,
+		clusterID: clusterID,
+		dir:       dir,
+		logger:    logger,
+		timeline:  timeline,
+		logs:      map[string]string{},
+		logSizes:  map[string]int{},
+		checks:    map[string]string{},
+		errors:    map[string]string{},
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	logger.Printf("Capturing the provisioning progression of cluster %s to %s every %v", clusterID, dir, interval)
+
+	w.capture(ctx)
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.capture(ctx)
+			}
+		}
+	}()
+	return w
+}
+
+// observe records the transitions of the state and status of the cluster since the last observation.
+func (w *provisioningWatcher) observe(cluster *spi.Cluster) {
+	if w == nil || cluster == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if state := cluster.State(); state != w.state {
+		w.record("cluster state %s -> %s", orNone(string(w.state)), state)
+		w.state = state
+	}
+	if status := cluster.Properties()[clusterproperties.Status]; status != w.status {
+		w.record("cluster status %s -> %s", orNone(w.status), orNone(status))
+		w.status = status
+	}
+}
+
+// stop ends the background captures, takes a final one and closes the timeline.
+func (w *provisioningWatcher) stop(err error) {
+	if w == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+
+	// The context of the wait may be done, so the final capture gets a short one of its own
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	w.capture(ctx)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err != nil {
+		w.record("stopped waiting for the cluster: %v", err)
+	} else {
+		w.record("stopped waiting for the cluster: ready")
+	}
+	if err := w.timeline.Close(); err != nil {
+		w.logger.Printf("Error closing the provisioning timeline: %v", err)
+	}
+}
+
+// capture snapshots the install logs and inflight checks that changed since the last capture.
+func (w *provisioningWatcher) capture(ctx context.Context) {
+	logs, logsErr := spi.WithContext(w.provider).Logs(ctx, w.clusterID)
+
+	var checks []spi.InflightCheck
+	var checksErr error
+	checker, hasChecks := spi.As[spi.InflightChecker](w.provider)
+	if hasChecks {
+		checks, checksErr = checker.InflightChecks(w.clusterID)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	now := time.Now().UTC()
+
+	w.recordError("install logs", logsErr)
+	names := make([]string, 0, len(logs))
+	for name := range logs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content := string(logs[name])
+		previous := w.logs[name]
+		if content == previous {
+			continue
+		}
+		w.logs[name] = content
+		file := name + "-log.txt"
+		added, appended := strings.CutPrefix(content, previous)
+		if !appended {
+			// The log was rewritten rather than appended to, e.g. by a new install attempt, so it's kept whole
+			added = fmt.Sprintf("--- %s rewritten at %s ---\n%s", name, now.Format(time.RFC3339), content)
+		}
+		w.record("install log %s changed (%d bytes): %s at offset %d", name, len(content), file, w.logSizes[name])
+		w.append(file, added)
+		w.logSizes[name] += len(added)
+	}
+
+	w.recordError("inflight checks", checksErr)
+	changed := false
+	for _, check := range checks {
+		if check.State == w.checks[check.Name] {
+			continue
+		}
+		w.record("inflight check %s %s -> %s", check.Name, orNone(w.checks[check.Name]), check.State)
+		w.checks[check.Name] = check.State
+		changed = true
+	}
+	if changed {
+		content, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			w.record("error encoding inflight checks: %v", err)
+			return
+		}
+		w.write(now.Format(snapshotTimeFormat)+"-inflight-checks.json", content)
+	}
+}
+
+// record adds a timestamped line to the timeline. It must be called with the mutex held.
+func (w *provisioningWatcher) record(format string, args ...any) {
+	line := fmt.Sprintf("%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	if _, err := w.timeline.WriteString(line); err != nil {
+		w.logger.Printf("Error writing the provisioning timeline: %v", err)
+	}
+}
+
+// recordError adds an error getting the source to the timeline, unless it's the same error as the
+// last one, so an API that keeps failing doesn't flood the timeline. It must be called with the mutex held.
+func (w *provisioningWatcher) recordError(source string, err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	if message == w.errors[source] {
+		return
+	}
+	if err != nil {
+		w.record("error getting %s: %v", source, err)
+	} else {
+		w.record("getting %s succeeded again", source)
+	}
+	w.errors[source] = message
+}
+
+// append appends content to a file in the watcher's directory.
+func (w *provisioningWatcher) append(name, content string) {
+	f, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err == nil {
+		_, err = f.WriteString(content)
+		err = errors.Join(err, f.Close())
+	}
+	if err != nil {
+		w.logger.Printf("Error writing provisioning log %s: %v", name, err)
+	}
+}
+
+// write writes a snapshot file into the watcher's directory.
+func (w *provisioningWatcher) write(name string, content []byte) {
+	if err := os.WriteFile(filepath.Join(w.dir, name), content, 0o644); err != nil {
+		w.logger.Printf("Error writing provisioning snapshot %s: %v", name, err)
+	}
+}
+
+// orNone names an unset state, status or check state in the timeline.
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
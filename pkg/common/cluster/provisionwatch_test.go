@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/fakeprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// progressingProvider is a fake provider whose install log grows and whose inflight check fails
+// after the first capture.
+type progressingProvider struct {
+	*fakeprovider.FakeProvider
+
+	mutex    sync.Mutex
+	captures int
+}
+
+func (p *progressingProvider) Logs(clusterID string) (map[string][]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.captures++
+	return map[string][]byte{"install": []byte(strings.Repeat("installing\n", p.captures))}, nil
+}
+
+func (p *progressingProvider) InflightChecks(clusterID string) ([]spi.InflightCheck, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	state := "running"
+	if p.captures > 1 {
+		state = "failed"
+	}
+	return []spi.InflightCheck{{Name: "egress", State: state}}, nil
+}
+
+func TestWaitForOCMProvisioningCapturesProgression(t *testing.T) {
+	fake := setupFakeProvider(t)
+	fake.AddCluster("installing", "installing", "4.16.3", spi.ClusterStateInstalling)
+	if err := fake.Script("installing", spi.ClusterStateInstalling, spi.ClusterStateReady); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reportDir := t.TempDir()
+	viper.Set(config.ReportDir, reportDir)
+	viper.Set(config.Cluster.ProvisioningWatchIntervalInSeconds, 60)
+	provider := &progressingProvider{FakeProvider: fake}
+
+	if _, err := WaitForOCMProvisioning(context.Background(), provider, "installing", nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir := filepath.Join(reportDir, "provisioning")
+	timeline, err := os.ReadFile(filepath.Join(dir, timelineFile))
+	if err != nil {
+		t.Fatalf("expected a timeline: %v", err)
+	}
+	for _, transition := range []string{
+		"cluster state (none) -> installing",
+		"cluster state installing -> ready",
+		"inflight check egress (none) -> running",
+		"inflight check egress running -> failed",
+		"install log install changed",
+		"stopped waiting for the cluster: ready",
+	} {
+		if !strings.Contains(string(timeline), transition) {
+			t.Errorf("expected the timeline to contain %q, got:\n%s", transition, timeline)
+		}
+	}
+
+	checks, _ := filepath.Glob(filepath.Join(dir, "*-inflight-checks.json"))
+	if len(checks) != 2 {
+		t.Errorf("expected a snapshot of every change, got checks %v", checks)
+	}
+	// Every capture grew the log by a line, which is appended once
+	installLog, err := os.ReadFile(filepath.Join(dir, "install-log.txt"))
+	if err != nil {
+		t.Fatalf("expected an install log: %v", err)
+	}
+	if captures := strings.Count(string(timeline), "install log install changed"); string(installLog) != strings.Repeat("installing\n", captures) {
+		t.Errorf("expected the %d captures of the log to be appended without repeats, got:\n%s", captures, installLog)
+	}
+	if !strings.Contains(string(timeline), "install-log.txt at offset 11") {
+		t.Errorf("expected the timeline to index the log, got:\n%s", timeline)
+	}
+}
+
+func TestWaitForOCMProvisioningWithoutReportDir(t *testing.T) {
+	provider := setupFakeProvider(t)
+	provider.AddCluster("ready", "ready", "4.16.3", spi.ClusterStateReady)
+	viper.Set(config.Cluster.ProvisioningWatchIntervalInSeconds, 60)
+
+	if _, err := WaitForOCMProvisioning(context.Background(), provider, "ready", nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.Called("Logs") {
+		t.Errorf("expected no logs to be captured without a report directory")
+	}
+}
+
+// rewritingProvider is a fake provider whose install log is replaced by a shorter one.
+type rewritingProvider struct {
+	*fakeprovider.FakeProvider
+
+	log string
+}
+
+func (p *rewritingProvider) Logs(clusterID string) (map[string][]byte, error) {
+	return map[string][]byte{"install": []byte(p.log)}, nil
+}
+
+func TestProvisioningWatcherRewrittenLog(t *testing.T) {
+	fake := setupFakeProvider(t)
+	fake.AddCluster("installing", "installing", "4.16.3", spi.ClusterStateInstalling)
+	viper.Set(config.ReportDir, t.TempDir())
+	viper.Set(config.Cluster.ProvisioningWatchIntervalInSeconds, 60)
+	provider := &rewritingProvider{FakeProvider: fake, log: "attempt 1\nfailed\n"}
+
+	w := watchProvisioning(context.Background(), provider, "installing", "provisioning", log.Default())
+	provider.log = "attempt 2\n"
+	w.stop(nil)
+
+	installLog, err := os.ReadFile(filepath.Join(w.dir, "install-log.txt"))
+	if err != nil {
+		t.Fatalf("expected an install log: %v", err)
+	}
+	if !strings.HasPrefix(string(installLog), "attempt 1\nfailed\n--- install rewritten at ") || !strings.HasSuffix(string(installLog), "---\nattempt 2\n") {
+		t.Errorf("expected both attempts to be kept, got:\n%s", installLog)
+	}
+}
@@ -0,0 +1,194 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	"github.com/openshift/osde2e/pkg/common/prometheus"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/slo"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// sloReportFile is the name of the SLO gate report written to the report directory.
+const sloReportFile = "slo-report.json"
+
+// SLOReport is the outcome of evaluating the SLO assertions once all tests ran.
+type SLOReport struct {
+	ClusterID string    `json:"clusterID"`
+	Time      time.Time `json:"time"`
+	Passed    bool      `json:"passed"`
+	// Skipped is why the SLOs weren't evaluated, a skipped gate neither passes nor fails
+	Skipped string       `json:"skipped,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Results []slo.Result `json:"results"`
+}
+
+// CheckSLOGate evaluates the SLO assertions of the gate stage against the cluster, writes the outcome
+// to the report directory and returns whether the cluster met them. A skipped gate doesn't fail the run.
+func CheckSLOGate(ctx context.Context, clusterID string, logger *log.Logger) (bool, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+	logger.Print("Evaluating the SLO gate...")
+
+	results, skipped, err := evaluateSLOGate(ctx, clusterID, logger)
+	report := SLOReport{
+		ClusterID: clusterID,
+		Time:      time.Now().UTC(),
+		Passed:    err == nil && skipped == "" && slo.Passed(results),
+		Skipped:   skipped,
+		Results:   results,
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if report.Results == nil {
+		report.Results = []slo.Result{}
+	}
+	if writeErr := writeSLOReport(report); writeErr != nil {
+		logger.Printf("Error writing the SLO report: %v", writeErr)
+	}
+
+	switch {
+	case err != nil:
+		return false, err
+	case skipped != "":
+		logger.Printf("Skipping the SLO gate: %s", skipped)
+		return true, nil
+	case !report.Passed:
+		return false, fmt.Errorf("SLOs not met: %s", slo.Summary(results))
+	}
+	return true, nil
+}
+
+// evaluateSLOGate returns the results of the gate, or why it was skipped.
+func evaluateSLOGate(ctx context.Context, clusterID string, logger *log.Logger) ([]slo.Result, string, error) {
+	sloConfig, err := slo.Load(viper.GetString(config.Tests.SLOConfig))
+	if err != nil {
+		return nil, "", err
+	}
+	restConfig, providerType, err := ClusterConfig(clusterID)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting cluster config: %w", err)
+	}
+	if providerType == "local" {
+		return nil, "local clusters don't run cluster monitoring", nil
+	}
+	cluster := slo.Cluster{
+		Provider:    providerType,
+		Environment: viper.GetString(ocmprovider.Env),
+		HCP:         isHCP(providerType),
+	}
+	if !sloConfig.Applies(cluster, slo.StageGate) {
+		return nil, fmt.Sprintf("no SLO assertions apply to %q clusters", providerType), nil
+	}
+
+	client, err := prometheus.CreateClusterClient(ctx, restConfig)
+	if errors.Is(err, prometheus.ErrUnavailable) {
+		return nil, err.Error(), nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating the Prometheus client: %w", err)
+	}
+	return slo.Evaluate(ctx, v1.NewAPI(client), sloConfig, cluster, slo.StageGate, logger), "", nil
+}
+
+// writeSLOReport writes the report as JSON and its results as JUnit testcases to the report
+// directory, if one is configured.
+func writeSLOReport(report SLOReport) error {
+	reportDir := viper.GetString(config.ReportDir)
+	if reportDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(reportDir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding SLO report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, sloReportFile), data, 0o644); err != nil {
+		return fmt.Errorf("error writing SLO report: %w", err)
+	}
+
+	data, err = xml.MarshalIndent(sloJUnit(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding SLO junit: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, SLOJUnitFile()), append([]byte(xml.Header), data...), 0o644); err != nil {
+		return fmt.Errorf("error writing SLO junit: %w", err)
+	}
+	return nil
+}
+
+// SLOJUnitFile is the name of the JUnit file the SLO gate is written to.
+func SLOJUnitFile() string {
+	return fmt.Sprintf("junit_slo_%s.xml", viper.GetString(config.Suffix))
+}
+
+// sloJUnit returns a testcase per result, or a single failed or skipped testcase if the SLOs couldn't
+// be evaluated.
+func sloJUnit(report SLOReport) healthJUnitSuite {
+	suite := healthJUnitSuite{Name: "SLO gate", Time: "0"}
+	if report.Skipped != "" {
+		suite.TestCases = append(suite.TestCases, healthJUnitCase{
+			Name:      "[slo] evaluate",
+			Classname: "slo",
+			Time:      "0",
+			Skipped:   &healthJUnitFailure{Message: report.Skipped},
+		})
+	}
+	if report.Error != "" {
+		suite.TestCases = append(suite.TestCases, healthJUnitCase{
+			Name:      "[slo] evaluate",
+			Classname: "slo",
+			Time:      "0",
+			Failure:   &healthJUnitFailure{Message: "error evaluating the SLOs", Body: report.Error},
+		})
+	}
+	for _, result := range report.Results {
+		testCase := healthJUnitCase{
+			Name:      "[slo] " + result.Name,
+			Classname: "slo",
+			Time:      "0",
+			SystemOut: result.Query,
+		}
+		if !result.Passed {
+			testCase.Failure = &healthJUnitFailure{Message: slo.Summary([]slo.Result{result}), Body: sloFailure(result)}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	for _, testCase := range suite.TestCases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// sloFailure lists why a result failed, a line per violation.
+func sloFailure(result slo.Result) string {
+	if result.Error != "" {
+		return result.Error
+	}
+	var lines []string
+	for _, violation := range result.Violations {
+		lines = append(lines, fmt.Sprintf("%s: %s", violation.Series, violation.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/slo"
+)
+
+func TestWriteSLOReport(t *testing.T) {
+	viper.Reset()
+	reportDir := t.TempDir()
+	viper.Set(config.ReportDir, reportDir)
+	viper.Set(config.Suffix, "abc")
+
+	firing := 1.0
+	err := writeSLOReport(SLOReport{
+		ClusterID: "cluster-1",
+		Results: []slo.Result{
+			{Name: "apiserver-error-rate", Passed: true},
+			{Name: "alerts", Violations: []slo.Violation{
+				{Series: `ALERTS{alertname="KubeAPIDown"}`, Value: &firing, Reason: "critical alert KubeAPIDown is firing"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportDir, sloReportFile))
+	if err != nil {
+		t.Fatalf("expected an SLO report: %v", err)
+	}
+	var report SLOReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("expected JSON, got %v", err)
+	}
+	if report.ClusterID != "cluster-1" || len(report.Results) != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	junit, err := os.ReadFile(filepath.Join(reportDir, "junit_slo_abc.xml"))
+	if err != nil {
+		t.Fatalf("expected a junit file: %v", err)
+	}
+	if strings.Count(string(junit), "<testcase ") != 2 || strings.Count(string(junit), "<failure ") != 1 {
+		t.Errorf("expected a testcase per result and one failure, got:\n%s", junit)
+	}
+	if !strings.Contains(string(junit), "critical alert KubeAPIDown is firing") {
+		t.Errorf("expected the failure to name the alert, got:\n%s", junit)
+	}
+}
+
+func TestSLOJUnitError(t *testing.T) {
+	suite := sloJUnit(SLOReport{Error: "no route to Prometheus"})
+	if suite.Tests != 1 || suite.Failures != 1 || suite.TestCases[0].Failure.Body != "no route to Prometheus" {
+		t.Errorf("expected a single failed testcase with the error, got %+v", suite)
+	}
+}
+
+func TestSLOJUnitSkipped(t *testing.T) {
+	suite := sloJUnit(SLOReport{Skipped: "cluster monitoring is unavailable"})
+	if suite.Tests != 1 || suite.Failures != 0 || suite.Skipped != 1 || suite.TestCases[0].Skipped.Message != "cluster monitoring is unavailable" {
+		t.Errorf("expected a single skipped testcase with the reason, got %+v", suite)
+	}
+}
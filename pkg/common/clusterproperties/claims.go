@@ -0,0 +1,13 @@
+package clusterproperties
+
+import "time"
+
+// LeaseExpired returns true if the claim lease in the cluster properties lapsed before now.
+// Claims without a readable lease count as expired, as nothing would ever release them otherwise.
+func LeaseExpired(properties map[string]string, now time.Time) bool {
+	expiry, err := time.Parse(time.RFC3339, properties[ClaimLeaseExpiry])
+	if err != nil {
+		return true
+	}
+	return expiry.Before(now)
+}
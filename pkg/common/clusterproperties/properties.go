@@ -43,4 +43,10 @@ const (
 
 	// Used represents the availability when a test job is finished on a cluster
 	Used = "used"
+
+	// ClaimToken identifies the test job holding the claim on a reserved cluster
+	ClaimToken = "ClaimToken"
+
+	// ClaimLeaseExpiry is when the claim on a reserved cluster lapses, in RFC 3339 format
+	ClaimLeaseExpiry = "ClaimLeaseExpiry"
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,8 +26,84 @@ type Secret struct {
 type TestSuite struct {
 	Image        string `yaml:"image" json:"image" mapstructure:"image"`
 	SlackChannel string `yaml:"slackChannel,omitempty" json:"slackChannel,omitempty" mapstructure:"slackChannel,omitempty"`
+	// Retry controls how the suite is retried when it fails. Suites are not retried by default.
+	Retry RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty" mapstructure:"retry,omitempty"`
+	// Quarantine lists the names of known flaky tests. Their failures are reported as informing and don't fail the run.
+	Quarantine []string `yaml:"quarantine,omitempty" json:"quarantine,omitempty" mapstructure:"quarantine,omitempty"`
+	// Pod customizes the pod the suite runs in, e.g. to run it on infra nodes or with a least-privilege role.
+	Pod PodOverrides `yaml:"pod,omitempty" json:"pod,omitempty" mapstructure:"pod,omitempty"`
+	// RBAC selects the permissions of the suite service account. Tests.AdHocTestRBACProfile is used if unset.
+	RBAC RBACProfile `yaml:"rbac,omitempty" json:"rbac,omitempty" mapstructure:"rbac,omitempty"`
 }
 
+// RBACProfile selects the permissions a test suite runs with.
+type RBACProfile struct {
+	// Profile is one of the RBACProfile constants.
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty" mapstructure:"profile,omitempty"`
+	// ClusterRoles are bound to the suite service account for RBACProfileCustom.
+	ClusterRoles []string `yaml:"clusterRoles,omitempty" json:"clusterRoles,omitempty" mapstructure:"clusterRoles,omitempty"`
+}
+
+const (
+	// RBACProfileClusterAdmin runs the suite as cluster-admin.
+	RBACProfileClusterAdmin = "cluster-admin"
+	// RBACProfileDedicatedAdmin runs the suite with the permissions of a customer dedicated admin.
+	RBACProfileDedicatedAdmin = "dedicated-admin"
+	// RBACProfileReadOnly runs the suite with read-only access to the cluster.
+	RBACProfileReadOnly = "read-only"
+	// RBACProfileCustom runs the suite with the cluster roles listed in the profile.
+	RBACProfileCustom = "custom"
+)
+
+// PodOverrides customizes the pod a test suite runs in. Unset fields keep the defaults.
+type PodOverrides struct {
+	// Requests and Limits are resource quantities of the suite container, e.g. {"cpu": "500m", "memory": "1Gi"}.
+	Requests map[string]string `yaml:"requests,omitempty" json:"requests,omitempty" mapstructure:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty" json:"limits,omitempty" mapstructure:"limits,omitempty"`
+	// NodeSelector and Tolerations place the suite pod.
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty" json:"nodeSelector,omitempty" mapstructure:"nodeSelector,omitempty"`
+	Tolerations  []Toleration      `yaml:"tolerations,omitempty" json:"tolerations,omitempty" mapstructure:"tolerations,omitempty"`
+	// ServiceAccount is the name of the service account created for the suite, the RBAC profile name by default.
+	ServiceAccount string `yaml:"serviceAccount,omitempty" json:"serviceAccount,omitempty" mapstructure:"serviceAccount,omitempty"`
+	// Env is added to the environment of the suite container.
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" mapstructure:"env,omitempty"`
+	// ConfigMaps and Secrets are copied from their namespace into the suite project and mounted into the suite container.
+	ConfigMaps []VolumeMount `yaml:"configMaps,omitempty" json:"configMaps,omitempty" mapstructure:"configMaps,omitempty"`
+	Secrets    []VolumeMount `yaml:"secrets,omitempty" json:"secrets,omitempty" mapstructure:"secrets,omitempty"`
+	// ArtifactImage replaces the image of the artifact collection sidecar.
+	ArtifactImage string `yaml:"artifactImage,omitempty" json:"artifactImage,omitempty" mapstructure:"artifactImage,omitempty"`
+}
+
+// Toleration lets a test suite pod be scheduled onto nodes with a matching taint.
+type Toleration struct {
+	Key      string `yaml:"key,omitempty" json:"key,omitempty" mapstructure:"key,omitempty"`
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty" mapstructure:"operator,omitempty"`
+	Value    string `yaml:"value,omitempty" json:"value,omitempty" mapstructure:"value,omitempty"`
+	Effect   string `yaml:"effect,omitempty" json:"effect,omitempty" mapstructure:"effect,omitempty"`
+}
+
+// VolumeMount is a ConfigMap or Secret mounted into a test suite pod.
+type VolumeMount struct {
+	Name      string `yaml:"name" json:"name" mapstructure:"name"`
+	Namespace string `yaml:"namespace" json:"namespace" mapstructure:"namespace"`
+	MountPath string `yaml:"mountPath" json:"mountPath" mapstructure:"mountPath"`
+}
+
+// RetryPolicy controls how a failing test suite is retried.
+type RetryPolicy struct {
+	// MaxAttempts is how often the suite is run at most, including the first run.
+	MaxAttempts int `yaml:"maxAttempts,omitempty" json:"maxAttempts,omitempty" mapstructure:"maxAttempts,omitempty"`
+	// Mode is RetryModeImage to rerun the whole image or RetryModeFailedSpecs to rerun only the failed specs.
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty" mapstructure:"mode,omitempty"`
+}
+
+const (
+	// RetryModeImage reruns the whole test suite image.
+	RetryModeImage = "image"
+	// RetryModeFailedSpecs reruns only the failed specs, passed to the image as a GINKGO_FOCUS regex.
+	RetryModeFailedSpecs = "failed"
+)
+
 const (
 	Success = 0
 	Failure = 1
@@ -126,6 +203,11 @@ const (
 
 	KonfluxTestOutputFile = "konfluxResultsPath"
 
+	// MetricsFile is the path of a Prometheus text-format file the durations of the run steps are written to.
+	// Nothing is written if empty.
+	// Env: METRICS_FILE
+	MetricsFile = "metricsFile"
+
 	// SlackMessageLength TotalSlackMessageLength is about 10000 characters
 	// Summary: 1500 Characters
 	// Build file comment: 500 Characters
@@ -184,6 +266,9 @@ var Upgrade = struct {
 	// UpgradeVersionEqualToInstallVersion is true if the install version and upgrade versions are the same.
 	UpgradeVersionEqualToInstallVersion string
 
+	// VersionSelector is the name of the selector that chose the upgrade version.
+	VersionSelector string
+
 	// Create disruptive Pod Disruption Budget workloads to test the Managed Upgrade Operator's ability to handle them.
 	ManagedUpgradeTestPodDisruptionBudgets string
 
@@ -206,6 +291,7 @@ var Upgrade = struct {
 	Image:                                  "upgrade.image",
 	Type:                                   "upgrade.type",
 	UpgradeVersionEqualToInstallVersion:    "upgrade.upgradeVersionEqualToInstallVersion",
+	VersionSelector:                        "upgrade.versionSelector",
 	ManagedUpgradeTestPodDisruptionBudgets: "upgrade.managedUpgradeTestPodDisruptionBudgets",
 	ManagedUpgradeTestNodeDrain:            "upgrade.managedUpgradeTestNodeDrain",
 	ManagedUpgradeRescheduled:              "upgrade.managedUpgradeRescheduled",
@@ -245,6 +331,18 @@ var Tests = struct {
 	// Env: AD_HOC_TEST_CONTAINER_TIMEOUT
 	AdHocTestContainerTimeout string
 
+	// AdHocTestParallelism is how many test suite images are run at the same time. Suites run one at a time by default.
+	// Env: AD_HOC_TEST_PARALLELISM
+	AdHocTestParallelism string
+
+	// AdHocTestRBACProfile is the RBAC profile of test suites that don't select one. Suites run as cluster-admin by default.
+	// Env: AD_HOC_TEST_RBAC_PROFILE
+	AdHocTestRBACProfile string
+
+	// AdHocTestArtifactImage is the image of the sidecar that collects the artifacts of test suite pods.
+	// Env: AD_HOC_TEST_ARTIFACT_IMAGE
+	AdHocTestArtifactImage string
+
 	// AdHocTestImages is a list of test adHocTestImages to run (DEPRECATED - use TestSuites).
 	// Env: AD_HOC_TEST_IMAGES
 	AdHocTestImages string
@@ -306,9 +404,32 @@ var Tests = struct {
 	OperatorSkip string
 
 	// SkipClusterHealthChecks skips the cluster health checks. Useful when developing against a running cluster.
+	// HealthChecksInclude and HealthChecksExclude skip only some of them.
 	// Env: SKIP_CLUSTER_HEALTH_CHECKS
 	SkipClusterHealthChecks string
 
+	// HealthChecksInclude is a comma-delimited list of the cluster health checks to run. ex. "cvo,node"
+	// All checks that apply to the cluster run when it's empty.
+	// Env: HEALTH_CHECKS_INCLUDE
+	HealthChecksInclude string
+
+	// HealthChecksExclude is a comma-delimited list of the cluster health checks not to run. ex. "cert,replicaset"
+	// Env: HEALTH_CHECKS_EXCLUDE
+	HealthChecksExclude string
+
+	// SLOConfig is the path to a YAML file of the Prometheus backed SLO assertions and alert allowlist
+	// to hold clusters to. The built in assertions are used when it's empty.
+	// Env: SLO_CONFIG
+	SLOConfig string
+
+	// SLOGate evaluates the SLO assertions once all tests ran and fails the run if the cluster misses them.
+	// Env: SLO_GATE
+	SLOGate string
+
+	// SkipSLOGate skips the SLO gate even if SLOGate is set, e.g. for clusters without cluster monitoring.
+	// Env: SKIP_SLO_GATE
+	SkipSLOGate string
+
 	// ClusterHealthChecksTimeout defines the duration for which the test will
 	// wait for the cluster to indicate it is healthy before cancelling the test
 	// run. This value should be formatted for use with time.ParseDuration.
@@ -331,6 +452,9 @@ var Tests = struct {
 	TestSuites:                 "tests.testSuites",
 	SuiteTimeout:               "tests.suiteTimeout",
 	AdHocTestContainerTimeout:  "tests.adHocTestContainerTimeout",
+	AdHocTestParallelism:       "tests.adHocTestParallelism",
+	AdHocTestArtifactImage:     "tests.adHocTestArtifactImage",
+	AdHocTestRBACProfile:       "tests.adHocTestRBACProfile",
 	PollingTimeout:             "tests.pollingTimeout",
 	ServiceAccount:             "tests.serviceAccount",
 	SlackChannel:               "tests.slackChannel",
@@ -344,6 +468,11 @@ var Tests = struct {
 	CleanRuns:                  "tests.cleanRuns",
 	OperatorSkip:               "tests.operatorSkip",
 	SkipClusterHealthChecks:    "tests.skipClusterHealthChecks",
+	HealthChecksInclude:        "tests.healthChecksInclude",
+	HealthChecksExclude:        "tests.healthChecksExclude",
+	SLOConfig:                  "tests.sloConfig",
+	SLOGate:                    "tests.sloGate",
+	SkipSLOGate:                "tests.skipSLOGate",
 	LogBucket:                  "tests.logBucket",
 	ClusterHealthChecksTimeout: "tests.clusterHealthChecksTimeout",
 	OnlyHealthCheckNodes:       "tests.onlyHealthCheckNodes",
@@ -380,6 +509,21 @@ var Cluster = struct {
 	// Env: CLUSTER_UP_TIMEOUT
 	InstallTimeout string
 
+	// ProvisioningWatchIntervalInSeconds is how often the install logs, state and inflight checks of a
+	// provisioning cluster are captured to the report directory. 0 disables the captures.
+	// Env: PROVISIONING_WATCH_INTERVAL_SECONDS
+	ProvisioningWatchIntervalInSeconds string
+
+	// ProvisionRetries is how many times provisioning is retried after a failure classified as
+	// transient, e.g. a lack of quota or capacity or cloud API throttling.
+	// Env: PROVISION_RETRIES
+	ProvisionRetries string
+
+	// ProvisionRetryRotate is a comma separated list of what to change when retrying after a lack of
+	// quota or capacity: "region" picks another random region and "machineType" another machine type.
+	// Env: PROVISION_RETRY_ROTATE
+	ProvisionRetryRotate string
+
 	// ReleaseImageLatest is used when we're testing versions not-yet-accepted from the release controller.
 	ReleaseImageLatest string
 
@@ -443,6 +587,9 @@ var Cluster = struct {
 	// PreviousVersionFromDefaultFound is true if a previous version from default was found.
 	PreviousVersionFromDefaultFound string
 
+	// VersionSelector is the name of the selector that chose the install version.
+	VersionSelector string
+
 	// ProvisionShardID is the shard ID that is set to provision a shard for the cluster.
 	ProvisionShardID string
 
@@ -476,6 +623,14 @@ var Cluster = struct {
 	// ClaimedFromReserve tracks whether this cluster's test run used a new or recycled cluster
 	ClaimedFromReserve string
 
+	// ClaimToken is the token this test run claimed its reserved cluster with, used to release it.
+	ClaimToken string
+
+	// ClaimLeaseInMinutes is how long a claim on a reserved cluster is held. The run renews it while
+	// it uses the cluster, so claimed clusters whose lease expired are considered abandoned.
+	// Env: RESERVE_CLAIM_LEASE_MINUTES
+	ClaimLeaseInMinutes string
+
 	// InspectNamespaces is a comma-delimited list of namespaces to perform an inspect on during test cleanup
 	InspectNamespaces string
 
@@ -497,6 +652,9 @@ var Cluster = struct {
 	ExpiryInMinutes:                     "cluster.expiryInMinutes",
 	AfterTestWait:                       "cluster.afterTestWait",
 	InstallTimeout:                      "cluster.installTimeout",
+	ProvisioningWatchIntervalInSeconds:  "cluster.provisioningWatchIntervalInSeconds",
+	ProvisionRetries:                    "cluster.provisionRetries",
+	ProvisionRetryRotate:                "cluster.provisionRetryRotate",
 	ReleaseImageLatest:                  "cluster.releaseImageLatest",
 	UseProxyForInstall:                  "cluster.useProxyForInstall",
 	UseLatestVersionForInstall:          "cluster.useLatestVersionForInstall",
@@ -516,6 +674,7 @@ var Cluster = struct {
 	Version:                             "cluster.version",
 	EnoughVersionsForOldestOrMiddleTest: "cluster.enoughVersionForOldestOrMiddleTest",
 	PreviousVersionFromDefaultFound:     "cluster.previousVersionFromDefaultFound",
+	VersionSelector:                     "cluster.versionSelector",
 	ProvisionShardID:                    "cluster.provisionshardID",
 	NumWorkerNodes:                      "cluster.numWorkerNodes",
 	NetworkProvider:                     "cluster.networkProvider",
@@ -524,6 +683,8 @@ var Cluster = struct {
 	UseClusterReserve:                   "cluster.useClusterReserve",
 	Passing:                             "cluster.passing",
 	ClaimedFromReserve:                  "cluster.claimedFromReserve",
+	ClaimToken:                          "cluster.claimToken",
+	ClaimLeaseInMinutes:                 "cluster.claimLeaseInMinutes",
 	InspectNamespaces:                   "cluster.inspectNamespaces",
 	EnableFips:                          "cluster.enableFips",
 	FedRamp:                             "cluster.fedRamp",
@@ -735,6 +896,8 @@ func InitOSDe2eViper() {
 
 	_ = viper.BindEnv(KonfluxTestOutputFile, "KONFLUX_TEST_OUTPUT_FILE")
 
+	_ = viper.BindEnv(MetricsFile, "METRICS_FILE")
+
 	_ = viper.BindEnv(Suffix, "SUFFIX")
 
 	viper.SetDefault(DryRun, false)
@@ -789,6 +952,13 @@ func InitOSDe2eViper() {
 	viper.SetDefault(Tests.AdHocTestContainerTimeout, "30m")
 	_ = viper.BindEnv(Tests.AdHocTestContainerTimeout, "AD_HOC_TEST_CONTAINER_TIMEOUT")
 
+	viper.SetDefault(Tests.AdHocTestParallelism, 1)
+	_ = viper.BindEnv(Tests.AdHocTestParallelism, "AD_HOC_TEST_PARALLELISM")
+
+	_ = viper.BindEnv(Tests.AdHocTestArtifactImage, "AD_HOC_TEST_ARTIFACT_IMAGE")
+
+	_ = viper.BindEnv(Tests.AdHocTestRBACProfile, "AD_HOC_TEST_RBAC_PROFILE")
+
 	viper.SetDefault(Tests.PollingTimeout, 300)
 	_ = viper.BindEnv(Tests.PollingTimeout, "POLLING_TIMEOUT")
 
@@ -813,6 +983,18 @@ func InitOSDe2eViper() {
 	viper.SetDefault(Tests.OperatorSkip, "insights")
 	_ = viper.BindEnv(Tests.OperatorSkip, "OPERATOR_SKIP")
 
+	_ = viper.BindEnv(Tests.HealthChecksInclude, "HEALTH_CHECKS_INCLUDE")
+
+	_ = viper.BindEnv(Tests.HealthChecksExclude, "HEALTH_CHECKS_EXCLUDE")
+
+	_ = viper.BindEnv(Tests.SLOConfig, "SLO_CONFIG")
+
+	viper.SetDefault(Tests.SLOGate, false)
+	_ = viper.BindEnv(Tests.SLOGate, "SLO_GATE")
+
+	viper.SetDefault(Tests.SkipSLOGate, false)
+	_ = viper.BindEnv(Tests.SkipSLOGate, "SKIP_SLO_GATE")
+
 	viper.SetDefault(Tests.ClusterHealthChecksTimeout, "2h")
 	_ = viper.BindEnv(Tests.ClusterHealthChecksTimeout, "CLUSTER_HEALTH_CHECKS_TIMEOUT")
 
@@ -847,6 +1029,14 @@ func InitOSDe2eViper() {
 	viper.SetDefault(Cluster.InstallTimeout, 135)
 	_ = viper.BindEnv(Cluster.InstallTimeout, "CLUSTER_UP_TIMEOUT")
 
+	viper.SetDefault(Cluster.ProvisioningWatchIntervalInSeconds, 60)
+	_ = viper.BindEnv(Cluster.ProvisioningWatchIntervalInSeconds, "PROVISIONING_WATCH_INTERVAL_SECONDS")
+
+	viper.SetDefault(Cluster.ProvisionRetries, 0)
+	_ = viper.BindEnv(Cluster.ProvisionRetries, "PROVISION_RETRIES")
+
+	_ = viper.BindEnv(Cluster.ProvisionRetryRotate, "PROVISION_RETRY_ROTATE")
+
 	_ = viper.BindEnv(Cluster.ReleaseImageLatest, "RELEASE_IMAGE_LATEST")
 
 	_ = viper.BindEnv(ProwJobId, "PROW_JOB_ID")
@@ -918,6 +1108,10 @@ func InitOSDe2eViper() {
 	_ = viper.BindEnv(Cluster.UseClusterReserve, "USE_EXISTING_CLUSTER", "USE_CLUSTER_RESERVE")
 
 	viper.SetDefault(Cluster.ClaimedFromReserve, false)
+
+	viper.SetDefault(Cluster.ClaimLeaseInMinutes, 60)
+	_ = viper.BindEnv(Cluster.ClaimLeaseInMinutes, "RESERVE_CLAIM_LEASE_MINUTES")
+
 	viper.SetDefault(Cluster.Passing, false)
 
 	viper.SetDefault(Cluster.InspectNamespaces, strings.Join(defaultInspectNamespaces, ","))
@@ -1096,7 +1290,7 @@ func GetTestSuites() ([]TestSuite, error) {
 		// Try structured unmarshaling first (config files)
 		var testSuites []TestSuite
 		if err := viper.UnmarshalKey(Tests.TestSuites, &testSuites); err == nil {
-			return testSuites, nil
+			return testSuites, validateTestSuites(testSuites)
 		}
 
 		// Try parsing as YAML string from environment variable
@@ -1105,7 +1299,7 @@ func GetTestSuites() ([]TestSuite, error) {
 		if err := yaml.Unmarshal([]byte(strValue), &suites); err != nil {
 			return nil, fmt.Errorf("failed to parse TEST_SUITES_YAML, format should be: '- image: ...\\n  slackChannel: ...'")
 		}
-		return suites, nil
+		return suites, validateTestSuites(suites)
 	}
 
 	// Priority 2: Try legacy AdHocTestImages as string slice (simple image names only)
@@ -1123,6 +1317,47 @@ func GetTestSuites() ([]TestSuite, error) {
 	return []TestSuite{}, nil
 }
 
+// validateTestSuites checks the retry policy, pod mounts and RBAC profile of every test suite.
+func validateTestSuites(suites []TestSuite) error {
+	for _, suite := range suites {
+		for _, mount := range slices.Concat(suite.Pod.ConfigMaps, suite.Pod.Secrets) {
+			if mount.Name == "" || mount.Namespace == "" || mount.MountPath == "" {
+				return fmt.Errorf("invalid mount %+v for test suite %s, name, namespace and mountPath are required", mount, suite.Image)
+			}
+		}
+		switch suite.Retry.Mode {
+		case "", RetryModeImage, RetryModeFailedSpecs:
+		default:
+			return fmt.Errorf("invalid retry mode %q for test suite %s, must be %q or %q", suite.Retry.Mode, suite.Image, RetryModeImage, RetryModeFailedSpecs)
+		}
+		if suite.Retry.MaxAttempts < 0 {
+			return fmt.Errorf("invalid retry maxAttempts %d for test suite %s", suite.Retry.MaxAttempts, suite.Image)
+		}
+		if err := validateRBACProfile(suite.RBAC); err != nil {
+			return fmt.Errorf("invalid rbac profile for test suite %s: %w", suite.Image, err)
+		}
+	}
+	return nil
+}
+
+// validateRBACProfile checks that a profile is known and that only custom profiles list cluster roles.
+func validateRBACProfile(rbac RBACProfile) error {
+	switch rbac.Profile {
+	case RBACProfileCustom:
+		if len(rbac.ClusterRoles) == 0 {
+			return fmt.Errorf("profile %q requires clusterRoles", RBACProfileCustom)
+		}
+	case "", RBACProfileClusterAdmin, RBACProfileDedicatedAdmin, RBACProfileReadOnly:
+		if len(rbac.ClusterRoles) > 0 {
+			return fmt.Errorf("clusterRoles are only used with profile %q", RBACProfileCustom)
+		}
+	default:
+		return fmt.Errorf("unknown profile %q, must be %q, %q, %q or %q", rbac.Profile,
+			RBACProfileClusterAdmin, RBACProfileDedicatedAdmin, RBACProfileReadOnly, RBACProfileCustom)
+	}
+	return nil
+}
+
 // GetAdHocTestImagesAsString returns only the images from the test suites configuration as a comma-separated string
 func GetAdHocTestImagesAsString() string {
 	suites, err := GetTestSuites()
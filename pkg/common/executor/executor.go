@@ -19,10 +19,8 @@ import (
 	"github.com/openshift/osde2e/pkg/common/util"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -45,12 +43,18 @@ type Config struct {
 	RestConfig            *rest.Config
 	MCSimulationEnabled   bool
 	MCSimulationSkipInfra bool
+	// Pod customizes the pods of all suites, suites can override it further
+	Pod PodOverrides
 }
 
 type Executor struct {
 	oc     *openshift.Client
 	cfg    *Config
 	logger logr.Logger
+	// buildLog receives the live suite logs, the standard logger's output is used if nil
+	buildLog io.Writer
+	// runSuite runs a single attempt of a suite, execute is used if nil
+	runSuite func(ctx context.Context, logger logr.Logger, image, outputDir string, pod PodOverrides) (*testResults, error)
 }
 
 // New sets up a new executor to run a given test suite image
@@ -68,94 +72,99 @@ func New(logger logr.Logger, cfg *Config) (*Executor, error) {
 	return &Executor{oc: oc, cfg: cfg, logger: logger.WithName("executor")}, nil
 }
 
+// Execute runs a test suite image and collects its artifacts into the configured output directory
 func (e *Executor) Execute(ctx context.Context, image string) (*testResults, error) {
-	if err := os.MkdirAll(e.cfg.OutputDir, os.ModePerm); err != nil {
+	return e.execute(ctx, e.logger, image, e.cfg.OutputDir, e.cfg.Pod.withDefaults())
+}
+
+// execute runs a test suite image in its own project and collects its artifacts into outputDir.
+// It does not modify the executor, so multiple suites can be executed concurrently.
+// The pod the suite runs in is customized by pod, which must have its defaults set.
+func (e *Executor) execute(ctx context.Context, logger logr.Logger, image, outputDir string, pod PodOverrides) (*testResults, error) {
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
 		return nil, fmt.Errorf("creating output directory: %w", err)
 	}
 
-	project, err := e.setupProject(ctx)
+	project, err := e.setupProject(ctx, logger, pod)
 	if err != nil {
 		return nil, fmt.Errorf("creating namespace: %w", err)
 	}
 
 	defer func() {
 		if e.cfg.SkipCleanup {
-			e.logger.Info("Skipping cleanup")
+			logger.Info("Skipping cleanup")
 			return
 		}
 		if err := e.oc.Delete(ctx, project); err != nil {
-			e.logger.Info("Failed to delete project", "name", project.Name)
+			logger.Info("Failed to delete project", "name", project.Name)
 		}
 	}()
 
-	job, err := e.createJob(ctx, project.Name, image)
+	job, err := e.createJob(ctx, logger, project.Name, image, pod)
 	if err != nil {
 		return nil, fmt.Errorf("creating job: %w", err)
 	}
 
-	e.logger.Info("waiting for suite to complete")
-	if err := e.waitForSuite(ctx, job.Name, job.Namespace, image); err != nil {
-		return nil, fmt.Errorf("waiting for suite to finish: %w", err)
+	clientSet, err := kubernetes.NewForConfig(e.oc.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("creating clientset: %w", err)
 	}
 
-	e.logger.Info("fetching artifacts")
-	if err := e.fetchArtifacts(ctx, job.Name, job.Namespace); err != nil {
+	// Stream the suite log while it runs so the output is kept even if the suite hangs
+	// until its timeout or the artifacts can't be retrieved afterwards.
+	stopLogs, err := e.streamSuiteLogs(ctx, logger, clientSet, job.Name, job.Namespace, image, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("streaming suite logs: %w", err)
+	}
+
+	logger.Info("waiting for suite to complete")
+	waitErr := e.waitForSuite(ctx, logger, job.Name, job.Namespace, image)
+	logsComplete := stopLogs(waitErr == nil)
+	if waitErr != nil {
+		return nil, fmt.Errorf("waiting for suite to finish: %w", waitErr)
+	}
+
+	logger.Info("fetching artifacts")
+	if err := e.fetchArtifacts(ctx, job.Name, job.Namespace, outputDir, !logsComplete); err != nil {
 		return nil, fmt.Errorf("fetching artifacts: %w", err)
 	}
 
-	e.logger.Info("processing test results")
-	results, err := processJUnitResults(e.logger, e.cfg.OutputDir)
+	logger.Info("processing test results")
+	results, err := processResults(logger, outputDir)
 	if err != nil {
-		return nil, fmt.Errorf("processing junit results: %w", err)
+		return nil, fmt.Errorf("processing test results: %w", err)
 	}
 
 	return results, nil
 }
 
-func (e *Executor) setupProject(ctx context.Context) (*projectv1.Project, error) {
+func (e *Executor) setupProject(ctx context.Context, logger logr.Logger, pod PodOverrides) (*projectv1.Project, error) {
 	// TODO: why does GenerateName not work?
 	project := &projectv1.Project{ObjectMeta: metav1.ObjectMeta{Name: "osde2e-executor-" + util.RandomStr(5)}}
 	if err := e.oc.Create(ctx, project); err != nil {
 		return nil, err
 	}
-	e.logger.Info("created namespace", "name", project.Name)
+	logger.Info("created namespace", "name", project.Name)
 
-	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin", Namespace: project.Name}}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: pod.ServiceAccount, Namespace: project.Name}}
 	if err := e.oc.Create(ctx, sa); err != nil {
-		return nil, fmt.Errorf("creating cluster-admin serviceaccount: %w", err)
+		return nil, fmt.Errorf("creating %s serviceaccount: %w", sa.Name, err)
 	}
-	e.logger.Info("created service account", "name", sa.Name)
+	logger.Info("created service account", "name", sa.Name)
 
-	crb := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: "osde2e-executor-cluster-admin-",
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(project.DeepCopy(), schema.FromAPIVersionAndKind("project.openshift.io/v1", "Project")),
-			},
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      rbacv1.ServiceAccountKind,
-				Name:      sa.Name,
-				Namespace: sa.Namespace,
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: rbacv1.GroupName,
-			Kind:     "ClusterRole",
-			Name:     "cluster-admin",
-		},
-	}
-
-	if err := e.oc.Create(ctx, crb); err != nil {
-		return nil, fmt.Errorf("creating cluster role binding: %w", err)
+	if err := e.bindProfile(ctx, logger, project, sa.Name, pod.RBAC); err != nil {
+		return nil, fmt.Errorf("binding rbac profile: %w", err)
 	}
 
 	return project, nil
 }
 
-func (e *Executor) createJob(ctx context.Context, namespace string, image string) (*batchv1.Job, error) {
-	job := e.buildJobSpec(namespace, image)
+func (e *Executor) createJob(ctx context.Context, logger logr.Logger, namespace string, image string, pod PodOverrides) (*batchv1.Job, error) {
+	job := e.buildJobSpec(namespace, image, pod)
+
+	if err := e.copyMounts(ctx, namespace, pod); err != nil {
+		return nil, fmt.Errorf("copying mounts: %w", err)
+	}
 
 	if len(e.cfg.PassthruSecrets) > 0 {
 		passthruSercret := &corev1.Secret{
@@ -188,19 +197,21 @@ func (e *Executor) createJob(ctx context.Context, namespace string, image string
 	if err := e.oc.Create(ctx, job); err != nil {
 		return nil, err
 	}
-	e.logger.Info("created job", "name", job.Name)
+	logger.Info("created job", "name", job.Name)
 	return job, nil
 }
 
-func (e *Executor) buildJobSpec(namespace string, image string) *batchv1.Job {
-	return &batchv1.Job{
+func (e *Executor) buildJobSpec(namespace string, image string, pod PodOverrides) *batchv1.Job {
+	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "executor-",
 			Namespace:    namespace,
 		},
 		Spec: batchv1.JobSpec{
-			Parallelism:           ptr.To[int32](1),
-			Completions:           ptr.To[int32](1),
+			Parallelism: ptr.To[int32](1),
+			Completions: ptr.To[int32](1),
+			// The artifact container keeps the pod running after the suite fails, so the Job never retries.
+			// Retries are handled by the executor instead, see ExecuteSuite.
 			BackoffLimit:          ptr.To[int32](0),
 			ActiveDeadlineSeconds: ptr.To(int64(e.cfg.Timeout.Seconds())),
 			Template: corev1.PodTemplateSpec{
@@ -210,10 +221,10 @@ func (e *Executor) buildJobSpec(namespace string, image string) *batchv1.Job {
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "cluster-admin",
+					ServiceAccountName: pod.ServiceAccount,
 					Containers: []corev1.Container{
 						{
-							Name:            "e2e-suite",
+							Name:            suiteContainer,
 							Image:           image,
 							ImagePullPolicy: corev1.PullAlways,
 							Env: []corev1.EnvVar{
@@ -251,7 +262,7 @@ func (e *Executor) buildJobSpec(namespace string, image string) *batchv1.Job {
 						},
 						{
 							Name:    "pause-for-artifacts",
-							Image:   "registry.access.redhat.com/ubi10/ubi:10.1",
+							Image:   pod.ArtifactImage,
 							Command: []string{"tail", "-f", "/dev/null"},
 							VolumeMounts: []corev1.VolumeMount{
 								{
@@ -274,11 +285,14 @@ func (e *Executor) buildJobSpec(namespace string, image string) *batchv1.Job {
 			},
 		},
 	}
+
+	applyPodOverrides(&job.Spec.Template.Spec, pod)
+	return job
 }
 
 // Wait for the e2e-suite container to complete (succeed/fail/stop)
 // We can't wait for the job because the pause container keeps it running for artifact collection
-func (e *Executor) waitForSuite(ctx context.Context, name, namespace, image string) error {
+func (e *Executor) waitForSuite(ctx context.Context, logger logr.Logger, name, namespace, image string) error {
 	return wait.PollUntilContextTimeout(ctx, 10*time.Second, e.cfg.Timeout, false, func(ctx context.Context) (bool, error) {
 		pod, err := e.findJobPod(ctx, name, namespace)
 		if err != nil {
@@ -286,7 +300,7 @@ func (e *Executor) waitForSuite(ctx context.Context, name, namespace, image stri
 		}
 		// Check the status of the e2e-suite container specifically
 		for _, containerStatus := range pod.Status.ContainerStatuses {
-			if containerStatus.Name == "e2e-suite" {
+			if containerStatus.Name == suiteContainer {
 				// Check for image pull failures first
 				if containerStatus.State.Waiting != nil {
 					reason := containerStatus.State.Waiting.Reason
@@ -300,7 +314,7 @@ func (e *Executor) waitForSuite(ctx context.Context, name, namespace, image stri
 				}
 				// Return true if container has terminated (succeeded or failed)
 				if containerStatus.State.Terminated != nil {
-					e.logger.Info("e2e-suite has terminated", "state", containerStatus.State)
+					logger.Info("e2e-suite has terminated", "state", containerStatus.State)
 					return true, nil
 				}
 				// Return false if container is still running or waiting
@@ -312,7 +326,9 @@ func (e *Executor) waitForSuite(ctx context.Context, name, namespace, image stri
 	})
 }
 
-func (e *Executor) fetchArtifacts(ctx context.Context, name, namespace string) error {
+// fetchArtifacts copies the result files out of the pod. The suite log is only fetched if fetchLogs
+// is set, which is the case when streaming did not capture the complete log.
+func (e *Executor) fetchArtifacts(ctx context.Context, name, namespace, outputDir string, fetchLogs bool) error {
 	clientSet, err := kubernetes.NewForConfig(e.oc.GetConfig())
 	if err != nil {
 		return fmt.Errorf("creating clientset: %w", err)
@@ -323,11 +339,13 @@ func (e *Executor) fetchArtifacts(ctx context.Context, name, namespace string) e
 		return fmt.Errorf("finding job pod: %w", err)
 	}
 
-	if err := e.fetchPodLogs(ctx, clientSet, pod, name); err != nil {
-		return fmt.Errorf("fetching pod logs: %w", err)
+	if fetchLogs {
+		if err := e.fetchPodLogs(ctx, clientSet, pod, name, outputDir); err != nil {
+			return fmt.Errorf("fetching pod logs: %w", err)
+		}
 	}
 
-	if err := e.fetchArtifactFiles(ctx, clientSet, pod); err != nil {
+	if err := e.fetchArtifactFiles(ctx, clientSet, pod, outputDir); err != nil {
 		return fmt.Errorf("fetching artifact files: %w", err)
 	}
 
@@ -347,10 +365,10 @@ func (e *Executor) findJobPod(ctx context.Context, jobName, namespace string) (*
 	return &pods.Items[0], nil
 }
 
-func (e *Executor) fetchPodLogs(ctx context.Context, clientSet *kubernetes.Clientset, pod *corev1.Pod, jobName string) error {
+func (e *Executor) fetchPodLogs(ctx context.Context, clientSet *kubernetes.Clientset, pod *corev1.Pod, jobName, outputDir string) error {
 	var logs strings.Builder
 
-	req := clientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: "e2e-suite"})
+	req := clientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: suiteContainer})
 	logStream, err := req.Stream(ctx)
 	if err != nil {
 		return fmt.Errorf("getting logs: %w", err)
@@ -365,14 +383,14 @@ func (e *Executor) fetchPodLogs(ctx context.Context, clientSet *kubernetes.Clien
 	logs.Write(logBytes)
 	logs.WriteString("\n")
 
-	if err = os.WriteFile(filepath.Join(e.cfg.OutputDir, jobName+".log"), []byte(logs.String()), os.ModePerm); err != nil {
+	if err = os.WriteFile(filepath.Join(outputDir, jobName+".log"), []byte(logs.String()), os.ModePerm); err != nil {
 		return fmt.Errorf("writing pod logs: %w", err)
 	}
 
 	return nil
 }
 
-func (e *Executor) fetchArtifactFiles(ctx context.Context, clientSet *kubernetes.Clientset, pod *corev1.Pod) error {
+func (e *Executor) fetchArtifactFiles(ctx context.Context, clientSet *kubernetes.Clientset, pod *corev1.Pod, outputDir string) error {
 	execRequest := clientSet.CoreV1().RESTClient().
 		Post().
 		Resource("pods").
@@ -403,7 +421,7 @@ func (e *Executor) fetchArtifactFiles(ctx context.Context, clientSet *kubernetes
 		return fmt.Errorf("streaming executor: %w", err)
 	}
 
-	if err = untarBuffer(&stdout, e.cfg.OutputDir); err != nil {
+	if err = untarBuffer(&stdout, outputDir); err != nil {
 		return fmt.Errorf("untarring buffer: %w", err)
 	}
 
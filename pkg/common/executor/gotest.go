@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// goTestEvent is a line of `go test -json` output, see `go doc test2json`
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// goTestParser reads `go test -json` event streams
+type goTestParser struct{}
+
+func (goTestParser) Name() string { return "gotest" }
+
+func (goTestParser) Match(fileName string) bool {
+	fileName = strings.ToLower(fileName)
+	return strings.HasSuffix(fileName, ".json") || strings.HasSuffix(fileName, ".jsonl")
+}
+
+// Recognize checks that the first line is a test2json event, which starts with its time and action
+func (goTestParser) Recognize(head []byte) bool {
+	line, _, _ := bytes.Cut(bytes.TrimSpace(head), []byte("\n"))
+	return bytes.HasPrefix(line, []byte("{")) && bytes.Contains(line, []byte(`"Action":`))
+}
+
+// Parse builds a suite per package. Tests that never finish, e.g. because the test binary panicked or
+// timed out, are reported as errors, and so are packages that fail without a failing test, e.g.
+// because they don't build.
+func (goTestParser) Parse(_ string, data []byte) ([]junit.Suite, error) {
+	type packageResult struct {
+		suite junit.Suite
+		tests map[string]int
+		// outputs are the outputs of the tests, by index
+		outputs []*strings.Builder
+		output  strings.Builder
+		failed  bool
+	}
+	packages := map[string]*packageResult{}
+	var order []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event goTestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if event.Action == "" {
+			return nil, fmt.Errorf("line %d: not a go test event", line)
+		}
+
+		pkg, ok := packages[event.Package]
+		if !ok {
+			pkg = &packageResult{suite: junit.Suite{Name: event.Package, Package: event.Package}, tests: map[string]int{}}
+			packages[event.Package] = pkg
+			order = append(order, event.Package)
+		}
+
+		if event.Test == "" {
+			switch event.Action {
+			case "output":
+				pkg.output.WriteString(event.Output)
+			case "fail":
+				pkg.failed = true
+			}
+			continue
+		}
+
+		index, ok := pkg.tests[event.Test]
+		if !ok {
+			index = len(pkg.suite.Tests)
+			pkg.tests[event.Test] = index
+			pkg.suite.Tests = append(pkg.suite.Tests, junit.Test{Name: event.Test, Classname: event.Package})
+			pkg.outputs = append(pkg.outputs, &strings.Builder{})
+		}
+		test := &pkg.suite.Tests[index]
+		output := pkg.outputs[index]
+
+		switch event.Action {
+		case "output":
+			output.WriteString(event.Output)
+		case "pass":
+			test.Status = junit.StatusPassed
+		case "skip":
+			test.Status = junit.StatusSkipped
+		case "fail":
+			test.Status = junit.StatusFailed
+		}
+		if event.Elapsed > 0 {
+			test.Duration = time.Duration(event.Elapsed * float64(time.Second))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading go test events: %w", err)
+	}
+	if len(order) == 0 {
+		return nil, errors.New("no go test events found")
+	}
+
+	suites := make([]junit.Suite, 0, len(order))
+	for _, name := range order {
+		pkg := packages[name]
+		var testFailed bool
+		for i := range pkg.suite.Tests {
+			test := &pkg.suite.Tests[i]
+			test.SystemOut = pkg.outputs[i].String()
+			switch test.Status {
+			case junit.StatusFailed:
+				testFailed = true
+				test.Error = junit.Error{Message: "test failed", Body: test.SystemOut}
+			case "":
+				testFailed = true
+				test.Status = junit.StatusError
+				test.Error = junit.Error{Message: "test did not finish", Body: test.SystemOut}
+			}
+		}
+
+		pkg.suite.SystemOut = pkg.output.String()
+		if pkg.failed && !testFailed {
+			pkg.suite.Tests = append(pkg.suite.Tests, junit.Test{
+				Name:      name,
+				Classname: name,
+				Status:    junit.StatusError,
+				Error:     junit.Error{Message: "package failed", Body: pkg.suite.SystemOut},
+			})
+		}
+		pkg.suite.Aggregate()
+		suites = append(suites, pkg.suite)
+	}
+	return suites, nil
+}
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,30 +23,39 @@ type testResults struct {
 	Suites       []junit.Suite
 }
 
-// processJUnitResults finds and processes all JUnit XML files in the given output directory
-func processJUnitResults(logger logr.Logger, outputDir string) (*testResults, error) {
-	// Find all JUnit XML files in the output directory
-	junitFiles, err := findJUnitFiles(outputDir)
+// processResults finds and processes all test result files in the given output directory. Results that
+// aren't JUnit XML are normalized and also written as JUnit XML next to the original file.
+func processResults(logger logr.Logger, outputDir string) (*testResults, error) {
+	// Find all result files in the output directory
+	resultFiles, err := findResultFiles(outputDir)
 	if err != nil {
-		return nil, fmt.Errorf("finding junit files: %w", err)
+		return nil, fmt.Errorf("finding result files: %w", err)
 	}
 
-	if len(junitFiles) == 0 {
-		logger.Info("no junit files found in output directory")
+	if len(resultFiles) == 0 {
+		logger.Info("no result files found in output directory")
 		return &testResults{}, nil
 	}
 
-	logger.Info("found junit files", "count", len(junitFiles))
+	logger.Info("found result files", "count", len(resultFiles))
 
-	// Parse all JUnit files
+	// Parse all result files
 	var allSuites []junit.Suite
-	for _, file := range junitFiles {
-		suites, err := junit.IngestFile(file)
+	for _, file := range resultFiles {
+		suites, parser, err := parseResultFile(file)
 		if err != nil {
-			logger.Error(err, "failed to parse junit file", "file", file)
+			logger.Error(err, "failed to parse result file", "file", file)
 			continue
 		}
 		allSuites = append(allSuites, suites...)
+
+		if parser.Name() != junitFormat {
+			normalized := normalizedJUnitPath(file, parser)
+			if err := writeJUnitFile(normalized, suites); err != nil {
+				logger.Error(err, "failed to write normalized junit file", "file", normalized)
+			}
+			logger.Info("normalized result file", "file", file, "format", parser.Name())
+		}
 	}
 
 	// Calculate test statistics
@@ -79,9 +89,9 @@ func processJUnitResults(logger logr.Logger, outputDir string) (*testResults, er
 	return results, nil
 }
 
-// findJUnitFiles recursively searches for XML files in the given directory
-func findJUnitFiles(outputDir string) ([]string, error) {
-	var junitFiles []string
+// findResultFiles recursively searches for files any result parser may read in the given directory
+func findResultFiles(outputDir string) ([]string, error) {
+	var resultFiles []string
 
 	err := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -93,15 +103,61 @@ func findJUnitFiles(outputDir string) ([]string, error) {
 			return nil
 		}
 
-		// Look for XML files that might be JUnit reports
-		if strings.HasSuffix(strings.ToLower(info.Name()), ".xml") {
-			// Additional filtering could be added here to be more specific about JUnit files
-			// For now, we'll process all XML files and let the parser handle invalid ones
-			junitFiles = append(junitFiles, path)
+		// Files are only parsed when a parser recognizes them, other artifacts may share the extension
+		isResult, err := isResultFile(path)
+		if err != nil {
+			return err
+		}
+		if isResult {
+			resultFiles = append(resultFiles, path)
 		}
 
 		return nil
 	})
 
-	return junitFiles, err
+	return resultFiles, err
+}
+
+// isJUnitReport reports whether the analysis engine reads the file as a JUnit report, it picks up
+// XML files with "junit" in their name
+func isJUnitReport(fileName string) bool {
+	return strings.HasSuffix(fileName, ".xml") && strings.Contains(fileName, "junit")
+}
+
+// removeJUnitReports removes the JUnit reports in the directory, so the reports of an attempt that
+// was retried aren't counted next to those of the retry
+func removeJUnitReports(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !isJUnitReport(info.Name()) {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+// junitFormat is the name of the JUnit XML result format
+const junitFormat = "junit"
+
+// junitParser reads JUnit XML reports, as written by Ginkgo
+type junitParser struct{}
+
+func (junitParser) Name() string { return junitFormat }
+
+func (junitParser) Match(fileName string) bool {
+	return strings.HasSuffix(strings.ToLower(fileName), ".xml")
+}
+
+// Recognize looks for the root element, either <testsuites> or a single <testsuite>
+func (junitParser) Recognize(head []byte) bool {
+	return bytes.Contains(head, []byte("<testsuite"))
+}
+
+func (junitParser) Parse(_ string, data []byte) ([]junit.Suite, error) {
+	return junit.Ingest(data)
 }
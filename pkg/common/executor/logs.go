@@ -0,0 +1,199 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// suiteContainer is the container running the test suite
+	suiteContainer = "e2e-suite"
+	// logReconnectInterval is how long to wait before reconnecting to a log stream that ended early
+	logReconnectInterval = 5 * time.Second
+	// logDrainTimeout is how long to wait for the remaining log output once the suite has terminated
+	logDrainTimeout = 30 * time.Second
+)
+
+// logStream follows the log of the suite container of a job pod. Log lines are written to a
+// per-suite file and to the build log as they arrive. The stream reconnects after API disconnects
+// and container restarts, resuming after the last line it has seen.
+type logStream struct {
+	clientSet kubernetes.Interface
+	logger    logr.Logger
+	findPod   func(ctx context.Context) (*corev1.Pod, error)
+	file      io.Writer
+	buildLog  io.Writer
+	prefix    string
+	interval  time.Duration
+
+	// last is the timestamp of the last line written, lines before it are skipped after reconnecting
+	last time.Time
+	// atLast is the number of lines written with the last timestamp. Runtimes such as CRI-O give every
+	// line of a chunk the same timestamp, so that many lines at last are resent after reconnecting.
+	atLast int
+	// resent is the number of lines at last the current stream still resends
+	resent int
+	// complete is set once the log was followed until the container terminated
+	complete bool
+}
+
+// run follows the log until the suite container terminates or ctx is done.
+func (s *logStream) run(ctx context.Context) {
+	for {
+		pod, err := s.findPod(ctx)
+		if err == nil {
+			terminated := containerTerminated(pod)
+
+			if err = s.follow(ctx, pod); err != nil && ctx.Err() == nil {
+				s.logger.Info("log stream interrupted, reconnecting", "error", err.Error())
+			}
+
+			// A follow stream only ends on its own once the container has stopped. Seeing the
+			// container terminated before following means everything up to the end has been read.
+			if err == nil && terminated {
+				s.complete = true
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// follow streams the suite container log of the pod until the stream ends.
+func (s *logStream) follow(ctx context.Context, pod *corev1.Pod) error {
+	options := &corev1.PodLogOptions{
+		Container:  suiteContainer,
+		Follow:     true,
+		Timestamps: true,
+	}
+	if !s.last.IsZero() {
+		since := metav1.NewTime(s.last)
+		options.SinceTime = &since
+	}
+
+	stream, err := s.clientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("opening log stream: %w", err)
+	}
+	defer stream.Close()
+
+	return s.copyLines(stream)
+}
+
+// copyLines writes every new line of a log stream to the per-suite file and the build log.
+func (s *logStream) copyLines(r io.Reader) error {
+	s.resent = s.atLast
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			s.writeLine(strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeLine strips the timestamp added by the API server and writes the line, unless it was already written.
+func (s *logStream) writeLine(line string) {
+	if timestamp, text, found := strings.Cut(line, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			switch {
+			case t.Before(s.last):
+				return
+			case t.Equal(s.last) && s.resent > 0:
+				s.resent--
+				return
+			case t.Equal(s.last):
+				s.atLast++
+			default:
+				s.last, s.atLast, s.resent = t, 1, 0
+			}
+			line = text
+		}
+	}
+
+	if _, err := fmt.Fprintln(s.file, line); err != nil {
+		s.logger.Error(err, "failed to write suite log")
+	}
+	fmt.Fprintf(s.buildLog, "[%s] %s\n", s.prefix, line)
+}
+
+// containerTerminated returns true if the suite container of the pod has terminated.
+func containerTerminated(pod *corev1.Pod) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == suiteContainer {
+			return containerStatus.State.Terminated != nil
+		}
+	}
+	return false
+}
+
+// streamSuiteLogs starts following the suite log of the job into <outputDir>/<jobName>.log.
+// The returned function stops the stream, waiting up to logDrainTimeout for the remaining
+// output when the suite has finished, and reports whether the complete log was captured.
+func (e *Executor) streamSuiteLogs(ctx context.Context, logger logr.Logger, clientSet kubernetes.Interface, jobName, namespace, image, outputDir string) (stop func(finished bool) bool, err error) {
+	file, err := os.Create(filepath.Join(outputDir, jobName+".log"))
+	if err != nil {
+		return nil, fmt.Errorf("creating suite log: %w", err)
+	}
+
+	buildLog := e.buildLog
+	if buildLog == nil {
+		buildLog = log.Writer()
+	}
+
+	stream := &logStream{
+		clientSet: clientSet,
+		logger:    logger,
+		findPod: func(ctx context.Context) (*corev1.Pod, error) {
+			return e.findJobPod(ctx, jobName, namespace)
+		},
+		file:     file,
+		buildLog: buildLog,
+		prefix:   image[strings.LastIndex(image, "/")+1:],
+		interval: logReconnectInterval,
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.run(streamCtx)
+	}()
+
+	return func(finished bool) bool {
+		if finished {
+			select {
+			case <-done:
+			case <-time.After(logDrainTimeout):
+				logger.Info("timed out waiting for suite log to finish")
+			}
+		}
+		cancel()
+		<-done
+		_ = file.Close()
+		return stream.complete
+	}, nil
+}
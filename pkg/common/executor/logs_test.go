@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestStream(findPod func(ctx context.Context) (*corev1.Pod, error)) (*logStream, *bytes.Buffer, *bytes.Buffer) {
+	var file, buildLog bytes.Buffer
+	return &logStream{
+		clientSet: fake.NewSimpleClientset(),
+		logger:    logr.Discard(),
+		findPod:   findPod,
+		file:      &file,
+		buildLog:  &buildLog,
+		prefix:    "suite:latest",
+		interval:  time.Millisecond,
+	}, &file, &buildLog
+}
+
+func suitePod(terminated bool) *corev1.Pod {
+	state := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if terminated {
+		state = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "executor-abc", Namespace: "osde2e-executor-abc"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: suiteContainer, State: state}},
+		},
+	}
+}
+
+func TestCopyLinesSkipsSeenLines(t *testing.T) {
+	stream, file, buildLog := newTestStream(nil)
+
+	first := "2024-01-01T00:00:01.000000000Z one\n2024-01-01T00:00:02.000000000Z two\n"
+	// After a reconnect the API server resends lines from the start of the second.
+	second := "2024-01-01T00:00:02.000000000Z two\n2024-01-01T00:00:03.000000000Z three"
+
+	if err := stream.copyLines(strings.NewReader(first)); err != nil {
+		t.Fatalf("unexpected error copying lines: %v", err)
+	}
+	if err := stream.copyLines(strings.NewReader(second)); err != nil {
+		t.Fatalf("unexpected error copying lines: %v", err)
+	}
+
+	if file.String() != "one\ntwo\nthree\n" {
+		t.Errorf("unexpected suite log:\n%s", file.String())
+	}
+	if !strings.Contains(buildLog.String(), "[suite:latest] three\n") {
+		t.Errorf("expected prefixed lines in build log, got:\n%s", buildLog.String())
+	}
+}
+
+func TestCopyLinesKeepsLinesWithTheSameTimestamp(t *testing.T) {
+	stream, file, _ := newTestStream(nil)
+
+	// Every line of a chunk gets the same timestamp.
+	first := "2024-01-01T00:00:01.000000000Z one\n2024-01-01T00:00:01.000000000Z two\n2024-01-01T00:00:01.000000000Z three\n"
+	second := "2024-01-01T00:00:01.000000000Z one\n2024-01-01T00:00:01.000000000Z two\n2024-01-01T00:00:01.000000000Z three\n" +
+		"2024-01-01T00:00:01.000000000Z four\n2024-01-01T00:00:02.000000000Z five\n"
+
+	if err := stream.copyLines(strings.NewReader(first)); err != nil {
+		t.Fatalf("unexpected error copying lines: %v", err)
+	}
+	if file.String() != "one\ntwo\nthree\n" {
+		t.Errorf("expected every line of the chunk, got:\n%s", file.String())
+	}
+	if err := stream.copyLines(strings.NewReader(second)); err != nil {
+		t.Fatalf("unexpected error copying lines: %v", err)
+	}
+	if file.String() != "one\ntwo\nthree\nfour\nfive\n" {
+		t.Errorf("expected only the resent lines to be skipped, got:\n%s", file.String())
+	}
+}
+
+func TestRunFollowsUntilTerminated(t *testing.T) {
+	calls := 0
+	stream, file, _ := newTestStream(func(ctx context.Context) (*corev1.Pod, error) {
+		calls++
+		switch calls {
+		case 1:
+			return nil, errors.New("pod for job not found")
+		case 2:
+			return suitePod(false), nil
+		default:
+			return suitePod(true), nil
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream.run(ctx)
+
+	if !stream.complete {
+		t.Errorf("expected the log to be complete")
+	}
+	if calls != 3 {
+		t.Errorf("expected the stream to reconnect until the container terminated, got %d pod lookups", calls)
+	}
+	// The fake clientset returns the same log for every request.
+	if file.String() != "fake logs\nfake logs\n" {
+		t.Errorf("unexpected suite log:\n%s", file.String())
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	stream, _, _ := newTestStream(func(ctx context.Context) (*corev1.Pod, error) {
+		return suitePod(false), nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stream.run(ctx)
+
+	if stream.complete {
+		t.Errorf("expected the log of a running container to be incomplete")
+	}
+}
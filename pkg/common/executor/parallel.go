@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Suite is a test suite image, the directory its artifacts are collected into and how it is retried
+type Suite struct {
+	Image     string
+	OutputDir string
+	// MaxAttempts is how often the suite is run at most, including the first run
+	MaxAttempts int
+	// RetryMode is either config.RetryModeImage or config.RetryModeFailedSpecs, config.RetryModeImage is used if empty
+	RetryMode string
+	// Quarantine lists test names whose failures are informing only
+	Quarantine []string
+	// Pod overrides the pod settings of the executor config for this suite
+	Pod PodOverrides
+}
+
+// SuiteResult is the outcome of executing a single Suite
+type SuiteResult struct {
+	Suite Suite
+	// Results are the test results of the first attempt
+	Results *testResults
+	// Err is the execution error of the last attempt
+	Err      error
+	Duration time.Duration
+	Attempts int
+	// Failures are the test failures of the last attempt that fail the suite, including earlier
+	// failures of tests the last attempt didn't run
+	Failures []string
+	// Informing are the failures of quarantined tests
+	Informing []string
+	// Flaky are the names of tests that failed but passed when retried
+	Flaky []string
+}
+
+// ExecuteAll runs the suites with at most parallelism suites running at once. Each suite runs
+// as its own Job in its own project and writes to its own output directory. Results are returned
+// in the same order as suites regardless of the order in which they complete.
+func (e *Executor) ExecuteAll(ctx context.Context, suites []Suite, parallelism int) []SuiteResult {
+	results := make([]SuiteResult, len(suites))
+
+	runBounded(len(suites), parallelism, func(i int) {
+		results[i] = e.ExecuteSuite(ctx, suites[i])
+	})
+
+	return results
+}
+
+// Aggregate sums the test results of all suites
+func Aggregate(results []SuiteResult) *testResults {
+	total := &testResults{}
+	for _, result := range results {
+		if result.Results == nil {
+			continue
+		}
+		total.TotalTests += result.Results.TotalTests
+		total.PassedTests += result.Results.PassedTests
+		total.FailedTests += result.Results.FailedTests
+		total.SkippedTests += result.Results.SkippedTests
+		total.ErrorTests += result.Results.ErrorTests
+		total.Duration += result.Results.Duration
+		total.Suites = append(total.Suites, result.Results.Suites...)
+	}
+	return total
+}
+
+// runBounded calls fn for every index in [0, n) with at most parallelism calls running concurrently.
+// A parallelism below one runs the calls sequentially.
+func runBounded(n, parallelism int, fn func(i int)) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for i := 0; i < n; i++ {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+func TestRunBounded(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism int
+		expectedMax int32
+	}{
+		{name: "sequential", parallelism: 1, expectedMax: 1},
+		{name: "zero is sequential", parallelism: 0, expectedMax: 1},
+		{name: "bounded", parallelism: 3, expectedMax: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak int32
+			var mu sync.Mutex
+			seen := make([]bool, 8)
+
+			runBounded(len(seen), tt.parallelism, func(i int) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&peak)
+					if current <= observed || atomic.CompareAndSwapInt32(&peak, observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+
+				mu.Lock()
+				seen[i] = true
+				mu.Unlock()
+			})
+
+			if peak != tt.expectedMax {
+				t.Errorf("expected at most %d concurrent calls, got %d", tt.expectedMax, peak)
+			}
+			for i, ok := range seen {
+				if !ok {
+					t.Errorf("expected index %d to be run", i)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	results := []SuiteResult{
+		{Results: &testResults{TotalTests: 3, PassedTests: 2, FailedTests: 1, Suites: []junit.Suite{{Name: "a"}}}},
+		{Err: errors.New("pull failed")},
+		{Results: &testResults{TotalTests: 2, SkippedTests: 1, ErrorTests: 1, Suites: []junit.Suite{{Name: "b"}}}},
+	}
+
+	total := Aggregate(results)
+	if total.TotalTests != 5 || total.PassedTests != 2 || total.FailedTests != 1 || total.SkippedTests != 1 || total.ErrorTests != 1 {
+		t.Errorf("unexpected totals: %+v", total)
+	}
+	if len(total.Suites) != 2 || total.Suites[0].Name != "a" || total.Suites[1].Name != "b" {
+		t.Errorf("expected suites in order a, b, got %v", total.Suites)
+	}
+}
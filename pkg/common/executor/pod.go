@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/osde2e/pkg/common/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultArtifactImage is the image of the sidecar that keeps the pod running to collect artifacts
+const DefaultArtifactImage = "registry.access.redhat.com/ubi10/ubi:10.1"
+
+// Mount is a ConfigMap or Secret copied from Namespace into the suite project and mounted at MountPath
+type Mount struct {
+	Name      string
+	Namespace string
+	MountPath string
+}
+
+// PodOverrides customizes the pod a suite runs in. Unset fields keep the defaults.
+type PodOverrides struct {
+	// Resources of the suite container
+	Resources corev1.ResourceRequirements
+	// NodeSelector and Tolerations place the suite pod, e.g. on infra nodes
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+	// ServiceAccount is the name of the service account created for the suite, the RBAC profile name by default
+	ServiceAccount string
+	// RBAC is the permission profile of the service account, config.RBACProfileClusterAdmin by default
+	RBAC RBACProfile
+	// Env is added to the environment of the suite container
+	Env []corev1.EnvVar
+	// ConfigMaps and Secrets are mounted into the suite container
+	ConfigMaps []Mount
+	Secrets    []Mount
+	// ArtifactImage replaces DefaultArtifactImage
+	ArtifactImage string
+}
+
+// merge returns the overrides with every field set in override replacing the field of p. Env and mounts are appended.
+func (p PodOverrides) merge(override PodOverrides) PodOverrides {
+	merged := p
+	if len(override.Resources.Requests) > 0 || len(override.Resources.Limits) > 0 {
+		merged.Resources = override.Resources
+	}
+	if len(override.NodeSelector) > 0 {
+		merged.NodeSelector = override.NodeSelector
+	}
+	if len(override.Tolerations) > 0 {
+		merged.Tolerations = override.Tolerations
+	}
+	if override.ServiceAccount != "" {
+		merged.ServiceAccount = override.ServiceAccount
+	}
+	if override.RBAC.Name != "" {
+		merged.RBAC = override.RBAC
+	}
+	if override.ArtifactImage != "" {
+		merged.ArtifactImage = override.ArtifactImage
+	}
+	merged.Env = append(append([]corev1.EnvVar{}, p.Env...), override.Env...)
+	merged.ConfigMaps = append(append([]Mount{}, p.ConfigMaps...), override.ConfigMaps...)
+	merged.Secrets = append(append([]Mount{}, p.Secrets...), override.Secrets...)
+	return merged
+}
+
+// withDefaults fills the unset fields that always need a value
+func (p PodOverrides) withDefaults() PodOverrides {
+	if p.RBAC.Name == "" {
+		p.RBAC.Name = config.RBACProfileClusterAdmin
+	}
+	if p.ServiceAccount == "" {
+		p.ServiceAccount = p.RBAC.Name
+	}
+	if p.ArtifactImage == "" {
+		p.ArtifactImage = DefaultArtifactImage
+	}
+	return p
+}
+
+// ParseResources parses resource quantities such as {"cpu": "100m", "memory": "1Gi"} into resource requirements
+func ParseResources(requests, limits map[string]string) (corev1.ResourceRequirements, error) {
+	var resources corev1.ResourceRequirements
+	var err error
+
+	if resources.Requests, err = parseResourceList(requests); err != nil {
+		return resources, fmt.Errorf("parsing resource requests: %w", err)
+	}
+	if resources.Limits, err = parseResourceList(limits); err != nil {
+		return resources, fmt.Errorf("parsing resource limits: %w", err)
+	}
+	return resources, nil
+}
+
+func parseResourceList(quantities map[string]string) (corev1.ResourceList, error) {
+	if len(quantities) == 0 {
+		return nil, nil
+	}
+
+	list := corev1.ResourceList{}
+	for name, value := range quantities {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s: %w", value, name, err)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// applyPodOverrides applies the overrides to the suite job pod
+func applyPodOverrides(spec *corev1.PodSpec, pod PodOverrides) {
+	spec.NodeSelector = pod.NodeSelector
+	spec.Tolerations = pod.Tolerations
+
+	suite := &spec.Containers[0]
+	suite.Resources = pod.Resources
+	suite.Env = append(suite.Env, pod.Env...)
+
+	for i, mount := range pod.ConfigMaps {
+		volume := fmt.Sprintf("configmap-%d", i)
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: volume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: mount.Name}},
+			},
+		})
+		suite.VolumeMounts = append(suite.VolumeMounts, corev1.VolumeMount{Name: volume, MountPath: mount.MountPath, ReadOnly: true})
+	}
+
+	for i, mount := range pod.Secrets {
+		volume := fmt.Sprintf("secret-%d", i)
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: volume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: mount.Name},
+			},
+		})
+		suite.VolumeMounts = append(suite.VolumeMounts, corev1.VolumeMount{Name: volume, MountPath: mount.MountPath, ReadOnly: true})
+	}
+}
+
+// copyMounts copies the mounted ConfigMaps and Secrets from their namespaces into the suite project
+func (e *Executor) copyMounts(ctx context.Context, namespace string, pod PodOverrides) error {
+	for _, mount := range pod.ConfigMaps {
+		if mount.Namespace == "" {
+			return fmt.Errorf("namespace of configmap %s is not set", mount.Name)
+		}
+		source := new(corev1.ConfigMap)
+		if err := e.oc.Get(ctx, mount.Name, mount.Namespace, source); err != nil {
+			return fmt.Errorf("getting configmap %s/%s: %w", mount.Namespace, mount.Name, err)
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: mount.Name, Namespace: namespace},
+			Data:       source.Data,
+			BinaryData: source.BinaryData,
+		}
+		if err := e.oc.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("creating configmap %s: %w", mount.Name, err)
+		}
+	}
+
+	for _, mount := range pod.Secrets {
+		if mount.Namespace == "" {
+			return fmt.Errorf("namespace of secret %s is not set", mount.Name)
+		}
+		source := new(corev1.Secret)
+		if err := e.oc.Get(ctx, mount.Name, mount.Namespace, source); err != nil {
+			return fmt.Errorf("getting secret %s/%s: %w", mount.Namespace, mount.Name, err)
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: mount.Name, Namespace: namespace},
+			Type:       source.Type,
+			Data:       source.Data,
+		}
+		if err := e.oc.Create(ctx, secret); err != nil {
+			return fmt.Errorf("creating secret %s: %w", mount.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodOverridesMerge(t *testing.T) {
+	base := PodOverrides{
+		ServiceAccount: "base",
+		NodeSelector:   map[string]string{"role": "worker"},
+		Env:            []corev1.EnvVar{{Name: "A", Value: "1"}},
+		ArtifactImage:  "artifacts:base",
+	}
+	override := PodOverrides{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Env:          []corev1.EnvVar{{Name: "B", Value: "2"}},
+		Secrets:      []Mount{{Name: "creds", Namespace: "ci", MountPath: "/creds"}},
+	}
+
+	merged := base.merge(override)
+	if merged.ServiceAccount != "base" || merged.ArtifactImage != "artifacts:base" {
+		t.Errorf("expected unset fields to keep the base values, got %+v", merged)
+	}
+	if _, ok := merged.NodeSelector["node-role.kubernetes.io/infra"]; !ok || len(merged.NodeSelector) != 1 {
+		t.Errorf("expected node selector to be replaced, got %v", merged.NodeSelector)
+	}
+	if len(merged.Env) != 2 || len(merged.Secrets) != 1 {
+		t.Errorf("expected env and mounts to be appended, got %+v", merged)
+	}
+	if len(base.Env) != 1 {
+		t.Errorf("expected base env to be unchanged, got %v", base.Env)
+	}
+}
+
+func TestPodOverridesWithDefaults(t *testing.T) {
+	pod := PodOverrides{}.withDefaults()
+	if pod.ServiceAccount != config.RBACProfileClusterAdmin || pod.RBAC.Name != config.RBACProfileClusterAdmin || pod.ArtifactImage != DefaultArtifactImage {
+		t.Errorf("unexpected defaults: %+v", pod)
+	}
+
+	pod = PodOverrides{RBAC: RBACProfile{Name: config.RBACProfileReadOnly}}.withDefaults()
+	if pod.ServiceAccount != config.RBACProfileReadOnly {
+		t.Errorf("expected the service account to be named after the profile, got %+v", pod)
+	}
+
+	pod = PodOverrides{ServiceAccount: "reader", RBAC: RBACProfile{Name: config.RBACProfileReadOnly}}.withDefaults()
+	if pod.ServiceAccount != "reader" || pod.RBAC.Name != config.RBACProfileReadOnly {
+		t.Errorf("expected overrides to be kept, got %+v", pod)
+	}
+}
+
+func TestParseResources(t *testing.T) {
+	resources, err := ParseResources(map[string]string{"cpu": "500m", "memory": "1Gi"}, map[string]string{"memory": "2Gi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resources.Requests.Cpu().Equal(resource.MustParse("500m")) || !resources.Limits.Memory().Equal(resource.MustParse("2Gi")) {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+	if resources.Limits.Cpu().Value() != 0 {
+		t.Errorf("expected no cpu limit, got %v", resources.Limits.Cpu())
+	}
+
+	if _, err := ParseResources(nil, map[string]string{"cpu": "lots"}); err == nil {
+		t.Errorf("expected an error for an invalid quantity")
+	}
+}
+
+func TestBuildJobSpecOverrides(t *testing.T) {
+	e := &Executor{cfg: &Config{Timeout: time.Minute}}
+	pod := PodOverrides{
+		ServiceAccount: "reader",
+		NodeSelector:   map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:    []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Effect: corev1.TaintEffectNoSchedule}},
+		Env:            []corev1.EnvVar{{Name: "EXTRA", Value: "1"}},
+		ConfigMaps:     []Mount{{Name: "settings", Namespace: "ci", MountPath: "/settings"}},
+		Secrets:        []Mount{{Name: "creds", Namespace: "ci", MountPath: "/creds"}},
+		ArtifactImage:  "artifacts:latest",
+	}
+
+	spec := e.buildJobSpec("osde2e-executor", "suite:latest", pod).Spec.Template.Spec
+	if spec.ServiceAccountName != "reader" || len(spec.NodeSelector) != 1 || len(spec.Tolerations) != 1 {
+		t.Errorf("expected placement and service account overrides, got %+v", spec)
+	}
+	if spec.Containers[1].Image != "artifacts:latest" {
+		t.Errorf("expected artifact image override, got %s", spec.Containers[1].Image)
+	}
+
+	suite := spec.Containers[0]
+	if last := suite.Env[len(suite.Env)-1]; last.Name != "EXTRA" {
+		t.Errorf("expected extra env to be added, got %v", suite.Env)
+	}
+	if len(spec.Volumes) != 3 || len(suite.VolumeMounts) != 3 {
+		t.Fatalf("expected results, configmap and secret volumes, got %+v", spec.Volumes)
+	}
+	if spec.Volumes[1].ConfigMap == nil || spec.Volumes[2].Secret == nil || spec.Volumes[2].Secret.SecretName != "creds" {
+		t.Errorf("unexpected volumes: %+v", spec.Volumes)
+	}
+	if mount := suite.VolumeMounts[2]; mount.MountPath != "/creds" || !mount.ReadOnly {
+		t.Errorf("unexpected secret mount: %+v", mount)
+	}
+}
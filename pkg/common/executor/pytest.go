@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// pytestReport is the subset of a pytest-json-report document that is needed for the results
+type pytestReport struct {
+	Tests *[]pytestTest `json:"tests"`
+}
+
+type pytestTest struct {
+	NodeID   string       `json:"nodeid"`
+	Outcome  string       `json:"outcome"`
+	Setup    *pytestStage `json:"setup"`
+	Call     *pytestStage `json:"call"`
+	Teardown *pytestStage `json:"teardown"`
+}
+
+type pytestStage struct {
+	Duration float64 `json:"duration"`
+	Outcome  string  `json:"outcome"`
+	Crash    *struct {
+		Message string `json:"message"`
+	} `json:"crash"`
+	LongRepr string `json:"longrepr"`
+	Stdout   string `json:"stdout"`
+}
+
+// pytestParser reads the JSON reports of the pytest-json-report plugin
+type pytestParser struct{}
+
+func (pytestParser) Name() string { return "pytest" }
+
+func (pytestParser) Match(fileName string) bool {
+	return strings.HasSuffix(strings.ToLower(fileName), ".json")
+}
+
+// Recognize looks for the exitcode key, which the plugin writes near the start of the report, before
+// the environment and the tests
+func (pytestParser) Recognize(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("{")) && bytes.Contains(head, []byte(`"exitcode"`))
+}
+
+// Parse builds a suite per test module. Tests that fail in setup or teardown are reported as errors,
+// as pytest does, and expected failures as skipped.
+func (pytestParser) Parse(_ string, data []byte) ([]junit.Suite, error) {
+	var report pytestReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	if report.Tests == nil {
+		return nil, errors.New("not a pytest json report")
+	}
+
+	var suites []junit.Suite
+	modules := map[string]int{}
+	for _, pt := range *report.Tests {
+		module, name, found := strings.Cut(pt.NodeID, "::")
+		if !found {
+			name = pt.NodeID
+		}
+
+		index, ok := modules[module]
+		if !ok {
+			index = len(suites)
+			modules[module] = index
+			suites = append(suites, junit.Suite{Name: module, Package: module})
+		}
+
+		test := junit.Test{Name: name, Classname: module}
+		var failed *pytestStage
+		for _, stage := range []*pytestStage{pt.Setup, pt.Call, pt.Teardown} {
+			if stage == nil {
+				continue
+			}
+			test.Duration += time.Duration(stage.Duration * float64(time.Second))
+			test.SystemOut += stage.Stdout
+			if failed == nil && stage.Outcome == "failed" {
+				failed = stage
+			}
+		}
+
+		switch pt.Outcome {
+		case "passed", "xpassed":
+			test.Status = junit.StatusPassed
+		case "skipped", "xfailed":
+			test.Status = junit.StatusSkipped
+			if failed != nil {
+				test.Message = failed.LongRepr
+			}
+		case "failed":
+			test.Status = junit.StatusFailed
+			test.Error = pytestError(failed)
+		case "error":
+			test.Status = junit.StatusError
+			test.Error = pytestError(failed)
+		default:
+			return nil, fmt.Errorf("unknown outcome %q for test %s", pt.Outcome, pt.NodeID)
+		}
+		suites[index].Tests = append(suites[index].Tests, test)
+	}
+
+	for i := range suites {
+		suites[i].Aggregate()
+	}
+	return suites, nil
+}
+
+// pytestError converts the failed stage of a test into an error
+func pytestError(stage *pytestStage) junit.Error {
+	if stage == nil {
+		return junit.Error{Message: "test failed"}
+	}
+	err := junit.Error{Message: "test failed", Body: stage.LongRepr}
+	if stage.Crash != nil && stage.Crash.Message != "" {
+		err.Message = stage.Crash.Message
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	projectv1 "github.com/openshift/api/project/v1"
+	"github.com/openshift/osde2e/pkg/common/config"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// dedicatedAdminsGroup is the group of customer admins, the same group the helper impersonates in AsDedicatedAdmin
+	dedicatedAdminsGroup = "dedicated-admins"
+	// readOnlyClusterRole can read all cluster resources except secrets
+	readOnlyClusterRole = "cluster-reader"
+	// projectAdminRole is bound in the suite project for dedicated admins, who administer their own projects
+	projectAdminRole = "admin"
+)
+
+// RBACProfile selects the permissions of the suite service account
+type RBACProfile struct {
+	// Name is one of the config.RBACProfile constants, config.RBACProfileClusterAdmin if empty
+	Name string
+	// ClusterRoles are bound for config.RBACProfileCustom
+	ClusterRoles []string
+}
+
+// clusterRoles returns the cluster roles bound to the suite service account for the profile
+func (e *Executor) clusterRoles(ctx context.Context, profile RBACProfile) ([]string, error) {
+	switch profile.Name {
+	case config.RBACProfileClusterAdmin:
+		return []string{config.RBACProfileClusterAdmin}, nil
+	case config.RBACProfileReadOnly:
+		return []string{readOnlyClusterRole}, nil
+	case config.RBACProfileCustom:
+		if len(profile.ClusterRoles) == 0 {
+			return nil, fmt.Errorf("rbac profile %s requires at least one cluster role", config.RBACProfileCustom)
+		}
+		return profile.ClusterRoles, nil
+	case config.RBACProfileDedicatedAdmin:
+		return e.groupClusterRoles(ctx, dedicatedAdminsGroup)
+	default:
+		return nil, fmt.Errorf("unknown rbac profile %q", profile.Name)
+	}
+}
+
+// groupClusterRoles returns the cluster roles bound to a group on the cluster, so a service account
+// bound to them can do what an impersonated member of the group can do
+func (e *Executor) groupClusterRoles(ctx context.Context, group string) ([]string, error) {
+	bindings := new(rbacv1.ClusterRoleBindingList)
+	if err := e.oc.List(ctx, bindings); err != nil {
+		return nil, fmt.Errorf("listing cluster role bindings: %w", err)
+	}
+
+	roles := boundClusterRoles(bindings.Items, group)
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("no cluster roles are bound to group %s", group)
+	}
+	return roles, nil
+}
+
+// boundClusterRoles returns the sorted names of the cluster roles the bindings grant to a group
+func boundClusterRoles(bindings []rbacv1.ClusterRoleBinding, group string) []string {
+	var roles []string
+	for _, binding := range bindings {
+		if binding.RoleRef.Kind != "ClusterRole" || slices.Contains(roles, binding.RoleRef.Name) {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			if subject.Kind == rbacv1.GroupKind && subject.Name == group {
+				roles = append(roles, binding.RoleRef.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// groupRoleBindings returns the namespaced role bindings that grant roles to a group, e.g. in the
+// namespaces dedicated admins manage on managed clusters
+func (e *Executor) groupRoleBindings(ctx context.Context, group string) ([]rbacv1.RoleBinding, error) {
+	bindings := new(rbacv1.RoleBindingList)
+	if err := e.oc.List(ctx, bindings); err != nil {
+		return nil, fmt.Errorf("listing role bindings: %w", err)
+	}
+	return boundRoleBindings(bindings.Items, group), nil
+}
+
+// boundRoleBindings returns the bindings that grant a role to a group
+func boundRoleBindings(bindings []rbacv1.RoleBinding, group string) []rbacv1.RoleBinding {
+	var bound []rbacv1.RoleBinding
+	for _, binding := range bindings {
+		for _, subject := range binding.Subjects {
+			if subject.Kind == rbacv1.GroupKind && subject.Name == group {
+				bound = append(bound, binding)
+				break
+			}
+		}
+	}
+	return bound
+}
+
+// bindProfile grants the suite service account the permissions of the profile. The bindings are owned
+// by the suite project so they are removed with it.
+func (e *Executor) bindProfile(ctx context.Context, logger logr.Logger, project *projectv1.Project, serviceAccount string, profile RBACProfile) error {
+	roles, err := e.clusterRoles(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	owner := *metav1.NewControllerRef(project.DeepCopy(), schema.FromAPIVersionAndKind("project.openshift.io/v1", "Project"))
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccount,
+			Namespace: project.Name,
+		},
+	}
+
+	for _, role := range roles {
+		crb := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName:    "osde2e-executor-" + role + "-",
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Subjects: subjects,
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     role,
+			},
+		}
+		if err := e.oc.Create(ctx, crb); err != nil {
+			return fmt.Errorf("creating cluster role binding for %s: %w", role, err)
+		}
+	}
+
+	if profile.Name == config.RBACProfileDedicatedAdmin {
+		bindings, err := e.groupRoleBindings(ctx, dedicatedAdminsGroup)
+		if err != nil {
+			return err
+		}
+		for _, binding := range bindings {
+			rb := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName:    "osde2e-executor-" + binding.RoleRef.Name + "-",
+					Namespace:       binding.Namespace,
+					OwnerReferences: []metav1.OwnerReference{owner},
+				},
+				Subjects: subjects,
+				RoleRef:  binding.RoleRef,
+			}
+			if err := e.oc.Create(ctx, rb); err != nil {
+				return fmt.Errorf("creating role binding for %s in %s: %w", binding.RoleRef.Name, binding.Namespace, err)
+			}
+		}
+
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osde2e-executor-" + projectAdminRole,
+				Namespace: project.Name,
+			},
+			Subjects: subjects,
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     projectAdminRole,
+			},
+		}
+		if err := e.oc.Create(ctx, rb); err != nil {
+			return fmt.Errorf("creating role binding for %s: %w", projectAdminRole, err)
+		}
+	}
+
+	logger.Info("bound rbac profile", "profile", profile.Name, "clusterRoles", roles)
+	return nil
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/openshift/osde2e/pkg/common/config"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterRoles(t *testing.T) {
+	e := &Executor{cfg: &Config{}}
+
+	tests := []struct {
+		profile RBACProfile
+		want    []string
+		wantErr bool
+	}{
+		{profile: RBACProfile{Name: config.RBACProfileClusterAdmin}, want: []string{"cluster-admin"}},
+		{profile: RBACProfile{Name: config.RBACProfileReadOnly}, want: []string{"cluster-reader"}},
+		{profile: RBACProfile{Name: config.RBACProfileCustom, ClusterRoles: []string{"view", "custom-role"}}, want: []string{"view", "custom-role"}},
+		{profile: RBACProfile{Name: config.RBACProfileCustom}, wantErr: true},
+		{profile: RBACProfile{Name: "superuser"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		roles, err := e.clusterRoles(context.Background(), tt.profile)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error: %v", tt.profile.Name, err)
+		}
+		if !slices.Equal(roles, tt.want) {
+			t.Errorf("%s: expected roles %v, got %v", tt.profile.Name, tt.want, roles)
+		}
+	}
+}
+
+func TestBoundClusterRoles(t *testing.T) {
+	group := rbacv1.Subject{Kind: rbacv1.GroupKind, Name: dedicatedAdminsGroup}
+	binding := func(role string, subjects ...rbacv1.Subject) rbacv1.ClusterRoleBinding {
+		return rbacv1.ClusterRoleBinding{
+			Subjects: subjects,
+			RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: role},
+		}
+	}
+
+	bindings := []rbacv1.ClusterRoleBinding{
+		binding("dedicated-admins-cluster", group),
+		binding("cluster-admin", rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "cluster-admins"}),
+		binding("dedicated-admins-cluster", group),
+		binding("dedicated-readers", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "someone"}, group),
+		binding("dedicated-admins", rbacv1.Subject{Kind: rbacv1.UserKind, Name: dedicatedAdminsGroup}),
+	}
+
+	roles := boundClusterRoles(bindings, dedicatedAdminsGroup)
+	if want := []string{"dedicated-admins-cluster", "dedicated-readers"}; !slices.Equal(roles, want) {
+		t.Errorf("expected roles %v, got %v", want, roles)
+	}
+}
+
+func TestBoundRoleBindings(t *testing.T) {
+	group := rbacv1.Subject{Kind: rbacv1.GroupKind, Name: dedicatedAdminsGroup}
+	binding := func(namespace, role string, subjects ...rbacv1.Subject) rbacv1.RoleBinding {
+		return rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: role},
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: role},
+		}
+	}
+
+	bindings := []rbacv1.RoleBinding{
+		binding("openshift-logging", "dedicated-admins-openshift-logging", group),
+		binding("openshift-logging", "logging-operator", rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "logging"}),
+		binding("openshift-ingress", "dedicated-admins-openshift-ingress", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "someone"}, group),
+	}
+
+	var bound []string
+	for _, binding := range boundRoleBindings(bindings, dedicatedAdminsGroup) {
+		bound = append(bound, binding.Namespace+"/"+binding.RoleRef.Name)
+	}
+	if want := []string{"openshift-logging/dedicated-admins-openshift-logging", "openshift-ingress/dedicated-admins-openshift-ingress"}; !slices.Equal(bound, want) {
+		t.Errorf("expected bindings %v, got %v", want, bound)
+	}
+}
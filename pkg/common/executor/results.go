@@ -0,0 +1,209 @@
+package executor
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// ResultParser reads test results in a format written by suite images and normalizes them into JUnit suites
+type ResultParser interface {
+	// Name identifies the format in logs and in the name of the normalized JUnit file
+	Name() string
+	// Match reports whether a file with this name may hold results in the format
+	Match(fileName string) bool
+	// Recognize reports whether a file that Matches holds results in the format, given the first
+	// resultHeadSize bytes of it. Other files are left alone, as artifacts often share the extension.
+	Recognize(head []byte) bool
+	// Parse converts the file contents into JUnit suites. Failed tests must have an error set.
+	Parse(fileName string, data []byte) ([]junit.Suite, error)
+}
+
+// resultHeadSize is how much of a file the parsers look at to recognize their format
+const resultHeadSize = 512
+
+// resultParsers are tried in order, the first one that matches a file and parses it wins
+var resultParsers = []ResultParser{
+	junitParser{},
+	tapParser{},
+	goTestParser{},
+	pytestParser{},
+}
+
+// RegisterResultParser adds a parser for another result format. It must be called before suites are executed.
+func RegisterResultParser(parser ResultParser) {
+	resultParsers = append(resultParsers, parser)
+}
+
+// matchingParsers returns the parsers that may read a file with this name
+func matchingParsers(fileName string) []ResultParser {
+	var parsers []ResultParser
+	for _, parser := range resultParsers {
+		if parser.Match(fileName) {
+			parsers = append(parsers, parser)
+		}
+	}
+	return parsers
+}
+
+// isResultFile reports whether any parser recognizes the file as its format, only the head of the
+// file is read
+func isResultFile(path string) (bool, error) {
+	parsers := matchingParsers(filepath.Base(path))
+	if len(parsers) == 0 {
+		return false, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	head := make([]byte, resultHeadSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	for _, parser := range parsers {
+		if parser.Recognize(head[:n]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resultHead returns the part of the data the parsers recognize their format by
+func resultHead(data []byte) []byte {
+	return data[:min(len(data), resultHeadSize)]
+}
+
+// parseResultFile parses a result file with the first parser that can read it
+func parseResultFile(path string) ([]junit.Suite, ResultParser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading result file: %w", err)
+	}
+
+	fileName := filepath.Base(path)
+	var errs []string
+	for _, parser := range matchingParsers(fileName) {
+		if !parser.Recognize(resultHead(data)) {
+			continue
+		}
+		suites, err := parser.Parse(fileName, data)
+		if err == nil {
+			return suites, parser, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", parser.Name(), err))
+	}
+	return nil, nil, fmt.Errorf("no parser could read the file: %s", strings.Join(errs, "; "))
+}
+
+// normalizedJUnitPath is where the JUnit version of a non-JUnit result file is written. The name
+// contains "junit" so the analysis engine picks the file up like a Ginkgo report.
+func normalizedJUnitPath(path string, parser ResultParser) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf("junit_%s_%s.xml", parser.Name(), base))
+}
+
+type junitXMLSuites struct {
+	XMLName xml.Name        `xml:"testsuites"`
+	Suites  []junitXMLSuite `xml:"testsuite"`
+}
+
+type junitXMLSuite struct {
+	Name      string         `xml:"name,attr"`
+	Package   string         `xml:"package,attr,omitempty"`
+	Tests     int            `xml:"tests,attr"`
+	Failures  int            `xml:"failures,attr"`
+	Errors    int            `xml:"errors,attr"`
+	Skipped   int            `xml:"skipped,attr"`
+	Time      string         `xml:"time,attr"`
+	TestCases []junitXMLCase `xml:"testcase"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitXMLCase struct {
+	Name      string           `xml:"name,attr"`
+	Classname string           `xml:"classname,attr"`
+	Time      string           `xml:"time,attr"`
+	Failure   *junitXMLFailure `xml:"failure,omitempty"`
+	Error     *junitXMLFailure `xml:"error,omitempty"`
+	Skipped   *junitXMLFailure `xml:"skipped,omitempty"`
+	SystemOut string           `xml:"system-out,omitempty"`
+}
+
+type junitXMLFailure struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnitFile writes the suites as a JUnit XML report
+func writeJUnitFile(path string, suites []junit.Suite) error {
+	report := junitXMLSuites{}
+	for _, suite := range suites {
+		suite.Aggregate()
+		xmlSuite := junitXMLSuite{
+			Name:      suite.Name,
+			Package:   suite.Package,
+			Tests:     suite.Totals.Tests,
+			Failures:  suite.Totals.Failed,
+			Errors:    suite.Totals.Error,
+			Skipped:   suite.Totals.Skipped,
+			Time:      xmlDuration(suite.Totals.Duration),
+			SystemOut: suite.SystemOut,
+		}
+
+		for _, test := range suite.Tests {
+			xmlCase := junitXMLCase{
+				Name:      test.Name,
+				Classname: test.Classname,
+				Time:      xmlDuration(test.Duration),
+				SystemOut: test.SystemOut,
+			}
+			switch test.Status {
+			case junit.StatusFailed:
+				xmlCase.Failure = xmlFailure(test)
+			case junit.StatusError:
+				xmlCase.Error = xmlFailure(test)
+			case junit.StatusSkipped:
+				xmlCase.Skipped = &junitXMLFailure{Message: test.Message}
+			}
+			xmlSuite.TestCases = append(xmlSuite.TestCases, xmlCase)
+		}
+		report.Suites = append(report.Suites, xmlSuite)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding junit xml: %w", err)
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), data...), 0o644)
+}
+
+func xmlFailure(test junit.Test) *junitXMLFailure {
+	failure := &junitXMLFailure{Message: test.Message}
+	if junitErr, ok := test.Error.(junit.Error); ok {
+		failure.Type = junitErr.Type
+		failure.Body = junitErr.Body
+		if failure.Message == "" {
+			failure.Message = junitErr.Message
+		}
+	} else if test.Error != nil {
+		failure.Body = test.Error.Error()
+	}
+	return failure
+}
+
+func xmlDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,230 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/joshdk/go-junit"
+)
+
+const tapOutput = `TAP version 13
+1..4
+ok 1 - creates the namespace
+not ok 2 - deletes the namespace
+  ---
+  message: namespace still exists
+  ...
+ok 3 - checks quota # SKIP no quota on this cluster
+    # Subtest: nested
+    ok 1 - ignored
+ok 4
+`
+
+const goTestOutput = `{"Action":"start","Package":"example.com/suite"}
+{"Action":"run","Package":"example.com/suite","Test":"TestPass"}
+{"Action":"output","Package":"example.com/suite","Test":"TestPass","Output":"=== RUN   TestPass\n"}
+{"Action":"pass","Package":"example.com/suite","Test":"TestPass","Elapsed":1.5}
+{"Action":"run","Package":"example.com/suite","Test":"TestFail"}
+{"Action":"output","Package":"example.com/suite","Test":"TestFail","Output":"    suite_test.go:12: expected 1, got 2\n"}
+{"Action":"fail","Package":"example.com/suite","Test":"TestFail","Elapsed":0.1}
+{"Action":"run","Package":"example.com/suite","Test":"TestHang"}
+{"Action":"fail","Package":"example.com/suite","Elapsed":2}
+{"Action":"output","Package":"example.com/broken","Output":"broken.go:3: undefined: x\n"}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0}
+`
+
+const pytestOutput = `{
+  "created": 1700000000.0,
+  "exitcode": 1,
+  "tests": [
+    {"nodeid": "tests/test_api.py::test_get", "outcome": "passed", "call": {"duration": 0.5, "outcome": "passed"}},
+    {"nodeid": "tests/test_api.py::test_post", "outcome": "failed",
+     "setup": {"duration": 0.1, "outcome": "passed"},
+     "call": {"duration": 0.2, "outcome": "failed", "crash": {"message": "AssertionError: 500 != 201"}, "longrepr": "def test_post():\n>   assert 500 == 201"}},
+    {"nodeid": "tests/test_db.py::TestDB::test_connect", "outcome": "error",
+     "setup": {"duration": 0.1, "outcome": "failed", "longrepr": "fixture 'db' not found"}},
+    {"nodeid": "tests/test_db.py::TestDB::test_flaky", "outcome": "xfailed"}
+  ]
+}`
+
+func statuses(suite junit.Suite) string {
+	var statuses []string
+	for _, test := range suite.Tests {
+		statuses = append(statuses, test.Name+"="+string(test.Status))
+	}
+	return strings.Join(statuses, ",")
+}
+
+func TestTAPParser(t *testing.T) {
+	suites, err := tapParser{}.Parse("smoke.tap", []byte(tapOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suites) != 1 || suites[0].Name != "smoke" {
+		t.Fatalf("expected a single smoke suite, got %+v", suites)
+	}
+
+	want := "creates the namespace=passed,deletes the namespace=failed,checks quota=skipped,test 4=passed"
+	if got := statuses(suites[0]); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if err := suites[0].Tests[1].Error; err == nil || !strings.Contains(err.Error(), "namespace still exists") {
+		t.Errorf("expected diagnostics in the failure, got %v", err)
+	}
+
+	if _, err := (tapParser{}).Parse("empty.tap", []byte("not tap at all\n")); err == nil {
+		t.Errorf("expected an error for a file without tap output")
+	}
+}
+
+func TestTAPParserBailOut(t *testing.T) {
+	suites, err := tapParser{}.Parse("bail.tap", []byte("1..3\nok 1 - first\nBail out! cluster unreachable\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := statuses(suites[0]); got != "first=passed,bail out=error" {
+		t.Errorf("unexpected tests: %s", got)
+	}
+}
+
+func TestGoTestParser(t *testing.T) {
+	suites, err := goTestParser{}.Parse("results.json", []byte(goTestOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suites) != 2 {
+		t.Fatalf("expected a suite per package, got %d", len(suites))
+	}
+
+	if got := statuses(suites[0]); got != "TestPass=passed,TestFail=failed,TestHang=error" {
+		t.Errorf("unexpected tests: %s", got)
+	}
+	if err := suites[0].Tests[1].Error; err == nil || !strings.Contains(err.Error(), "expected 1, got 2") {
+		t.Errorf("expected the test output in the failure, got %v", err)
+	}
+	if d := suites[0].Tests[0].Duration.Seconds(); d != 1.5 {
+		t.Errorf("expected a duration of 1.5s, got %v", d)
+	}
+
+	if got := statuses(suites[1]); got != "example.com/broken=error" {
+		t.Errorf("expected the package failure to be reported, got %s", got)
+	}
+
+	if _, err := (goTestParser{}).Parse("results.json", []byte(pytestOutput)); err == nil {
+		t.Errorf("expected an error for a pytest report")
+	}
+}
+
+func TestPytestParser(t *testing.T) {
+	suites, err := pytestParser{}.Parse("report.json", []byte(pytestOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suites) != 2 || suites[0].Name != "tests/test_api.py" {
+		t.Fatalf("expected a suite per module, got %+v", suites)
+	}
+
+	if got := statuses(suites[0]); got != "test_get=passed,test_post=failed" {
+		t.Errorf("unexpected tests: %s", got)
+	}
+	if got := statuses(suites[1]); got != "TestDB::test_connect=error,TestDB::test_flaky=skipped" {
+		t.Errorf("unexpected tests: %s", got)
+	}
+
+	failure, ok := suites[0].Tests[1].Error.(junit.Error)
+	if !ok || failure.Message != "AssertionError: 500 != 201" || !strings.Contains(failure.Body, "assert 500 == 201") {
+		t.Errorf("unexpected failure: %+v", suites[0].Tests[1].Error)
+	}
+	if err := suites[1].Tests[0].Error; err == nil || !strings.Contains(err.Error(), "fixture 'db' not found") {
+		t.Errorf("expected the setup failure, got %v", err)
+	}
+
+	if _, err := (pytestParser{}).Parse("other.json", []byte(`{"kind": "ConfigMap"}`)); err == nil {
+		t.Errorf("expected an error for other json documents")
+	}
+}
+
+func TestIsResultFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "junit_e2e.xml", content: `<?xml version="1.0"?>` + "\n" + `<testsuites tests="1"></testsuites>`, want: true},
+		{name: "smoke.tap", content: tapOutput, want: true},
+		{name: "results.json", content: goTestOutput, want: true},
+		{name: "report.json", content: pytestOutput, want: true},
+		{name: "cluster-info.json", content: `{"kind": "ConfigMap", "data": {"tests": "[]"}}`},
+		{name: "empty.json"},
+		{name: "pod.xml", content: `<?xml version="1.0"?>` + "\n" + `<pod name="suite"></pod>`},
+		{name: "suite.log", content: tapOutput},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := isResultFile(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestProcessResults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"smoke.tap":          tapOutput,
+		"go/results.json":    goTestOutput,
+		"pytest/report.json": pytestOutput,
+		"cluster-info.json":  `{"kind": "ConfigMap"}`,
+		"suite.log":          "not a result file",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := processResults(logr.Discard(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.TotalTests != 12 || results.FailedTests != 3 || results.ErrorTests != 3 || results.SkippedTests != 2 {
+		t.Errorf("unexpected totals: %+v", results)
+	}
+	if failures := failedTests(results); len(failures) != 6 {
+		t.Errorf("expected 6 failures, got %+v", failures)
+	}
+
+	// The normalized reports can be read like Ginkgo reports and hold the same results
+	for _, path := range []string{"junit_tap_smoke.xml", "go/junit_gotest_results.xml", "pytest/junit_pytest_report.xml"} {
+		suites, err := junit.IngestFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatalf("reading %s: %v", path, err)
+		}
+		if len(suites) == 0 || len(suites[0].Tests) == 0 {
+			t.Errorf("expected tests in %s", path)
+		}
+	}
+
+	normalized, err := processResults(logr.Discard(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.TotalTests != 2*results.TotalTests || normalized.FailedTests != 2*results.FailedTests || normalized.ErrorTests != 2*results.ErrorTests {
+		t.Errorf("expected the normalized reports to hold the same results, got %+v", normalized)
+	}
+}
@@ -0,0 +1,222 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/joshdk/go-junit"
+	"github.com/openshift/osde2e/pkg/common/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// focusEnv is the environment variable used to tell the suite image which specs to rerun
+	focusEnv = "GINKGO_FOCUS"
+
+	// notRunMessage prefixes the last failure of a test that didn't run again when retried
+	notRunMessage = "did not run when retried, last failure: "
+)
+
+// nodeTypePrefix matches the node type Ginkgo puts in front of spec names in JUnit reports, e.g. "[It] "
+var nodeTypePrefix = regexp.MustCompile(`^\[[A-Za-z]+\] `)
+
+// labelSuffix matches the labels Ginkgo appends to spec names in JUnit reports, e.g. " [Informing, Slow]".
+// They aren't part of the spec text a focus matches against.
+var labelSuffix = regexp.MustCompile(` \[[^\[\]]*\]$`)
+
+// failure is a failed test case
+type failure struct {
+	name    string
+	message string
+}
+
+// ExecuteSuite runs a suite image, retrying it according to the suite's retry policy. Failures of
+// quarantined tests are reported as informing and never fail the suite or cause a retry.
+func (e *Executor) ExecuteSuite(ctx context.Context, suite Suite) SuiteResult {
+	logger := e.logger.WithValues("suite", suite.Image)
+	start := time.Now()
+
+	maxAttempts := suite.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	result := SuiteResult{Suite: suite}
+	informing := map[string]bool{}
+	var previous []failure
+	pod := e.cfg.Pod.merge(suite.Pod).withDefaults()
+	var focus []corev1.EnvVar
+	run := e.runSuite
+	if run == nil {
+		run = e.execute
+	}
+
+	var outputDir string
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			// The retry supersedes the reports of the previous attempt, the analysis would count both
+			if err := removeJUnitReports(outputDir); err != nil {
+				logger.Error(err, "failed to remove junit reports of the retried attempt", "dir", outputDir)
+			}
+		}
+		outputDir = suite.OutputDir
+		if attempt > 1 {
+			outputDir = filepath.Join(suite.OutputDir, fmt.Sprintf("attempt-%d", attempt))
+		}
+
+		logger.Info("running test suite", "attempt", attempt, "maxAttempts", maxAttempts, "timeout", e.cfg.Timeout)
+		attemptPod := pod
+		attemptPod.Env = append(append([]corev1.EnvVar{}, pod.Env...), focus...)
+		results, err := run(ctx, logger, suite.Image, outputDir, attemptPod)
+		if err != nil {
+			logger.Error(err, "execution failed", "attempt", attempt)
+		}
+
+		result.Attempts = attempt
+		result.Err = err
+		if attempt == 1 {
+			result.Results = results
+		}
+
+		failing, quarantined := splitFailures(failedTests(results), suite.Quarantine)
+		for _, f := range quarantined {
+			if !informing[f.name] {
+				informing[f.name] = true
+				result.Informing = append(result.Informing, formatFailure(f))
+			}
+		}
+		if err == nil {
+			passed := passedTests(results)
+			result.Flaky = append(result.Flaky, resolved(previous, passed)...)
+			// A test only recovers by passing, not by being left out of the retry, e.g. by a focus that matched nothing
+			failing = append(failing, unresolved(previous, passed, failing)...)
+			previous = failing
+		}
+
+		if err == nil && len(failing) == 0 {
+			result.Failures = nil
+			break
+		}
+		result.Failures = formatFailures(failing)
+
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		// Without results there is nothing to focus on, so the previous selection is run again.
+		if suite.RetryMode == config.RetryModeFailedSpecs && err == nil {
+			focus = []corev1.EnvVar{{Name: focusEnv, Value: focusRegex(failing)}}
+		}
+		logger.Info("retrying test suite", "failures", len(failing), "mode", suite.RetryMode)
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// failedTests returns the failed and errored test cases in the results
+func failedTests(results *testResults) []failure {
+	if results == nil {
+		return nil
+	}
+
+	var failures []failure
+	for _, suite := range results.Suites {
+		for _, test := range suite.Tests {
+			if test.Error != nil {
+				failures = append(failures, failure{name: test.Name, message: test.Error.Error()})
+			}
+		}
+	}
+	return failures
+}
+
+// passedTests returns the names of the passed test cases in the results
+func passedTests(results *testResults) map[string]bool {
+	passed := map[string]bool{}
+	if results == nil {
+		return passed
+	}
+	for _, suite := range results.Suites {
+		for _, test := range suite.Tests {
+			if test.Status == junit.StatusPassed {
+				passed[test.Name] = true
+			}
+		}
+	}
+	return passed
+}
+
+// splitFailures separates the failures of quarantined tests from the others. Quarantine entries match test names exactly.
+func splitFailures(failures []failure, quarantine []string) (failing, quarantined []failure) {
+	names := make(map[string]bool, len(quarantine))
+	for _, name := range quarantine {
+		names[name] = true
+	}
+
+	for _, f := range failures {
+		if names[f.name] || names[nodeTypePrefix.ReplaceAllString(f.name, "")] {
+			quarantined = append(quarantined, f)
+		} else {
+			failing = append(failing, f)
+		}
+	}
+	return failing, quarantined
+}
+
+// resolved returns the names of previously failing tests that passed
+func resolved(previous []failure, passed map[string]bool) []string {
+	var names []string
+	for _, f := range previous {
+		if passed[f.name] {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// unresolved returns the previously failing tests that neither passed nor failed again, because they didn't run
+func unresolved(previous []failure, passed map[string]bool, failing []failure) []failure {
+	still := make(map[string]bool, len(failing))
+	for _, f := range failing {
+		still[f.name] = true
+	}
+
+	var notRun []failure
+	for _, f := range previous {
+		if passed[f.name] || still[f.name] {
+			continue
+		}
+		if !strings.HasPrefix(f.message, notRunMessage) {
+			f.message = notRunMessage + f.message
+		}
+		notRun = append(notRun, f)
+	}
+	return notRun
+}
+
+// focusRegex builds a Ginkgo focus that matches exactly the failed specs
+func focusRegex(failures []failure) string {
+	patterns := make([]string, 0, len(failures))
+	for _, f := range failures {
+		spec := labelSuffix.ReplaceAllString(nodeTypePrefix.ReplaceAllString(f.name, ""), "")
+		patterns = append(patterns, regexp.QuoteMeta(spec))
+	}
+	return strings.Join(patterns, "|")
+}
+
+func formatFailure(f failure) string {
+	return fmt.Sprintf("test case failure: %q - %v", f.name, f.message)
+}
+
+func formatFailures(failures []failure) []string {
+	formatted := make([]string, 0, len(failures))
+	for _, f := range failures {
+		formatted = append(formatted, formatFailure(f))
+	}
+	return formatted
+}
@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/joshdk/go-junit"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+func TestFailedTests(t *testing.T) {
+	results := &testResults{
+		Suites: []junit.Suite{{
+			Tests: []junit.Test{
+				{Name: "[It] passes", Status: junit.StatusPassed},
+				{Name: "[It] fails", Status: junit.StatusFailed, Error: errors.New("boom")},
+				{Name: "[It] errors", Status: junit.StatusError, Error: errors.New("panic")},
+			},
+		}},
+	}
+
+	failures := failedTests(results)
+	if len(failures) != 2 || failures[0].name != "[It] fails" || failures[1].message != "panic" {
+		t.Errorf("unexpected failures: %+v", failures)
+	}
+	if failedTests(nil) != nil {
+		t.Errorf("expected no failures without results")
+	}
+}
+
+func TestSplitFailures(t *testing.T) {
+	failures := []failure{
+		{name: "[It] stable test"},
+		{name: "[It] flaky test"},
+		{name: "exact name"},
+	}
+
+	failing, quarantined := splitFailures(failures, []string{"flaky test", "exact name"})
+	if len(failing) != 1 || failing[0].name != "[It] stable test" {
+		t.Errorf("unexpected failing tests: %+v", failing)
+	}
+	if len(quarantined) != 2 {
+		t.Errorf("expected both quarantined tests to match, got %+v", quarantined)
+	}
+}
+
+func TestResolved(t *testing.T) {
+	previous := []failure{{name: "a"}, {name: "b"}, {name: "c"}}
+	passed := map[string]bool{"a": true, "d": true}
+	failing := []failure{{name: "b"}}
+
+	if names := resolved(previous, passed); strings.Join(names, ",") != "a" {
+		t.Errorf("expected only a to be resolved, got %v", names)
+	}
+	notRun := unresolved(previous, passed, failing)
+	if len(notRun) != 1 || notRun[0].name != "c" || !strings.HasPrefix(notRun[0].message, notRunMessage) {
+		t.Errorf("expected c to still fail as not run, got %+v", notRun)
+	}
+}
+
+func TestFocusRegex(t *testing.T) {
+	failures := []failure{{name: "[It] checks (things) [Label, Slow]"}, {name: "[It] other.test"}}
+	focus := regexp.MustCompile(focusRegex(failures))
+
+	if strings.Contains(focus.String(), "Label") {
+		t.Errorf("expected the labels to be left out of focus %q", focus)
+	}
+	// Ginkgo matches the focus against the spec text, which doesn't include the labels
+	for _, spec := range []string{"checks (things)", "other.test"} {
+		if !focus.MatchString(spec) {
+			t.Errorf("expected focus %q to match %q", focus, spec)
+		}
+	}
+	if focus.MatchString("otherXtest") {
+		t.Errorf("expected special characters in %q to be escaped", focus)
+	}
+}
+
+// fakeAttempts returns the results of one attempt per call and records the focus of each attempt
+type fakeAttempts struct {
+	results []*testResults
+	focus   []string
+}
+
+func (f *fakeAttempts) run(_ context.Context, _ logr.Logger, _, _ string, pod PodOverrides) (*testResults, error) {
+	focus := ""
+	for _, env := range pod.Env {
+		if env.Name == focusEnv {
+			focus = env.Value
+		}
+	}
+	f.focus = append(f.focus, focus)
+	results := f.results[len(f.focus)-1]
+	return results, nil
+}
+
+func suiteResults(tests ...junit.Test) *testResults {
+	return &testResults{Suites: []junit.Suite{{Tests: tests}}}
+}
+
+func TestExecuteSuiteRetryFailedSpecs(t *testing.T) {
+	failed := junit.Test{Name: "[It] flaky test [Label]", Status: junit.StatusFailed, Error: errors.New("boom")}
+	passedAgain := junit.Test{Name: "[It] flaky test [Label]", Status: junit.StatusPassed}
+
+	tests := []struct {
+		name          string
+		retry         *testResults
+		expectFlaky   bool
+		expectFailing bool
+	}{
+		{name: "passes when retried", retry: suiteResults(passedAgain), expectFlaky: true},
+		{name: "focus matches nothing", retry: suiteResults(), expectFailing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := &fakeAttempts{results: []*testResults{suiteResults(failed), tt.retry}}
+			e := &Executor{cfg: &Config{}, logger: logr.Discard(), runSuite: attempts.run}
+
+			result := e.ExecuteSuite(context.Background(), Suite{
+				Image:       "suite",
+				OutputDir:   t.TempDir(),
+				MaxAttempts: 2,
+				RetryMode:   config.RetryModeFailedSpecs,
+			})
+
+			if result.Attempts != 2 || len(attempts.focus) != 2 {
+				t.Fatalf("expected 2 attempts, got %d", result.Attempts)
+			}
+			if attempts.focus[1] != "flaky test" {
+				t.Errorf("expected the retry to focus on the spec text, got %q", attempts.focus[1])
+			}
+			if flaky := len(result.Flaky) == 1; flaky != tt.expectFlaky {
+				t.Errorf("expected flaky to be %v, got %v", tt.expectFlaky, result.Flaky)
+			}
+			if failing := len(result.Failures) > 0; failing != tt.expectFailing {
+				t.Errorf("expected failing to be %v, got %v", tt.expectFailing, result.Failures)
+			}
+		})
+	}
+}
+
+func TestExecuteSuiteRemovesRetriedReports(t *testing.T) {
+	failed := junit.Test{Name: "[It] flaky test", Status: junit.StatusFailed, Error: errors.New("boom")}
+	var dirs []string
+	run := func(_ context.Context, _ logr.Logger, _, outputDir string, _ PodOverrides) (*testResults, error) {
+		dirs = append(dirs, outputDir)
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return nil, err
+		}
+		for _, name := range []string{"junit_suite.xml", "test_output.log"} {
+			if err := os.WriteFile(filepath.Join(outputDir, name), nil, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		return suiteResults(failed), nil
+	}
+	e := &Executor{cfg: &Config{}, logger: logr.Discard(), runSuite: run}
+
+	result := e.ExecuteSuite(context.Background(), Suite{Image: "suite", OutputDir: t.TempDir(), MaxAttempts: 3})
+
+	if result.Attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", result.Attempts)
+	}
+	for i, dir := range dirs {
+		_, err := os.Stat(filepath.Join(dir, "junit_suite.xml"))
+		if last := i == len(dirs)-1; last != (err == nil) {
+			t.Errorf("attempt %d: expected the junit report to exist to be %v, got %v", i+1, last, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "test_output.log")); err != nil {
+			t.Errorf("attempt %d: expected other artifacts to be kept, got %v", i+1, err)
+		}
+	}
+}
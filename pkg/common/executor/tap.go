@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/joshdk/go-junit"
+)
+
+// tapTestLine matches a TAP test point, e.g. "not ok 2 - description # SKIP reason"
+var tapTestLine = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+)\b\s*(.*))?$`)
+
+// tapParser reads Test Anything Protocol output
+type tapParser struct{}
+
+func (tapParser) Name() string { return "tap" }
+
+func (tapParser) Match(fileName string) bool {
+	return strings.HasSuffix(strings.ToLower(fileName), ".tap")
+}
+
+// Recognize accepts any file, the extension is specific to TAP
+func (tapParser) Recognize([]byte) bool { return true }
+
+// Parse reads the top level test points of a TAP stream. YAML diagnostics following a failed test
+// become its error body, "# SKIP" and "# TODO" directives mark tests as skipped and "Bail out!"
+// is reported as an errored test.
+func (tapParser) Parse(fileName string, data []byte) ([]junit.Suite, error) {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	suite := junit.Suite{Name: name}
+
+	var diagnostics *strings.Builder
+	var found bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if diagnostics != nil {
+			if strings.TrimRight(line, " ") == "  ..." {
+				attachDiagnostics(&suite.Tests[len(suite.Tests)-1], diagnostics.String())
+				diagnostics = nil
+			} else {
+				diagnostics.WriteString(strings.TrimPrefix(line, "  ") + "\n")
+			}
+			continue
+		}
+
+		switch {
+		// Only diagnostics of top level test points are read, subtests are indented further
+		case strings.TrimRight(line, " ") == "  ---" && len(suite.Tests) > 0:
+			diagnostics = &strings.Builder{}
+		case strings.HasPrefix(line, "TAP version"), strings.HasPrefix(line, "1.."):
+			found = true
+		case strings.HasPrefix(line, "Bail out!"):
+			found = true
+			reason := strings.TrimSpace(strings.TrimPrefix(line, "Bail out!"))
+			suite.Tests = append(suite.Tests, junit.Test{
+				Name:      "bail out",
+				Classname: name,
+				Status:    junit.StatusError,
+				Message:   reason,
+				Error:     junit.Error{Message: reason, Type: "bail out"},
+			})
+		default:
+			match := tapTestLine.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			found = true
+			suite.Tests = append(suite.Tests, tapTest(name, len(suite.Tests)+1, match))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading tap stream: %w", err)
+	}
+	if diagnostics != nil {
+		attachDiagnostics(&suite.Tests[len(suite.Tests)-1], diagnostics.String())
+	}
+	if !found {
+		return nil, errors.New("no tap plan or test points found")
+	}
+
+	suite.Aggregate()
+	return []junit.Suite{suite}, nil
+}
+
+// tapTest converts a matched test point into a test
+func tapTest(classname string, number int, match []string) junit.Test {
+	description, directive, reason := match[3], strings.ToUpper(match[4]), match[5]
+	if description == "" {
+		if match[2] != "" {
+			description = "test " + match[2]
+		} else {
+			description = fmt.Sprintf("test %d", number)
+		}
+	}
+
+	test := junit.Test{Name: description, Classname: classname, Status: junit.StatusPassed}
+	switch {
+	case directive == "SKIP" || directive == "TODO":
+		test.Status = junit.StatusSkipped
+		test.Message = reason
+	case match[1] == "not ok":
+		test.Status = junit.StatusFailed
+		test.Error = junit.Error{Message: "not ok"}
+	}
+	return test
+}
+
+// attachDiagnostics sets the YAML diagnostics block as the error body of a failed test
+func attachDiagnostics(test *junit.Test, diagnostics string) {
+	if test.Status != junit.StatusFailed {
+		test.SystemOut = diagnostics
+		return
+	}
+	test.Error = junit.Error{Message: "not ok", Body: diagnostics}
+}
@@ -0,0 +1,87 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "osde2e"
+
+// WriteMetricsFile writes the step timings and the outcome of the result to path in the Prometheus
+// text format, e.g. for the node exporter textfile collector or a push to a Pushgateway. The labels
+// are added to every metric, so runs of different jobs can be told apart.
+func WriteMetricsFile(path string, result *Result, labels map[string]string) error {
+	registry := prometheus.NewRegistry()
+
+	duration := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Name:        "step_duration_seconds",
+		Help:        "How long a step of the run took.",
+		ConstLabels: labels,
+	}, []string{"step", "phase"})
+	started := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Name:        "step_start_timestamp_seconds",
+		Help:        "When a step of the run started, in seconds since the epoch.",
+		ConstLabels: labels,
+	}, []string{"step", "phase"})
+	finished := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Name:        "step_end_timestamp_seconds",
+		Help:        "When a step of the run finished, in seconds since the epoch.",
+		ConstLabels: labels,
+	}, []string{"step", "phase"})
+	succeeded := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Name:        "step_success",
+		Help:        "Whether a step of the run succeeded (1) or failed (0).",
+		ConstLabels: labels,
+	}, []string{"step", "phase"})
+	exitCode := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Name:        "run_exit_code",
+		Help:        "The exit code of the run.",
+		ConstLabels: labels,
+	})
+	registry.MustRegister(duration, started, finished, succeeded, exitCode)
+
+	for _, step := range result.Steps {
+		duration.WithLabelValues(step.Name, step.Phase).Set(step.Duration().Seconds())
+		started.WithLabelValues(step.Name, step.Phase).Set(float64(step.StartedAt.UnixMilli()) / 1000)
+		finished.WithLabelValues(step.Name, step.Phase).Set(float64(step.FinishedAt.UnixMilli()) / 1000)
+		success := 1.0
+		if step.Err != nil {
+			success = 0
+		}
+		succeeded.WithLabelValues(step.Name, step.Phase).Set(success)
+	}
+	exitCode.Set(float64(result.ExitCode))
+
+	if err := prometheus.WriteToTextfile(path, registry); err != nil {
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	return nil
+}
+
+// WriteConfiguredMetricsFile writes the metrics of the result to the configured metrics file, if any.
+// Failures are logged as they shouldn't change the outcome of the run.
+func WriteConfiguredMetricsFile(result *Result) {
+	path := viper.GetString(config.MetricsFile)
+	if path == "" {
+		return
+	}
+
+	labels := map[string]string{
+		"job":      viper.GetString(config.JobName),
+		"provider": viper.GetString(config.Provider),
+	}
+	if err := WriteMetricsFile(path, result, labels); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+		return
+	}
+	log.Printf("Step metrics written to %s", path)
+}
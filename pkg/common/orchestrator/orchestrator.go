@@ -1,7 +1,13 @@
 // Package orchestrator defines the interface for managing end-to-end test lifecycles.
 package orchestrator
 
-import "context"
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/config"
+)
 
 // Orchestrator manages the complete lifecycle of e2e test execution including
 // cluster provisioning, test execution, failure analysis, and reporting.
@@ -47,4 +53,66 @@ type Result struct {
 	UpgradePassed bool    // Whether upgrade phase tests passed (if run)
 	ClusterID     string  // ID of the cluster used for testing
 	Errors        []error // Collection of errors encountered during execution
+	Steps         []Step  // Timings of the steps of the run in the order they finished
+}
+
+// Names of the steps recorded in Result.Steps. Implementations may record additional steps.
+const (
+	StepPreProcess         = "pre-process"
+	StepProvision          = "provision"
+	StepHealthCheck        = "health-check"
+	StepTests              = "tests"
+	StepExecute            = "execute"
+	StepUpgrade            = "upgrade"
+	StepSLOGate            = "slo-gate"
+	StepAnalyzeLogs        = "analyze-logs"
+	StepPostProcessCluster = "post-process-cluster"
+	StepReport             = "report"
+	StepCleanup            = "cleanup"
+)
+
+// Step is the timing of a single step of a run.
+type Step struct {
+	Name string
+	// Phase is the test phase of steps that run once per phase, e.g. the tests or health checks
+	Phase      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Err        error
+}
+
+// Duration is how long the step took.
+func (s Step) Duration() time.Duration {
+	return s.FinishedAt.Sub(s.StartedAt)
+}
+
+// RecordStep records a step that started at startedAt and finishes now.
+func (r *Result) RecordStep(name, phase string, startedAt time.Time, err error) {
+	r.RecordStepAt(Step{Name: name, Phase: phase, StartedAt: startedAt, FinishedAt: time.Now(), Err: err})
+}
+
+// RecordStepAt records a step whose end is already known.
+func (r *Result) RecordStepAt(step Step) {
+	r.Steps = append(r.Steps, step)
+}
+
+// Step returns the first recorded step with the given name and phase.
+func (r *Result) Step(name, phase string) (Step, bool) {
+	for _, step := range r.Steps {
+		if step.Name == name && step.Phase == phase {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+// MarkAborted sets the exit code of the result to config.Aborted if ctx was canceled or ran past its
+// deadline, e.g. on Ctrl-C or a CI timeout. It reports whether the run was aborted.
+func (r *Result) MarkAborted(ctx context.Context) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	log.Printf("Run aborted: %v", context.Cause(ctx))
+	r.ExitCode = config.Aborted
+	return true
 }
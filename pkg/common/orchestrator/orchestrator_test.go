@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+// fakeOrchestrator records a step of its own from Execute, like implementations that time their test phases
+type fakeOrchestrator struct {
+	result     Result
+	executeErr error
+}
+
+func (f *fakeOrchestrator) PreProcess(context.Context) error { return nil }
+func (f *fakeOrchestrator) Provision(context.Context) error  { return nil }
+func (f *fakeOrchestrator) Execute(context.Context) error {
+	f.result.RecordStep(StepTests, "install", time.Now(), f.executeErr)
+	return f.executeErr
+}
+func (f *fakeOrchestrator) AnalyzeLogs(context.Context, error) error     { return nil }
+func (f *fakeOrchestrator) Report(context.Context) error                 { return nil }
+func (f *fakeOrchestrator) Cleanup(context.Context) error                { return nil }
+func (f *fakeOrchestrator) PostProcessCluster(ctx context.Context) error { return nil }
+func (f *fakeOrchestrator) Result() *Result                              { return &f.result }
+
+func TestTimed(t *testing.T) {
+	ctx := context.Background()
+	testErr := errors.New("tests failed")
+	orch := Timed(&fakeOrchestrator{executeErr: testErr})
+
+	_ = orch.PreProcess(ctx)
+	_ = orch.Provision(ctx)
+	if err := orch.Execute(ctx); !errors.Is(err, testErr) {
+		t.Errorf("expected the error of the step to be returned, got %v", err)
+	}
+	_ = orch.Cleanup(ctx)
+
+	var names []string
+	for _, step := range orch.Result().Steps {
+		names = append(names, step.Name)
+		if step.FinishedAt.Before(step.StartedAt) {
+			t.Errorf("step %s finished before it started", step.Name)
+		}
+	}
+	if got, want := strings.Join(names, ","), "pre-process,provision,tests,execute,cleanup"; got != want {
+		t.Errorf("expected steps %s, got %s", want, got)
+	}
+
+	if step, ok := orch.Result().Step(StepTests, "install"); !ok || !errors.Is(step.Err, testErr) {
+		t.Errorf("expected the failed install tests step, got %+v", step)
+	}
+	if _, ok := orch.Result().Step(StepReport, ""); ok {
+		t.Errorf("expected no report step")
+	}
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	startedAt := time.Unix(1700000000, 0)
+	result := &Result{
+		ExitCode: 1,
+		Steps: []Step{
+			{Name: StepProvision, StartedAt: startedAt, FinishedAt: startedAt.Add(90 * time.Second)},
+			{Name: StepTests, Phase: "upgrade", StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second), Err: errors.New("failed")},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "osde2e.prom")
+	if err := WriteMetricsFile(path, result, map[string]string{"job": "nightly"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`osde2e_step_duration_seconds{job="nightly",phase="",step="provision"} 90`,
+		`osde2e_step_start_timestamp_seconds{job="nightly",phase="",step="provision"} 1.7e+09`,
+		`osde2e_step_success{job="nightly",phase="upgrade",step="tests"} 0`,
+		`osde2e_run_exit_code{job="nightly"} 1`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in:\n%s", want, data)
+		}
+	}
+}
+
+func TestMarkAborted(t *testing.T) {
+	result := &Result{ExitCode: 1}
+	if result.MarkAborted(context.Background()) || result.ExitCode != 1 {
+		t.Errorf("expected a live context to leave the result alone, got exit code %d", result.ExitCode)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if !result.MarkAborted(ctx) || result.ExitCode != config.Aborted {
+		t.Errorf("expected a canceled context to abort the run, got exit code %d", result.ExitCode)
+	}
+}
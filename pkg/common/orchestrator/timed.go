@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+)
+
+// timed records the timing of every step of the orchestrator it wraps in its result.
+type timed struct {
+	Orchestrator
+}
+
+// Timed wraps an orchestrator so the start, end and duration of each of its steps is
+// recorded in Result().Steps. Steps an implementation runs within a step, e.g. the
+// test phases within Execute, are recorded by the implementation itself.
+func Timed(o Orchestrator) Orchestrator {
+	return &timed{Orchestrator: o}
+}
+
+func (t *timed) record(name string, step func() error) error {
+	startedAt := time.Now()
+	err := step()
+	t.Result().RecordStep(name, "", startedAt, err)
+	return err
+}
+
+func (t *timed) PreProcess(ctx context.Context) error {
+	return t.record(StepPreProcess, func() error { return t.Orchestrator.PreProcess(ctx) })
+}
+
+func (t *timed) Provision(ctx context.Context) error {
+	return t.record(StepProvision, func() error { return t.Orchestrator.Provision(ctx) })
+}
+
+func (t *timed) Execute(ctx context.Context) error {
+	return t.record(StepExecute, func() error { return t.Orchestrator.Execute(ctx) })
+}
+
+func (t *timed) AnalyzeLogs(ctx context.Context, testErr error) error {
+	return t.record(StepAnalyzeLogs, func() error { return t.Orchestrator.AnalyzeLogs(ctx, testErr) })
+}
+
+func (t *timed) Report(ctx context.Context) error {
+	return t.record(StepReport, func() error { return t.Orchestrator.Report(ctx) })
+}
+
+func (t *timed) Cleanup(ctx context.Context) error {
+	return t.record(StepCleanup, func() error { return t.Orchestrator.Cleanup(ctx) })
+}
+
+func (t *timed) PostProcessCluster(ctx context.Context) error {
+	return t.record(StepPostProcessCluster, func() error { return t.Orchestrator.PostProcessCluster(ctx) })
+}
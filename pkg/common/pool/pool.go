@@ -0,0 +1,410 @@
+// Package pool keeps a warm pool of reserved clusters that test jobs claim instead of provisioning
+// their own, on top of the Availability cluster property.
+package pool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+// ownedQuery narrows ListClusters down to the clusters osde2e created, for providers that support queries.
+const ownedQuery = "properties.MadeByOSDe2e='true'"
+
+// Key identifies a pool of interchangeable clusters.
+type Key struct {
+	// Version is an OpenShift version or version prefix, e.g. 4.16 or 4.16.3.
+	Version string
+
+	// CloudProvider is the cloud provider ID, e.g. aws or gcp.
+	CloudProvider string
+
+	// Product is the product ID, e.g. rosa or osd.
+	Product string
+
+	// Region is the cloud region. An empty region matches clusters in any region.
+	Region string
+}
+
+// String returns the key in the format ParseSpec accepts.
+func (k Key) String() string {
+	return strings.Join([]string{k.Version, k.CloudProvider, k.Product, k.Region}, "/")
+}
+
+// Matches reports whether the cluster belongs to the pool of the key.
+func (k Key) Matches(cluster *spi.Cluster) bool {
+	return versionMatches(cluster.Version(), k.Version) &&
+		cluster.CloudProvider() == k.CloudProvider &&
+		cluster.Product() == k.Product &&
+		(k.Region == "" || cluster.Region() == k.Region)
+}
+
+// versionMatches reports whether the version equals the prefix or is a patch or pre-release of it.
+func versionMatches(version, prefix string) bool {
+	version = strings.TrimPrefix(version, util.VersionPrefix)
+	prefix = strings.TrimPrefix(prefix, util.VersionPrefix)
+	if !strings.HasPrefix(version, prefix) {
+		return false
+	}
+	rest := version[len(prefix):]
+	return rest == "" || rest[0] == '.' || rest[0] == '-'
+}
+
+// Spec is the number of ready clusters to keep in a pool.
+type Spec struct {
+	Key
+	Size int
+}
+
+// ParseSpec parses a spec in the format version/cloud/product[/region]=size, e.g. 4.16/aws/rosa/us-east-1=3.
+func ParseSpec(s string) (Spec, error) {
+	key, size, ok := strings.Cut(s, "=")
+	if !ok {
+		return Spec{}, fmt.Errorf("pool %q has no size, expected version/cloud/product[/region]=size", s)
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n < 0 {
+		return Spec{}, fmt.Errorf("pool %q has an invalid size %q", s, size)
+	}
+
+	parts := strings.Split(key, "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return Spec{}, fmt.Errorf("pool %q must be version/cloud/product[/region]=size", s)
+	}
+	for _, part := range parts[:3] {
+		if part == "" {
+			return Spec{}, fmt.Errorf("pool %q must set a version, cloud provider and product", s)
+		}
+	}
+	spec := Spec{Key: Key{Version: parts[0], CloudProvider: parts[1], Product: parts[2]}, Size: n}
+	if len(parts) == 4 {
+		spec.Region = parts[3]
+	}
+	return spec, nil
+}
+
+// Launcher launches a new reserved cluster for the pool of the key and returns its ID.
+type Launcher func(ctx context.Context, key Key) (string, error)
+
+// Options configures how a Manager maintains its pools.
+type Options struct {
+	// MaxAge is the age after which pool clusters are deleted, whatever their availability.
+	// Zero disables the limit.
+	MaxAge time.Duration
+
+	// RecycleUsed returns ready used clusters to the pool instead of deleting them.
+	RecycleUsed bool
+
+	// DryRun only logs the actions that would be taken.
+	DryRun bool
+}
+
+// ActionType is what Maintain did with a cluster.
+type ActionType string
+
+const (
+	// ActionLaunch launched a new cluster.
+	ActionLaunch ActionType = "launch"
+
+	// ActionDelete deleted a cluster.
+	ActionDelete ActionType = "delete"
+
+	// ActionRecycle returned a used cluster to the pool.
+	ActionRecycle ActionType = "recycle"
+)
+
+// Action is a change Maintain made, or would have made in a dry run.
+type Action struct {
+	Type      ActionType
+	ClusterID string
+	Pool      Key
+	Reason    string
+	Err       error
+}
+
+// Status is the number of pool clusters by availability.
+type Status struct {
+	Spec
+
+	// Ready are reserved clusters that can be claimed right away.
+	Ready int
+
+	// Installing are reserved clusters that are still provisioning.
+	Installing int
+
+	// Claimed are clusters a test job is using.
+	Claimed int
+
+	// Used are clusters a test job finished with.
+	Used int
+
+	// Unhealthy are pool clusters in the error state.
+	Unhealthy int
+}
+
+// Manager maintains pools of reserved clusters of a provider.
+type Manager struct {
+	provider spi.Provider
+	launch   Launcher
+	specs    []Spec
+	options  Options
+	now      func() time.Time
+}
+
+// NewManager creates a Manager that keeps the pools of the specs filled with clusters of the provider.
+func NewManager(provider spi.Provider, launch Launcher, specs []Spec, options Options) *Manager {
+	return &Manager{
+		provider: provider,
+		launch:   launch,
+		specs:    specs,
+		options:  options,
+		now:      time.Now,
+	}
+}
+
+// poolClusters lists the clusters that are part of any pool, i.e. that have an availability.
+func (m *Manager) poolClusters() ([]*spi.Cluster, error) {
+	clusters, err := m.provider.ListClusters(ownedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error listing pool clusters: %w", err)
+	}
+
+	var pooled []*spi.Cluster
+	for _, cluster := range clusters {
+		switch cluster.Properties()[clusterproperties.Availability] {
+		case clusterproperties.Reserved, clusterproperties.Claimed, clusterproperties.Used:
+			pooled = append(pooled, cluster)
+		}
+	}
+	return pooled, nil
+}
+
+// specFor returns the index of the first spec whose pool the cluster belongs to, or -1.
+func (m *Manager) specFor(cluster *spi.Cluster) int {
+	for i, spec := range m.specs {
+		if spec.Matches(cluster) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Status counts the clusters of every pool.
+func (m *Manager) Status() ([]Status, error) {
+	clusters, err := m.poolClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.specs))
+	for i, spec := range m.specs {
+		statuses[i].Spec = spec
+	}
+	for _, cluster := range clusters {
+		i := m.specFor(cluster)
+		if i < 0 {
+			continue
+		}
+		status := &statuses[i]
+
+		switch availability := cluster.Properties()[clusterproperties.Availability]; {
+		case cluster.State() == spi.ClusterStateError:
+			status.Unhealthy++
+		case availability == clusterproperties.Claimed:
+			status.Claimed++
+		case availability == clusterproperties.Used:
+			status.Used++
+		case cluster.State() == spi.ClusterStateReady:
+			status.Ready++
+		case isProvisioning(cluster):
+			status.Installing++
+		}
+	}
+	return statuses, nil
+}
+
+// Maintain deletes expired, failed and used pool clusters, or recycles used ones, and then launches
+// clusters until every pool has its size in ready or installing reserved clusters.
+//
+// Failures to delete or launch a cluster are recorded in its action so that one bad cluster doesn't
+// stop the rest of the pools from being filled.
+func (m *Manager) Maintain(ctx context.Context) ([]Action, error) {
+	clusters, err := m.poolClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	available := make([]int, len(m.specs))
+	for _, cluster := range clusters {
+		if err := ctx.Err(); err != nil {
+			return actions, err
+		}
+
+		// Clusters of other versions and products are maintained by whoever pools them
+		i := m.specFor(cluster)
+		if i < 0 {
+			continue
+		}
+
+		action, keep := m.review(cluster)
+		if action != nil {
+			action.Pool = m.specs[i].Key
+			actions = append(actions, *m.apply(action))
+			keep = keep && action.Err == nil
+		}
+		if keep {
+			available[i]++
+		}
+	}
+
+	for i, spec := range m.specs {
+		for n := available[i]; n < spec.Size; n++ {
+			if err := ctx.Err(); err != nil {
+				return actions, err
+			}
+			action := Action{Type: ActionLaunch, Pool: spec.Key, Reason: fmt.Sprintf("%d of %d clusters available", n, spec.Size)}
+			if !m.options.DryRun {
+				action.ClusterID, action.Err = m.launch(ctx, spec.Key)
+			}
+			log.Printf("Pool %s: %s", spec.Key, describe(action))
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// review decides what to do with a pool cluster, and whether it counts towards the available
+// clusters of its pool afterwards. Claimed clusters are left alone until their lease expires, however
+// old they are, as a test job is using them.
+func (m *Manager) review(cluster *spi.Cluster) (*Action, bool) {
+	availability := cluster.Properties()[clusterproperties.Availability]
+	age := m.now().Sub(cluster.CreationTimestamp())
+
+	switch {
+	case cluster.State() == spi.ClusterStateUninstalling:
+		return nil, false
+	case availability == clusterproperties.Claimed && !clusterproperties.LeaseExpired(cluster.Properties(), m.now()):
+		return nil, false
+	case m.options.MaxAge > 0 && age > m.options.MaxAge:
+		return &Action{Type: ActionDelete, ClusterID: cluster.ID(), Reason: fmt.Sprintf("older than %s", m.options.MaxAge)}, false
+	case cluster.State() == spi.ClusterStateError:
+		return &Action{Type: ActionDelete, ClusterID: cluster.ID(), Reason: "cluster failed"}, false
+	case availability == clusterproperties.Claimed:
+		return &Action{Type: ActionDelete, ClusterID: cluster.ID(), Reason: "claim lease expired"}, false
+	case availability == clusterproperties.Used:
+		if _, ok := spi.As[spi.PropertyStore](m.provider); ok && m.options.RecycleUsed && cluster.State() == spi.ClusterStateReady {
+			return &Action{Type: ActionRecycle, ClusterID: cluster.ID(), Reason: "test job finished"}, true
+		}
+		return &Action{Type: ActionDelete, ClusterID: cluster.ID(), Reason: "test job finished"}, false
+	case availability == clusterproperties.Reserved:
+		return nil, cluster.State() == spi.ClusterStateReady || isProvisioning(cluster)
+	}
+	return nil, false
+}
+
+// apply performs the action, unless this is a dry run, and logs it.
+func (m *Manager) apply(action *Action) *Action {
+	if !m.options.DryRun {
+		switch action.Type {
+		case ActionDelete:
+			action.Err = m.provider.DeleteCluster(action.ClusterID)
+		case ActionRecycle:
+			action.Err = SetProperties(m.provider, action.ClusterID, map[string]string{
+				clusterproperties.Availability: clusterproperties.Reserved,
+			})
+		}
+	}
+	log.Printf("Pool %s: %s", action.Pool, describe(*action))
+	return action
+}
+
+// SetProperties sets the properties of a cluster that don't have the value yet. Providers like OCM
+// replace all properties of a cluster with those of the cluster given to AddProperty, so the cluster
+// is read again before every property is set.
+func SetProperties(provider spi.Provider, clusterID string, properties map[string]string) error {
+	store, ok := spi.As[spi.PropertyStore](provider)
+	if !ok {
+		return spi.NewNotSupportedError(provider.Type(), "AddProperty")
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cluster, err := provider.GetCluster(clusterID)
+		if err != nil {
+			return fmt.Errorf("error getting cluster %s: %w", clusterID, err)
+		}
+		if cluster.Properties()[name] == properties[name] {
+			continue
+		}
+		if err := store.AddProperty(cluster, name, properties[name]); err != nil {
+			return fmt.Errorf("error setting property %s of cluster %s: %w", name, clusterID, err)
+		}
+	}
+	return nil
+}
+
+// describe formats the action for logs.
+func describe(action Action) string {
+	s := string(action.Type)
+	if action.ClusterID != "" {
+		s += " " + action.ClusterID
+	}
+	s += " (" + action.Reason + ")"
+	if action.Err != nil {
+		s += ": " + action.Err.Error()
+	}
+	return s
+}
+
+// isProvisioning reports whether the cluster is on its way to being ready.
+func isProvisioning(cluster *spi.Cluster) bool {
+	switch cluster.State() {
+	case spi.ClusterStatePending, spi.ClusterStatePendingAccount, spi.ClusterStateInstalling:
+		return true
+	}
+	return false
+}
+
+// WriteStatus writes the statuses as a table.
+func WriteStatus(w io.Writer, statuses []Status) error {
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Key.String() < statuses[j].Key.String() })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POOL\tSIZE\tREADY\tINSTALLING\tCLAIMED\tUSED\tUNHEALTHY")
+	for _, s := range statuses {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n", s.Key, s.Size, s.Ready, s.Installing, s.Claimed, s.Used, s.Unhealthy)
+	}
+	return tw.Flush()
+}
+
+// ResolveVersion returns the newest available version of the key's version prefix in the
+// openshift-v format providers launch clusters with.
+func ResolveVersion(versions *spi.VersionList, prefix string) (string, error) {
+	var newest *semver.Version
+	for _, v := range versions.AvailableVersions() {
+		if versionMatches(v.Version().String(), prefix) && (newest == nil || v.Version().GreaterThan(newest)) {
+			newest = v.Version()
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("no available version matches %s", prefix)
+	}
+	return util.SemverToOpenshiftVersion(newest), nil
+}
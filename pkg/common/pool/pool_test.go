@@ -0,0 +1,281 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeProvider lists a fixed set of clusters and records deletions. Like OCM, AddProperty replaces
+// all properties of a cluster with those of the given cluster and the new property.
+type fakeProvider struct {
+	spi.Lifecycle
+	clusters []*spi.Cluster
+	deleted  []string
+}
+
+func (f *fakeProvider) Type() string { return "fake" }
+
+func (f *fakeProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+	return f.clusters, nil
+}
+
+func (f *fakeProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+	for _, cluster := range f.clusters {
+		if cluster.ID() == clusterID {
+			return withProperties(cluster, cluster.Properties()), nil
+		}
+	}
+	return nil, fmt.Errorf("cluster %s not found", clusterID)
+}
+
+func (f *fakeProvider) DeleteCluster(clusterID string) error {
+	f.deleted = append(f.deleted, clusterID)
+	return nil
+}
+
+func (f *fakeProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+	properties := maps.Clone(cluster.Properties())
+	if properties == nil {
+		properties = map[string]string{}
+	}
+	properties[tag] = value
+	for i, stored := range f.clusters {
+		if stored.ID() == cluster.ID() {
+			f.clusters[i] = withProperties(stored, properties)
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %s not found", cluster.ID())
+}
+
+func (f *fakeProvider) GetProperty(clusterID string, property string) (string, error) {
+	cluster, err := f.GetCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.Properties()[property], nil
+}
+
+// withProperties copies the cluster with a copy of the properties.
+func withProperties(cluster *spi.Cluster, properties map[string]string) *spi.Cluster {
+	return spi.NewClusterBuilder().
+		ID(cluster.ID()).
+		Version(cluster.Version()).
+		CloudProvider(cluster.CloudProvider()).
+		Product(cluster.Product()).
+		Region(cluster.Region()).
+		State(cluster.State()).
+		CreationTimestamp(cluster.CreationTimestamp()).
+		Properties(maps.Clone(properties)).
+		Build()
+}
+
+func cluster(id, version, availability string, state spi.ClusterState, age time.Duration) *spi.Cluster {
+	return spi.NewClusterBuilder().
+		ID(id).
+		Version(version).
+		CloudProvider("aws").
+		Product("rosa").
+		Region("us-east-1").
+		State(state).
+		CreationTimestamp(now.Add(-age)).
+		Properties(map[string]string{clusterproperties.Availability: availability}).
+		Build()
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec("4.16/aws/rosa/us-east-1=3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Version != "4.16" || spec.CloudProvider != "aws" || spec.Product != "rosa" || spec.Region != "us-east-1" || spec.Size != 3 {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+
+	for _, invalid := range []string{"4.16/aws/rosa", "4.16/aws=2", "4.16/aws/rosa=-1", "/aws/rosa=1"} {
+		if _, err := ParseSpec(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		version, prefix string
+		want            bool
+	}{
+		{"openshift-v4.16.3", "4.16", true},
+		{"4.16.3", "openshift-v4.16.3", true},
+		{"openshift-v4.16.0-rc.1", "4.16.0", true},
+		{"openshift-v4.1.3", "4.16", false},
+		{"openshift-v4.16.3", "4.1", false},
+	}
+	for _, tt := range tests {
+		if got := versionMatches(tt.version, tt.prefix); got != tt.want {
+			t.Errorf("versionMatches(%q, %q) = %v, expected %v", tt.version, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestMaintain(t *testing.T) {
+	abandoned := cluster("abandoned", "openshift-v4.16.3", clusterproperties.Claimed, spi.ClusterStateReady, 8*time.Hour)
+	abandoned.Properties()[clusterproperties.ClaimLeaseExpiry] = now.Add(-time.Hour).Format(time.RFC3339)
+	inUse := cluster("in-use", "openshift-v4.16.3", clusterproperties.Claimed, spi.ClusterStateReady, 30*time.Hour)
+	inUse.Properties()[clusterproperties.ClaimLeaseExpiry] = now.Add(time.Hour).Format(time.RFC3339)
+
+	provider := &fakeProvider{
+		clusters: []*spi.Cluster{
+			cluster("ready", "openshift-v4.16.3", clusterproperties.Reserved, spi.ClusterStateReady, time.Hour),
+			cluster("installing", "openshift-v4.16.3", clusterproperties.Reserved, spi.ClusterStateInstalling, time.Minute),
+			cluster("expired", "openshift-v4.16.3", clusterproperties.Reserved, spi.ClusterStateReady, 30*time.Hour),
+			cluster("failed", "openshift-v4.16.3", clusterproperties.Reserved, spi.ClusterStateError, time.Hour),
+			cluster("used", "openshift-v4.16.3", clusterproperties.Used, spi.ClusterStateReady, time.Hour),
+			// Claimed without a lease, e.g. by a job that died before writing it
+			cluster("claimed", "openshift-v4.16.3", clusterproperties.Claimed, spi.ClusterStateReady, time.Hour),
+			abandoned,
+			inUse,
+			cluster("other-pool", "openshift-v4.15.9", clusterproperties.Reserved, spi.ClusterStateReady, 30*time.Hour),
+			cluster("other-pool-used", "openshift-v4.15.9", clusterproperties.Used, spi.ClusterStateReady, time.Hour),
+			cluster("not-pooled", "openshift-v4.16.3", "", spi.ClusterStateReady, 30*time.Hour),
+		},
+	}
+
+	var launched []Key
+	launch := func(ctx context.Context, key Key) (string, error) {
+		launched = append(launched, key)
+		return "new", nil
+	}
+
+	spec, _ := ParseSpec("4.16/aws/rosa=4")
+	manager := NewManager(spi.Complete(provider), launch, []Spec{spec}, Options{MaxAge: 24 * time.Hour})
+	manager.now = func() time.Time { return now }
+
+	status, err := manager.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Status{Spec: spec, Ready: 2, Installing: 1, Claimed: 3, Used: 1, Unhealthy: 1}
+	if status[0] != want {
+		t.Errorf("expected status %+v, got %+v", want, status[0])
+	}
+
+	actions, err := manager.Maintain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(provider.deleted, ",") != "expired,failed,used,claimed,abandoned" {
+		t.Errorf("expected the expired, failed, used, claimed and abandoned clusters to be deleted, got %v", provider.deleted)
+	}
+	if len(launched) != 2 || launched[0] != spec.Key {
+		t.Errorf("expected 2 clusters to be launched to fill the pool, got %v", launched)
+	}
+	if len(actions) != 7 {
+		t.Errorf("expected 7 actions, got %+v", actions)
+	}
+
+	var out bytes.Buffer
+	if err := WriteStatus(&out, status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "4.16/aws/rosa/") {
+		t.Errorf("expected the status table to list the pool, got:\n%s", out.String())
+	}
+}
+
+func TestMaintainRecycle(t *testing.T) {
+	provider := &fakeProvider{
+		clusters: []*spi.Cluster{
+			cluster("used", "openshift-v4.16.3", clusterproperties.Used, spi.ClusterStateReady, time.Hour),
+		},
+	}
+	launch := func(ctx context.Context, key Key) (string, error) {
+		t.Errorf("expected the recycled cluster to fill the pool")
+		return "", nil
+	}
+
+	spec, _ := ParseSpec("4.16/aws/rosa=1")
+	manager := NewManager(spi.Complete(provider), launch, []Spec{spec}, Options{RecycleUsed: true})
+	manager.now = func() time.Time { return now }
+
+	if _, err := manager.Maintain(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if availability, _ := provider.GetProperty("used", clusterproperties.Availability); availability != clusterproperties.Reserved || len(provider.deleted) != 0 {
+		t.Errorf("expected the used cluster to be reserved again, got %q (deleted %v)", availability, provider.deleted)
+	}
+}
+
+func TestMaintainDryRun(t *testing.T) {
+	provider := &fakeProvider{
+		clusters: []*spi.Cluster{
+			cluster("used", "openshift-v4.16.3", clusterproperties.Used, spi.ClusterStateReady, time.Hour),
+		},
+	}
+	launch := func(ctx context.Context, key Key) (string, error) {
+		t.Errorf("expected no cluster to be launched in a dry run")
+		return "", nil
+	}
+
+	spec, _ := ParseSpec("4.16/aws/rosa=1")
+	manager := NewManager(spi.Complete(provider), launch, []Spec{spec}, Options{DryRun: true})
+	actions, err := manager.Maintain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actions) != 2 || len(provider.deleted) != 0 {
+		t.Errorf("expected a delete and a launch to be planned but not made, got %+v (deleted %v)", actions, provider.deleted)
+	}
+}
+
+func TestSetProperties(t *testing.T) {
+	launched := cluster("launched", "openshift-v4.16.3", "", spi.ClusterStateInstalling, time.Minute)
+	launched.Properties()[clusterproperties.Status] = clusterproperties.StatusProvisioning
+	launched.Properties()[clusterproperties.MadeByOSDe2e] = "true"
+	provider := &fakeProvider{clusters: []*spi.Cluster{launched}}
+
+	if err := SetProperties(spi.Complete(provider), "launched", map[string]string{
+		clusterproperties.MadeByOSDe2e: "true",
+		clusterproperties.Availability: clusterproperties.Reserved,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cluster, _ := provider.GetCluster("launched")
+	want := map[string]string{
+		clusterproperties.MadeByOSDe2e: "true",
+		clusterproperties.Availability: clusterproperties.Reserved,
+		clusterproperties.Status:       clusterproperties.StatusProvisioning,
+	}
+	if !maps.Equal(cluster.Properties(), want) {
+		t.Errorf("expected the existing properties to be kept, got %v", cluster.Properties())
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	var available []*spi.Version
+	for _, v := range []string{"4.15.9", "4.16.1", "4.16.3", "4.16.10-rc.1"} {
+		available = append(available, spi.NewVersionBuilder().Version(semver.MustParse(v)).Build())
+	}
+	versions := spi.NewVersionListBuilder().AvailableVersions(available).Build()
+
+	if version, err := ResolveVersion(versions, "4.16"); err != nil || version != "openshift-v4.16.10-rc.1" {
+		t.Errorf("expected the newest 4.16 version, got %q (%v)", version, err)
+	}
+	if version, err := ResolveVersion(versions, "openshift-v4.15.9"); err != nil || version != "openshift-v4.15.9" {
+		t.Errorf("expected the exact version, got %q (%v)", version, err)
+	}
+	if _, err := ResolveVersion(versions, "4.17"); err == nil {
+		t.Errorf("expected an error for an unavailable version")
+	}
+}
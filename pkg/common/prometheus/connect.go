@@ -3,6 +3,7 @@ package prometheus
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -10,12 +11,23 @@ import (
 	"strings"
 	"time"
 
-	"github.com/openshift/osde2e/pkg/common/helper"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/prometheus/client_golang/api"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
+// monitoringNamespace is the namespace of the cluster monitoring stack.
+const monitoringNamespace = "openshift-monitoring"
+
+// ErrUnavailable is returned when the cluster has no Prometheus to query, e.g. a local cluster
+// without cluster monitoring.
+var ErrUnavailable = errors.New("cluster Prometheus unavailable")
+
 // createRoundTripper will create a RoundTripper like api.DefaultRoundTripper with an added stripping
 // of cert verification and adding the bearer token to the HTTP request
 func createRoundTripper(bearerToken string) http.RoundTripper {
@@ -35,12 +47,23 @@ func createRoundTripper(bearerToken string) http.RoundTripper {
 	}
 }
 
-func CreateClusterClient(h *helper.H) (api.Client, error) {
-	promHost, err := getClusterPrometheusHost(h)
+// CreateClusterClient creates a client for the Prometheus of the cluster behind the rest config,
+// authenticated as the prometheus-k8s service account.
+func CreateClusterClient(ctx context.Context, restConfig *rest.Config) (api.Client, error) {
+	routeClient, err := routeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Route Clientset: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Kube Clientset: %w", err)
+	}
+
+	promHost, err := getClusterPrometheusHost(ctx, routeClient)
 	if err != nil {
 		return nil, err
 	}
-	clusterBearerToken, err := getClusterPrometheusToken(h)
+	clusterBearerToken, err := getClusterPrometheusToken(ctx, kubeClient)
 	if err != nil {
 		return nil, err
 	}
@@ -51,17 +74,21 @@ func CreateClusterClient(h *helper.H) (api.Client, error) {
 	})
 }
 
-func getClusterPrometheusHost(h *helper.H) (*string, error) {
-	route, err := h.Route().RouteV1().Routes("openshift-monitoring").Get(context.TODO(), "prometheus-k8s", metav1.GetOptions{})
+func getClusterPrometheusHost(ctx context.Context, routeClient routeclient.Interface) (*string, error) {
+	route, err := routeClient.RouteV1().Routes(monitoringNamespace).Get(ctx, "prometheus-k8s", metav1.GetOptions{})
 	if err != nil {
+		// Clusters without routes or cluster monitoring don't have the route
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
 		return nil, err
 	}
 	hostUrl := "https://" + route.Spec.Host
 	return &hostUrl, nil
 }
 
-func getClusterPrometheusToken(h *helper.H) (*string, error) {
-	secrets, err := h.Kube().CoreV1().Secrets("openshift-monitoring").List(context.TODO(), metav1.ListOptions{})
+func getClusterPrometheusToken(ctx context.Context, kubeClient kubernetes.Interface) (*string, error) {
+	secrets, err := kubeClient.CoreV1().Secrets(monitoringNamespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch secrets in openshift-monitoring")
 	}
@@ -75,7 +102,12 @@ func getClusterPrometheusToken(h *helper.H) (*string, error) {
 		}
 	}
 	if len(stringToken) == 0 {
-		return nil, fmt.Errorf("failed to find token secret for prometheus-k8s SA")
+		// Newer clusters don't create token secrets for service accounts, request a token instead
+		request, err := kubeClient.CoreV1().ServiceAccounts(monitoringNamespace).CreateToken(ctx, "prometheus-k8s", &authenticationv1.TokenRequest{}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find token secret for prometheus-k8s SA or to request a token: %w", err)
+		}
+		stringToken = request.Status.Token
 	}
 
 	return &stringToken, nil
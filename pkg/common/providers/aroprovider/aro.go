@@ -0,0 +1,290 @@
+// Package aroprovider allows osde2e to provision Azure Red Hat OpenShift clusters through the
+// Microsoft.RedHatOpenShift resource API.
+package aroprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// providerType is the name the ARO provider is registered under.
+	providerType = "aro"
+
+	// environment is the Azure cloud clusters are created in.
+	environment = "public"
+
+	// requestTimeout bounds every call to Azure.
+	requestTimeout = 2 * time.Minute
+)
+
+// errNotFound is returned for resources that don't exist.
+var errNotFound = errors.New("not found")
+
+// configClientBuilder builds an OpenShift config client from raw kubeconfig contents.
+type configClientBuilder func(kubeconfig []byte) (configclient.Interface, error)
+
+// AROProvider provisions clusters on Azure Red Hat OpenShift.
+type AROProvider struct {
+	subscriptionID string
+	tenantID       string
+	clientID       string
+	clientSecret   string
+	resourceGroup  string
+	location       string
+	endpoint       string
+	authorityHost  string
+	apiVersion     string
+
+	httpClient      *http.Client
+	newConfigClient configClientBuilder
+	token           *tokenCache
+
+	// ctx is the context the calls are bound to with Bind, none for the registered provider
+	ctx context.Context
+}
+
+// tokenCache is the access token shared by the provider and the providers bound to a context.
+type tokenCache struct {
+	mutex  sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// ARO clusters don't expire and have no proxy, addon or version gate API.
+var (
+	_ spi.Lifecycle     = &AROProvider{}
+	_ spi.Upgrader      = &AROProvider{}
+	_ spi.PropertyStore = &AROProvider{}
+	_ spi.ContextBinder = &AROProvider{}
+)
+
+// New creates a new AROProvider from the config.
+func New() (*AROProvider, error) {
+	return NewWithClient(&http.Client{Timeout: requestTimeout})
+}
+
+// NewWithClient creates a new AROProvider from the config that calls Azure with the given HTTP client,
+// e.g. one that talks to a local stub of the ARO API.
+func NewWithClient(httpClient *http.Client) (*AROProvider, error) {
+	a := &AROProvider{
+		token:           &tokenCache{},
+		subscriptionID:  viper.GetString(SubscriptionID),
+		tenantID:        viper.GetString(TenantID),
+		clientID:        viper.GetString(ClientID),
+		clientSecret:    viper.GetString(ClientSecret),
+		resourceGroup:   viper.GetString(ResourceGroup),
+		location:        viper.GetString(Location),
+		endpoint:        strings.TrimSuffix(viper.GetString(Endpoint), "/"),
+		authorityHost:   strings.TrimSuffix(viper.GetString(AuthorityHost), "/"),
+		apiVersion:      viper.GetString(APIVersion),
+		httpClient:      httpClient,
+		newConfigClient: newConfigClient,
+	}
+
+	for key, value := range map[string]string{
+		SubscriptionID: a.subscriptionID,
+		TenantID:       a.tenantID,
+		ClientID:       a.clientID,
+		ClientSecret:   a.clientSecret,
+		ResourceGroup:  a.resourceGroup,
+	} {
+		if value == "" {
+			return nil, fmt.Errorf("%s must be set for the ARO provider", key)
+		}
+	}
+
+	return a, nil
+}
+
+// newConfigClient builds an OpenShift config client from raw kubeconfig contents.
+func newConfigClient(kubeconfig []byte) (configclient.Interface, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return configclient.NewForConfig(restConfig)
+}
+
+// Type returns the provider type.
+func (a *AROProvider) Type() string {
+	return providerType
+}
+
+// Environment returns the Azure cloud clusters are created in.
+func (a *AROProvider) Environment() string {
+	return environment
+}
+
+// Metrics returns the metrics of the cluster.
+func (a *AROProvider) Metrics(clusterID string) (bool, error) {
+	return true, nil
+}
+
+// UpgradeSource returns Cincinnati, as ARO clusters only upgrade to released versions.
+func (a *AROProvider) UpgradeSource() spi.UpgradeSource {
+	return spi.CincinnatiSource
+}
+
+// CincinnatiChannel returns the stable channel, the only channel ARO supports.
+func (a *AROProvider) CincinnatiChannel() spi.CincinnatiChannel {
+	return spi.CincinnatiStableChannel
+}
+
+// CheckQuota always succeeds, Azure rejects the create request if the subscription is out of quota.
+func (a *AROProvider) CheckQuota(sku string) (bool, error) {
+	return true, nil
+}
+
+// DetermineMachineType returns the configured worker VM size.
+func (a *AROProvider) DetermineMachineType(cloudProvider string) (string, error) {
+	return viper.GetString(WorkerVMSize), nil
+}
+
+// Logs returns no logs, ARO doesn't expose install logs through its API.
+func (a *AROProvider) Logs(clusterID string) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
+}
+
+// clusterPath is the resource path of the cluster with the given name.
+func (a *AROProvider) clusterPath(name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.RedHatOpenShift/openShiftClusters/%s",
+		a.subscriptionID, a.resourceGroup, name)
+}
+
+// Bind returns the provider with its calls to Azure bound to ctx, so cancelling it interrupts them.
+func (a *AROProvider) Bind(ctx context.Context) spi.Lifecycle {
+	bound := *a
+	bound.ctx = ctx
+	return &bound
+}
+
+// callContext returns the context calls are bound to.
+func (a *AROProvider) callContext() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// accessToken returns a token for the resource manager, requesting a new one shortly before it expires.
+func (a *AROProvider) accessToken(ctx context.Context) (string, error) {
+	a.token.mutex.Lock()
+	defer a.token.mutex.Unlock()
+
+	if a.token.token != "" && time.Now().Before(a.token.expiry) {
+		return a.token.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {a.clientID},
+		"client_secret": {a.clientSecret},
+		"scope":         {a.endpoint + "/.default"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/%s/oauth2/v2.0/token", a.authorityHost, a.tenantID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting Azure token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("error requesting Azure token: %s: %s", resp.Status, body)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding Azure token: %w", err)
+	}
+
+	a.token.token = token.AccessToken
+	a.token.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return a.token.token, nil
+}
+
+// do sends a request to the resource manager and decodes the response into out, if given.
+// path is either a resource path or an absolute URL, e.g. the next page of a list.
+func (a *AROProvider) do(ctx context.Context, method, path string, in, out any) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := path
+	if !strings.HasPrefix(path, "http") {
+		target = fmt.Sprintf("%s%s?api-version=%s", a.endpoint, path, url.QueryEscape(a.apiVersion))
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling ARO API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Code != "" {
+			return fmt.Errorf("%s %s: %s: %s", method, path, apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error decoding ARO API response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package aroprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/client-go/config/clientset/versioned/fake"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const clustersPath = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters"
+
+// stubARO is a local stub of the Azure token endpoint and the ARO resource API.
+type stubARO struct {
+	mutex    sync.Mutex
+	clusters map[string]*openShiftCluster
+	tokens   int
+}
+
+func (s *stubARO) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+		s.tokens++
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != "2023-11-22" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if strings.HasSuffix(r.URL.Path, "/openShiftVersions") {
+		_, _ = w.Write([]byte(`{"value":[{"properties":{"version":"4.15.2"}},{"properties":{"version":"4.16.1"}},{"properties":{"version":"4.14.9"}}]}`))
+		return
+	}
+	if r.URL.Path == clustersPath {
+		var list struct {
+			Value []*openShiftCluster `json:"value"`
+		}
+		for _, cluster := range s.clusters {
+			list.Value = append(list.Value, cluster)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+		return
+	}
+
+	name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, clustersPath+"/"), "/")
+	cluster, ok := s.clusters[name]
+	switch {
+	case r.Method == http.MethodPut:
+		var created openShiftCluster
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		created.Name = name
+		created.Properties.ProvisioningState = "Creating"
+		s.clusters[name] = &created
+		w.WriteHeader(http.StatusCreated)
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":"ResourceNotFound","message":"not found"}}`))
+	case action == "listAdminCredentials":
+		kubeconfig := base64.StdEncoding.EncodeToString([]byte("kubeconfig of " + name))
+		_ = json.NewEncoder(w).Encode(map[string]string{"kubeconfig": kubeconfig})
+	case r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(cluster)
+	case r.Method == http.MethodPatch:
+		var patch openShiftCluster
+		_ = json.NewDecoder(r.Body).Decode(&patch)
+		cluster.Tags = patch.Tags
+	case r.Method == http.MethodDelete:
+		cluster.Properties.ProvisioningState = "Deleting"
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func newTestProvider(t *testing.T) (*AROProvider, *stubARO) {
+	stub := &stubARO{clusters: map[string]*openShiftCluster{}}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	for key, value := range map[string]string{
+		SubscriptionID: "sub",
+		TenantID:       "tenant",
+		ClientID:       "client",
+		ClientSecret:   "secret",
+		ResourceGroup:  "rg",
+		MasterSubnetID: "master-subnet",
+		WorkerSubnetID: "worker-subnet",
+		Endpoint:       server.URL,
+		AuthorityHost:  server.URL,
+	} {
+		viper.Set(key, value)
+	}
+
+	provider, err := NewWithClient(server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return provider, stub
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	provider, stub := newTestProvider(t)
+
+	if valid, err := provider.IsValidClusterName("osde2e-abc"); !valid || err != nil {
+		t.Errorf("expected new cluster name to be valid, got %v (%v)", valid, err)
+	}
+
+	id, err := provider.LaunchCluster("osde2e-abc")
+	if err != nil || id != "osde2e-abc" {
+		t.Fatalf("expected the cluster to be created, got %q (%v)", id, err)
+	}
+	if valid, _ := provider.IsValidClusterName("osde2e-abc"); valid {
+		t.Errorf("expected the name of an existing cluster to be invalid")
+	}
+	created := stub.clusters["osde2e-abc"]
+	if created.Properties.WorkerProfiles[0].SubnetID != "worker-subnet" || created.Properties.ClusterProfile.Domain != "osde2e-abc" {
+		t.Errorf("unexpected create request: %+v", created.Properties)
+	}
+
+	cluster, err := provider.GetCluster(id)
+	if err != nil || cluster.State() != spi.ClusterStateInstalling || cluster.NumComputeNodes() != 3 {
+		t.Errorf("expected an installing cluster with 3 workers, got %+v (%v)", cluster, err)
+	}
+	created.Properties.ProvisioningState = "Succeeded"
+	if cluster, _ := provider.GetCluster(id); cluster.State() != spi.ClusterStateReady {
+		t.Errorf("expected the cluster to be ready, got %s", cluster.State())
+	}
+
+	if err := provider.AddProperty(cluster, "Status", "healthy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := provider.GetProperty(id, "Status"); value != "healthy" {
+		t.Errorf("expected the property to be stored as a tag, got %q", value)
+	}
+
+	kubeconfig, err := provider.ClusterKubeconfig(id)
+	if err != nil || string(kubeconfig) != "kubeconfig of osde2e-abc" {
+		t.Errorf("expected the decoded admin kubeconfig, got %q (%v)", kubeconfig, err)
+	}
+
+	clusters, err := provider.ListClusters("")
+	if err != nil || len(clusters) != 1 {
+		t.Errorf("expected one cluster, got %d (%v)", len(clusters), err)
+	}
+
+	if err := provider.DeleteCluster(id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cluster, _ := provider.GetCluster(id); cluster.State() != spi.ClusterStateUninstalling {
+		t.Errorf("expected the cluster to be uninstalling, got %s", cluster.State())
+	}
+
+	if _, err := provider.GetCluster("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected an error for a missing cluster, got %v", err)
+	}
+	if stub.tokens != 1 {
+		t.Errorf("expected the token to be reused, requested %d", stub.tokens)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	provider, stub := newTestProvider(t)
+	stub.clusters["osde2e-abc"] = &openShiftCluster{Name: "osde2e-abc"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := provider.Bind(ctx).GetCluster("osde2e-abc"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the call to be cancelled, got %v", err)
+	}
+	// A run cleaning up after being cancelled still deletes its cluster
+	if err := provider.Bind(ctx).DeleteCluster("osde2e-abc"); err != nil {
+		t.Errorf("expected the delete not to be cancelled, got %v", err)
+	}
+	if state := stub.clusters["osde2e-abc"].Properties.ProvisioningState; state != "Deleting" {
+		t.Errorf("expected the cluster to be deleting, got %q", state)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	versions, err := provider.Versions()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions.AvailableVersions()) != 3 || versions.Default().String() != "4.16.1" {
+		t.Errorf("expected 3 versions with 4.16.1 as the default, got %d and %s", len(versions.AvailableVersions()), versions.Default())
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	provider, stub := newTestProvider(t)
+	stub.clusters["osde2e-abc"] = &openShiftCluster{Name: "osde2e-abc"}
+
+	client := fake.NewSimpleClientset(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterVersionName},
+		Spec:       configv1.ClusterVersionSpec{Channel: "stable-4.15"},
+	})
+	provider.newConfigClient = func(kubeconfig []byte) (configclient.Interface, error) {
+		if string(kubeconfig) != "kubeconfig of osde2e-abc" {
+			t.Errorf("expected the admin kubeconfig of the cluster, got %q", kubeconfig)
+		}
+		return client, nil
+	}
+
+	if err := provider.Upgrade("osde2e-abc", "4.16.1", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clusterVersion, _ := client.ConfigV1().ClusterVersions().Get(context.Background(), clusterVersionName, metav1.GetOptions{})
+	if clusterVersion.Spec.Channel != "stable-4.16" || clusterVersion.Spec.DesiredUpdate.Version != "4.16.1" {
+		t.Errorf("expected an upgrade to 4.16.1 on stable-4.16, got %s %+v", clusterVersion.Spec.Channel, clusterVersion.Spec.DesiredUpdate)
+	}
+
+	if policyID, err := provider.GetUpgradePolicyID("osde2e-abc"); policyID != "" || err != nil {
+		t.Errorf("expected no upgrade policy, got %q (%v)", policyID, err)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	provider := spi.Complete(&AROProvider{})
+	if _, ok := spi.As[spi.Upgrader](provider); !ok {
+		t.Errorf("expected ARO to upgrade clusters")
+	}
+	if _, ok := spi.As[spi.ExpiryManager](provider); ok {
+		t.Errorf("expected ARO clusters to not expire")
+	}
+}
@@ -0,0 +1,287 @@
+package aroprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+// openShiftCluster is the Microsoft.RedHatOpenShift/openShiftClusters resource.
+type openShiftCluster struct {
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties clusterProperties `json:"properties"`
+	SystemData *struct {
+		CreatedAt time.Time `json:"createdAt"`
+	} `json:"systemData,omitempty"`
+}
+
+type clusterProperties struct {
+	ProvisioningState       string                  `json:"provisioningState,omitempty"`
+	ClusterProfile          clusterProfile          `json:"clusterProfile"`
+	ServicePrincipalProfile servicePrincipalProfile `json:"servicePrincipalProfile"`
+	NetworkProfile          networkProfile          `json:"networkProfile"`
+	MasterProfile           masterProfile           `json:"masterProfile"`
+	WorkerProfiles          []workerProfile         `json:"workerProfiles"`
+	APIServerProfile        visibilityProfile       `json:"apiserverProfile"`
+	IngressProfiles         []ingressProfile        `json:"ingressProfiles"`
+}
+
+type clusterProfile struct {
+	Domain          string `json:"domain"`
+	Version         string `json:"version,omitempty"`
+	ResourceGroupID string `json:"resourceGroupId"`
+	PullSecret      string `json:"pullSecret,omitempty"`
+}
+
+type servicePrincipalProfile struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+type networkProfile struct {
+	PodCIDR     string `json:"podCidr"`
+	ServiceCIDR string `json:"serviceCidr"`
+}
+
+type masterProfile struct {
+	VMSize   string `json:"vmSize"`
+	SubnetID string `json:"subnetId"`
+}
+
+type workerProfile struct {
+	Name       string `json:"name"`
+	VMSize     string `json:"vmSize"`
+	DiskSizeGB int    `json:"diskSizeGB"`
+	SubnetID   string `json:"subnetId"`
+	Count      int    `json:"count"`
+}
+
+type visibilityProfile struct {
+	Visibility string `json:"visibility"`
+}
+
+type ingressProfile struct {
+	Name       string `json:"name"`
+	Visibility string `json:"visibility"`
+}
+
+// clusterStates maps ARO provisioning states to cluster states.
+var clusterStates = map[string]spi.ClusterState{
+	"Creating":      spi.ClusterStateInstalling,
+	"Updating":      spi.ClusterStateReady,
+	"AdminUpdating": spi.ClusterStateReady,
+	"Succeeded":     spi.ClusterStateReady,
+	"Deleting":      spi.ClusterStateUninstalling,
+	"Failed":        spi.ClusterStateError,
+	"Canceled":      spi.ClusterStateError,
+}
+
+// IsValidClusterName checks that no cluster with the given name exists in the resource group yet.
+func (a *AROProvider) IsValidClusterName(clusterName string) (bool, error) {
+	err := a.do(a.callContext(), http.MethodGet, a.clusterPath(clusterName), nil, nil)
+	if errors.Is(err, errNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// LaunchCluster requests a new cluster and returns its name as the cluster ID.
+//
+// The cluster is created asynchronously, GetCluster reports it as installing until it's ready.
+func (a *AROProvider) LaunchCluster(clusterName string) (string, error) {
+	for key, value := range map[string]string{
+		MasterSubnetID: viper.GetString(MasterSubnetID),
+		WorkerSubnetID: viper.GetString(WorkerSubnetID),
+	} {
+		if value == "" {
+			return "", fmt.Errorf("%s must be set to create ARO clusters", key)
+		}
+	}
+
+	cluster := openShiftCluster{
+		Location: a.location,
+		Properties: clusterProperties{
+			ClusterProfile: clusterProfile{
+				Domain:          strings.ToLower(clusterName),
+				Version:         strings.TrimPrefix(viper.GetString(config.Cluster.Version), util.VersionPrefix),
+				ResourceGroupID: fmt.Sprintf("/subscriptions/%s/resourceGroups/aro-%s", a.subscriptionID, clusterName),
+				PullSecret:      viper.GetString(PullSecret),
+			},
+			ServicePrincipalProfile: servicePrincipalProfile{
+				ClientID:     a.clientID,
+				ClientSecret: a.clientSecret,
+			},
+			NetworkProfile: networkProfile{
+				PodCIDR:     "10.128.0.0/14",
+				ServiceCIDR: "172.30.0.0/16",
+			},
+			MasterProfile: masterProfile{
+				VMSize:   viper.GetString(MasterVMSize),
+				SubnetID: viper.GetString(MasterSubnetID),
+			},
+			WorkerProfiles: []workerProfile{{
+				Name:       "worker",
+				VMSize:     viper.GetString(WorkerVMSize),
+				DiskSizeGB: 128,
+				SubnetID:   viper.GetString(WorkerSubnetID),
+				Count:      viper.GetInt(WorkerCount),
+			}},
+			APIServerProfile: visibilityProfile{Visibility: "Public"},
+			IngressProfiles:  []ingressProfile{{Name: "default", Visibility: "Public"}},
+		},
+	}
+
+	log.Printf("Creating ARO cluster %s in %s", clusterName, a.location)
+	if err := a.do(a.callContext(), http.MethodPut, a.clusterPath(clusterName), cluster, nil); err != nil {
+		return "", fmt.Errorf("error creating ARO cluster: %w", err)
+	}
+	return clusterName, nil
+}
+
+// DeleteCluster starts deleting the cluster without waiting for it to finish.
+func (a *AROProvider) DeleteCluster(clusterID string) error {
+	// Deletes aren't interrupted, so that a run cleaning up after being cancelled doesn't leak the cluster
+	if err := a.do(context.WithoutCancel(a.callContext()), http.MethodDelete, a.clusterPath(clusterID), nil, nil); err != nil {
+		return fmt.Errorf("error deleting ARO cluster: %w", err)
+	}
+	return nil
+}
+
+// ListClusters lists the clusters in the resource group. The query is ignored as the API has no search.
+func (a *AROProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.RedHatOpenShift/openShiftClusters",
+		a.subscriptionID, a.resourceGroup)
+
+	var clusters []*spi.Cluster
+	for path != "" {
+		var page struct {
+			Value    []openShiftCluster `json:"value"`
+			NextLink string             `json:"nextLink"`
+		}
+		if err := a.do(a.callContext(), http.MethodGet, path, nil, &page); err != nil {
+			return nil, fmt.Errorf("error listing ARO clusters: %w", err)
+		}
+		for _, cluster := range page.Value {
+			clusters = append(clusters, a.toCluster(cluster))
+		}
+		path = page.NextLink
+	}
+	return clusters, nil
+}
+
+// GetCluster gets the cluster.
+func (a *AROProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+	cluster, err := a.getCluster(a.callContext(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return a.toCluster(*cluster), nil
+}
+
+// GetClusterRegion returns the Azure region of the cluster.
+func (a *AROProvider) GetClusterRegion(clusterID string) (string, error) {
+	cluster, err := a.getCluster(a.callContext(), clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.Location, nil
+}
+
+// ClusterKubeconfig returns the admin kubeconfig of the cluster.
+func (a *AROProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	var credentials struct {
+		Kubeconfig string `json:"kubeconfig"`
+	}
+	if err := a.do(a.callContext(), http.MethodPost, a.clusterPath(clusterID)+"/listAdminCredentials", nil, &credentials); err != nil {
+		return nil, fmt.Errorf("error getting admin kubeconfig of ARO cluster: %w", err)
+	}
+
+	kubeconfig, err := base64.StdEncoding.DecodeString(credentials.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding admin kubeconfig of ARO cluster: %w", err)
+	}
+	return kubeconfig, nil
+}
+
+// AddProperty stores the property as a tag of the cluster resource.
+func (a *AROProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+	current, err := a.getCluster(a.callContext(), cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	tags := current.Tags
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	tags[tag] = value
+
+	patch := map[string]any{"tags": tags}
+	if err := a.do(a.callContext(), http.MethodPatch, a.clusterPath(cluster.ID()), patch, nil); err != nil {
+		return fmt.Errorf("error tagging ARO cluster: %w", err)
+	}
+	return nil
+}
+
+// GetProperty gets a property from the tags of the cluster resource.
+func (a *AROProvider) GetProperty(clusterID string, property string) (string, error) {
+	cluster, err := a.getCluster(a.callContext(), clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.Tags[property], nil
+}
+
+// getCluster gets the cluster resource.
+func (a *AROProvider) getCluster(ctx context.Context, clusterID string) (*openShiftCluster, error) {
+	var cluster openShiftCluster
+	if err := a.do(ctx, http.MethodGet, a.clusterPath(clusterID), nil, &cluster); err != nil {
+		return nil, fmt.Errorf("couldn't get ARO cluster '%s': %w", clusterID, err)
+	}
+	return &cluster, nil
+}
+
+// toCluster converts the cluster resource to a cluster.
+func (a *AROProvider) toCluster(cluster openShiftCluster) *spi.Cluster {
+	state, ok := clusterStates[cluster.Properties.ProvisioningState]
+	if !ok {
+		state = spi.ClusterStateUnknown
+	}
+
+	computeNodes := 0
+	for _, worker := range cluster.Properties.WorkerProfiles {
+		computeNodes += worker.Count
+	}
+
+	builder := spi.NewClusterBuilder().
+		ID(cluster.Name).
+		Name(cluster.Name).
+		Version(cluster.Properties.ClusterProfile.Version).
+		CloudProvider("azure").
+		Product(providerType).
+		Region(cluster.Location).
+		Flavour(providerType).
+		State(state).
+		NumComputeNodes(computeNodes).
+		Properties(cluster.Tags)
+	if cluster.SystemData != nil {
+		builder.CreationTimestamp(cluster.SystemData.CreatedAt)
+	}
+	return builder.Build()
+}
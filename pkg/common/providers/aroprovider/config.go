@@ -0,0 +1,112 @@
+package aroprovider
+
+import (
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+const (
+	// SubscriptionID is the Azure subscription clusters are created in.
+	// Env: AZURE_SUBSCRIPTION_ID
+	SubscriptionID = "aro.subscriptionID"
+
+	// TenantID is the Azure AD tenant of the service principal.
+	// Env: AZURE_TENANT_ID
+	TenantID = "aro.tenantID"
+
+	// ClientID is the client ID of the service principal used to call the ARO API.
+	// It's also the service principal of created clusters.
+	// Env: AZURE_CLIENT_ID
+	ClientID = "aro.clientID"
+
+	// ClientSecret is the client secret of the service principal.
+	// Env: AZURE_CLIENT_SECRET
+	ClientSecret = "aro.clientSecret"
+
+	// ResourceGroup is the resource group clusters are created in.
+	// Env: ARO_RESOURCE_GROUP
+	ResourceGroup = "aro.resourceGroup"
+
+	// Location is the Azure region clusters are created in.
+	// Env: ARO_LOCATION
+	Location = "aro.location"
+
+	// MasterSubnetID is the resource ID of the subnet for control plane nodes.
+	// Env: ARO_MASTER_SUBNET_ID
+	MasterSubnetID = "aro.masterSubnetID"
+
+	// WorkerSubnetID is the resource ID of the subnet for worker nodes.
+	// Env: ARO_WORKER_SUBNET_ID
+	WorkerSubnetID = "aro.workerSubnetID"
+
+	// MasterVMSize is the VM size of control plane nodes.
+	// Env: ARO_MASTER_VM_SIZE
+	MasterVMSize = "aro.masterVMSize"
+
+	// WorkerVMSize is the VM size of worker nodes.
+	// Env: ARO_WORKER_VM_SIZE
+	WorkerVMSize = "aro.workerVMSize"
+
+	// WorkerCount is the number of worker nodes.
+	// Env: ARO_WORKER_COUNT
+	WorkerCount = "aro.workerCount"
+
+	// PullSecret is the Red Hat pull secret passed to created clusters.
+	// Env: ARO_PULL_SECRET
+	PullSecret = "aro.pullSecret"
+
+	// Endpoint is the Azure Resource Manager endpoint.
+	// Env: ARO_ENDPOINT
+	Endpoint = "aro.endpoint"
+
+	// AuthorityHost is the Azure AD endpoint tokens are requested from.
+	// Env: AZURE_AUTHORITY_HOST
+	AuthorityHost = "aro.authorityHost"
+
+	// APIVersion is the version of the Microsoft.RedHatOpenShift API.
+	// Env: ARO_API_VERSION
+	APIVersion = "aro.apiVersion"
+)
+
+func init() {
+	// ----- ARO -----
+	_ = viper.BindEnv(SubscriptionID, "AZURE_SUBSCRIPTION_ID")
+
+	_ = viper.BindEnv(TenantID, "AZURE_TENANT_ID")
+
+	_ = viper.BindEnv(ClientID, "AZURE_CLIENT_ID")
+	config.RegisterSecret(ClientID, "azure-client-id")
+
+	_ = viper.BindEnv(ClientSecret, "AZURE_CLIENT_SECRET")
+	config.RegisterSecret(ClientSecret, "azure-client-secret")
+
+	_ = viper.BindEnv(ResourceGroup, "ARO_RESOURCE_GROUP")
+
+	viper.SetDefault(Location, "eastus")
+	_ = viper.BindEnv(Location, "ARO_LOCATION")
+
+	_ = viper.BindEnv(MasterSubnetID, "ARO_MASTER_SUBNET_ID")
+
+	_ = viper.BindEnv(WorkerSubnetID, "ARO_WORKER_SUBNET_ID")
+
+	viper.SetDefault(MasterVMSize, "Standard_D8s_v3")
+	_ = viper.BindEnv(MasterVMSize, "ARO_MASTER_VM_SIZE")
+
+	viper.SetDefault(WorkerVMSize, "Standard_D4s_v3")
+	_ = viper.BindEnv(WorkerVMSize, "ARO_WORKER_VM_SIZE")
+
+	viper.SetDefault(WorkerCount, 3)
+	_ = viper.BindEnv(WorkerCount, "ARO_WORKER_COUNT")
+
+	_ = viper.BindEnv(PullSecret, "ARO_PULL_SECRET")
+	config.RegisterSecret(PullSecret, "aro-pull-secret")
+
+	viper.SetDefault(Endpoint, "https://management.azure.com")
+	_ = viper.BindEnv(Endpoint, "ARO_ENDPOINT")
+
+	viper.SetDefault(AuthorityHost, "https://login.microsoftonline.com")
+	_ = viper.BindEnv(AuthorityHost, "AZURE_AUTHORITY_HOST")
+
+	viper.SetDefault(APIVersion, "2023-11-22")
+	_ = viper.BindEnv(APIVersion, "ARO_API_VERSION")
+}
@@ -0,0 +1,63 @@
+package aroprovider
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterVersionName is the name of the ClusterVersion of every OpenShift cluster.
+const clusterVersionName = "version"
+
+// Upgrade starts upgrading the cluster to the given version.
+//
+// ARO has no upgrade policies, upgrades are requested on the ClusterVersion of the cluster and start
+// right away, so t is ignored.
+func (a *AROProvider) Upgrade(clusterID string, version string, t time.Time) error {
+	target, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("error parsing upgrade version '%s': %w", version, err)
+	}
+	if time.Until(t) > 0 {
+		log.Printf("ARO upgrades can't be scheduled, upgrading %s to %s now", clusterID, version)
+	}
+
+	kubeconfig, err := a.ClusterKubeconfig(clusterID)
+	if err != nil {
+		return err
+	}
+	client, err := a.newConfigClient(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("couldn't create client for ARO cluster '%s': %w", clusterID, err)
+	}
+
+	clusterVersion, err := client.ConfigV1().ClusterVersions().Get(a.callContext(), clusterVersionName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting cluster version: %w", err)
+	}
+
+	// The channel has to offer the target version for a minor version upgrade
+	clusterVersion.Spec.Channel = fmt.Sprintf("%s-%d.%d", a.CincinnatiChannel(), target.Major(), target.Minor())
+	clusterVersion.Spec.DesiredUpdate = &configv1.Update{Version: target.String()}
+	if _, err := client.ConfigV1().ClusterVersions().Update(a.callContext(), clusterVersion, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error requesting upgrade of ARO cluster: %w", err)
+	}
+
+	log.Printf("Requested upgrade of ARO cluster %s to %s", clusterID, target)
+	return nil
+}
+
+// GetUpgradePolicyID returns no policy, ARO has no upgrade policies.
+func (a *AROProvider) GetUpgradePolicyID(clusterID string) (string, error) {
+	return "", nil
+}
+
+// UpdateSchedule is not supported, ARO upgrades can't be scheduled.
+func (a *AROProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	return spi.NewNotSupportedError(providerType, "UpdateSchedule")
+}
@@ -0,0 +1,54 @@
+package aroprovider
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Versions returns the OpenShift versions ARO can install in the configured location.
+// The newest version is the default.
+func (a *AROProvider) Versions() (*spi.VersionList, error) {
+	var list struct {
+		Value []struct {
+			Properties struct {
+				Version string `json:"version"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.RedHatOpenShift/locations/%s/openShiftVersions",
+		a.subscriptionID, a.location)
+	if err := a.do(a.callContext(), http.MethodGet, path, nil, &list); err != nil {
+		return nil, fmt.Errorf("error listing ARO versions: %w", err)
+	}
+
+	var versions []*semver.Version
+	for _, v := range list.Value {
+		version, err := semver.NewVersion(v.Properties.Version)
+		if err != nil {
+			log.Printf("Skipping ARO version '%s': %v", v.Properties.Version, err)
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no ARO versions available in %s", a.location)
+	}
+	sort.Sort(semver.Collection(versions))
+
+	available := make([]*spi.Version, 0, len(versions))
+	for i, version := range versions {
+		available = append(available, spi.NewVersionBuilder().
+			Version(version).
+			Default(i == len(versions)-1).
+			Build())
+	}
+
+	return spi.NewVersionListBuilder().
+		AvailableVersions(available).
+		Build(), nil
+}
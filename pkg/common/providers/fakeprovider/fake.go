@@ -0,0 +1,846 @@
+// Package fakeprovider is an in-memory spi.Provider for exercising osde2e orchestration without OCM
+// in tests. It isn't linked into the osde2e binary.
+package fakeprovider
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Name is the provider name the fake provider is registered under by Install.
+const Name = "fake"
+
+// DefaultLaunchScript is the sequence of states a launched cluster moves through, one per GetCluster call.
+var DefaultLaunchScript = []spi.ClusterState{
+	spi.ClusterStatePending,
+	spi.ClusterStateInstalling,
+	spi.ClusterStateReady,
+}
+
+// DefaultKubeconfig is returned by ClusterKubeconfig unless overridden with Kubeconfig.
+const DefaultKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: fake
+contexts:
+- context:
+    cluster: fake
+    user: admin
+  name: fake
+current-context: fake
+users:
+- name: admin
+  user:
+    token: fake
+`
+
+var (
+	registerOnce sync.Once
+	activeMutex  sync.Mutex
+	active       *FakeProvider
+)
+
+// Install registers the fake provider with spi on first use and makes p the provider
+// returned for Name. This lets a test drive code that looks up the provider from config.
+func Install(p *FakeProvider) {
+	activeMutex.Lock()
+	active = p
+	activeMutex.Unlock()
+
+	registerOnce.Do(func() {
+		spi.RegisterProvider(Name, func() (spi.Provider, error) {
+			activeMutex.Lock()
+			defer activeMutex.Unlock()
+			if active == nil {
+				return nil, fmt.Errorf("no fake provider installed")
+			}
+			return active, nil
+		})
+	})
+}
+
+// UpgradePolicy is an upgrade requested through the fake provider.
+type UpgradePolicy struct {
+	ID      string
+	Version string
+	Time    time.Time
+}
+
+// Proxy is the cluster-wide proxy configuration of a fake cluster.
+type Proxy struct {
+	HTTPSProxy   string
+	HTTPProxy    string
+	UserCABundle string
+}
+
+// fakeCluster is the mutable state of a cluster owned by the fake provider.
+type fakeCluster struct {
+	id              string
+	name            string
+	version         string
+	state           spi.ClusterState
+	script          []spi.ClusterState
+	created         time.Time
+	expiration      time.Time
+	addons          []string
+	properties      map[string]string
+	upgradePolicies []UpgradePolicy
+	gateAgreements  []string
+	proxy           Proxy
+}
+
+// FakeProvider is an in-memory, scriptable spi.Provider.
+type FakeProvider struct {
+	mutex sync.Mutex
+
+	environment   string
+	cloudProvider string
+	product       string
+	region        string
+	computeNodes  int
+	kubeconfig    []byte
+	quota         bool
+	versions      []*spi.Version
+	launchScript  []spi.ClusterState
+	deleteScript  []spi.ClusterState
+	logs          map[string][]byte
+	provisionErr  string
+	versionGates  map[string]string
+	errors        map[string]error
+	nextErrors    map[string][]error
+
+	clusters map[string]*fakeCluster
+	calls    []string
+	nextID   int
+}
+
+// New creates a fake provider with a single default version and the default launch script.
+func New() *FakeProvider {
+	return &FakeProvider{
+		environment:   "fake",
+		cloudProvider: "aws",
+		product:       "osd",
+		region:        "us-east-1",
+		computeNodes:  2,
+		kubeconfig:    []byte(DefaultKubeconfig),
+		quota:         true,
+		versions: []*spi.Version{
+			spi.NewVersionBuilder().Version(semver.MustParse("4.16.0")).Default(true).Build(),
+		},
+		launchScript: DefaultLaunchScript,
+		deleteScript: []spi.ClusterState{spi.ClusterStateUninstalling},
+		logs:         map[string][]byte{},
+		versionGates: map[string]string{},
+		errors:       map[string]error{},
+		nextErrors:   map[string][]error{},
+		clusters:     map[string]*fakeCluster{},
+	}
+}
+
+// WithVersions replaces the available versions. The first version is the default.
+func (p *FakeProvider) WithVersions(versions ...string) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.versions = nil
+	for i, v := range versions {
+		p.versions = append(p.versions, spi.NewVersionBuilder().
+			Version(semver.MustParse(v)).
+			Default(i == 0).
+			Build())
+	}
+	return p
+}
+
+// WithLaunchScript sets the states newly launched clusters move through, one per GetCluster call.
+func (p *FakeProvider) WithLaunchScript(states ...spi.ClusterState) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.launchScript = states
+	return p
+}
+
+// WithDeleteScript sets the states deleted clusters move through before they disappear.
+func (p *FakeProvider) WithDeleteScript(states ...spi.ClusterState) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.deleteScript = states
+	return p
+}
+
+// WithQuota sets the result of CheckQuota.
+func (p *FakeProvider) WithQuota(quota bool) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.quota = quota
+	return p
+}
+
+// WithKubeconfig sets the kubeconfig returned for ready clusters.
+func (p *FakeProvider) WithKubeconfig(kubeconfig []byte) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.kubeconfig = kubeconfig
+	return p
+}
+
+// WithLogs sets the provider logs returned for every cluster.
+func (p *FakeProvider) WithLogs(logs map[string][]byte) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.logs = logs
+	return p
+}
+
+// WithProvisionError sets the reason reported for clusters in the error state.
+func (p *FakeProvider) WithProvisionError(provisionError string) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.provisionErr = provisionError
+	return p
+}
+
+// WithVersionGate adds a version gate that must be agreed to before upgrading to the version.
+func (p *FakeProvider) WithVersionGate(version, gateID string) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.versionGates[version] = gateID
+	return p
+}
+
+// FailOn makes every call to the named spi.Provider method return err. A nil err clears the failure.
+func (p *FakeProvider) FailOn(method string, err error) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err == nil {
+		delete(p.errors, method)
+	} else {
+		p.errors[method] = err
+	}
+	return p
+}
+
+// FailNext makes the next call to the named spi.Provider method return err. Calls after it succeed
+// again, unless FailNext is called more than once or a failure is configured with FailOn.
+func (p *FakeProvider) FailNext(method string, err error) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.nextErrors[method] = append(p.nextErrors[method], err)
+	return p
+}
+
+// AddCluster adds an existing cluster in the given state, e.g. to test reusing a cluster.
+func (p *FakeProvider) AddCluster(id, name, version string, state spi.ClusterState) *FakeProvider {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.clusters[id] = p.newCluster(id, name, version, nil)
+	p.clusters[id].state = state
+	return p
+}
+
+// Script replaces the remaining state transitions of a cluster.
+func (p *FakeProvider) Script(clusterID string, states ...spi.ClusterState) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cluster, ok := p.clusters[clusterID]
+	if !ok {
+		return fmt.Errorf("cluster %s not found", clusterID)
+	}
+	cluster.script = states
+	return nil
+}
+
+// Calls returns the spi.Provider methods called so far, in order.
+func (p *FakeProvider) Calls() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string{}, p.calls...)
+}
+
+// Called returns true if the named method was called at least once.
+func (p *FakeProvider) Called(method string) bool {
+	for _, call := range p.Calls() {
+		if call == method {
+			return true
+		}
+	}
+	return false
+}
+
+// UpgradePolicies returns the upgrade policies scheduled for a cluster.
+func (p *FakeProvider) UpgradePolicies(clusterID string) []UpgradePolicy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if cluster, ok := p.clusters[clusterID]; ok {
+		return append([]UpgradePolicy{}, cluster.upgradePolicies...)
+	}
+	return nil
+}
+
+// GateAgreements returns the version gates agreed to for a cluster.
+func (p *FakeProvider) GateAgreements(clusterID string) []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if cluster, ok := p.clusters[clusterID]; ok {
+		return append([]string{}, cluster.gateAgreements...)
+	}
+	return nil
+}
+
+// ClusterProxy returns the proxy configuration of a cluster.
+func (p *FakeProvider) ClusterProxy(clusterID string) Proxy {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if cluster, ok := p.clusters[clusterID]; ok {
+		return cluster.proxy
+	}
+	return Proxy{}
+}
+
+// call records a method call and returns the failure configured for it, if any. Must be called with the mutex held.
+func (p *FakeProvider) call(method string) error {
+	p.calls = append(p.calls, method)
+	if next := p.nextErrors[method]; len(next) > 0 {
+		p.nextErrors[method] = next[1:]
+		return next[0]
+	}
+	return p.errors[method]
+}
+
+// newCluster creates the state for a cluster. Must be called with the mutex held.
+func (p *FakeProvider) newCluster(id, name, version string, script []spi.ClusterState) *fakeCluster {
+	now := time.Now()
+	return &fakeCluster{
+		id:         id,
+		name:       name,
+		version:    version,
+		state:      spi.ClusterStateUnknown,
+		script:     append([]spi.ClusterState{}, script...),
+		created:    now,
+		expiration: now.Add(6 * time.Hour),
+		addons:     []string{},
+		properties: map[string]string{},
+	}
+}
+
+// getCluster looks up a cluster. Must be called with the mutex held.
+func (p *FakeProvider) getCluster(clusterID string) (*fakeCluster, error) {
+	cluster, ok := p.clusters[clusterID]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	return cluster, nil
+}
+
+// build converts the cluster state into an spi.Cluster. Must be called with the mutex held.
+func (p *FakeProvider) build(cluster *fakeCluster) *spi.Cluster {
+	properties := make(map[string]string, len(cluster.properties))
+	for k, v := range cluster.properties {
+		properties[k] = v
+	}
+
+	builder := spi.NewClusterBuilder().
+		ID(cluster.id).
+		Name(cluster.name).
+		Version(cluster.version).
+		CloudProvider(p.cloudProvider).
+		Product(p.product).
+		Region(p.region).
+		CreationTimestamp(cluster.created).
+		ExpirationTimestamp(cluster.expiration).
+		State(cluster.state).
+		Flavour(Name).
+		Addons(append([]string{}, cluster.addons...)).
+		NumComputeNodes(p.computeNodes).
+		Properties(properties)
+	if cluster.state == spi.ClusterStateError {
+		builder.ProvisionError(p.provisionErr)
+	}
+	return builder.Build()
+}
+
+// IsValidClusterName returns false if a cluster with the name already exists.
+func (p *FakeProvider) IsValidClusterName(clusterName string) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("IsValidClusterName"); err != nil {
+		return false, err
+	}
+
+	for _, cluster := range p.clusters {
+		if cluster.name == clusterName {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// LaunchCluster creates a cluster that moves through the launch script.
+func (p *FakeProvider) LaunchCluster(clusterName string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("LaunchCluster"); err != nil {
+		return "", err
+	}
+
+	version := ""
+	for _, v := range p.versions {
+		if v.Default() {
+			version = v.Version().String()
+		}
+	}
+
+	p.nextID++
+	id := fmt.Sprintf("fake-%d", p.nextID)
+	p.clusters[id] = p.newCluster(id, clusterName, version, p.launchScript)
+	return id, nil
+}
+
+// DeleteCluster moves the cluster through the delete script, after which it is removed.
+func (p *FakeProvider) DeleteCluster(clusterID string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("DeleteCluster"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	cluster.script = append([]spi.ClusterState{}, p.deleteScript...)
+	cluster.script = append(cluster.script, "")
+	return nil
+}
+
+// ListClusters returns every cluster. The query is ignored.
+func (p *FakeProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("ListClusters"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(p.clusters))
+	for id := range p.clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	clusters := make([]*spi.Cluster, 0, len(ids))
+	for _, id := range ids {
+		clusters = append(clusters, p.build(p.clusters[id]))
+	}
+	return clusters, nil
+}
+
+// GetCluster advances the cluster one step through its script and returns it.
+// A cluster whose delete script has finished is removed and reported as not found.
+func (p *FakeProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("GetCluster"); err != nil {
+		return nil, err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cluster.script) > 0 {
+		next := cluster.script[0]
+		cluster.script = cluster.script[1:]
+		if next == "" {
+			delete(p.clusters, clusterID)
+			return nil, fmt.Errorf("cluster %s not found", clusterID)
+		}
+		cluster.state = next
+	}
+	return p.build(cluster), nil
+}
+
+// GetClusterRegion returns the configured region.
+func (p *FakeProvider) GetClusterRegion(clusterID string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("GetClusterRegion"); err != nil {
+		return "", err
+	}
+	if _, err := p.getCluster(clusterID); err != nil {
+		return "", err
+	}
+	return p.region, nil
+}
+
+// ClusterKubeconfig returns the configured kubeconfig once the cluster is ready.
+func (p *FakeProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("ClusterKubeconfig"); err != nil {
+		return nil, err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.state != spi.ClusterStateReady {
+		return nil, fmt.Errorf("cluster %s is %s, kubeconfig not available", clusterID, cluster.state)
+	}
+	return p.kubeconfig, nil
+}
+
+// CheckQuota returns the configured quota.
+func (p *FakeProvider) CheckQuota(sku string) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("CheckQuota"); err != nil {
+		return false, err
+	}
+	return p.quota, nil
+}
+
+// InstallAddons installs the addons that are not yet installed and returns how many were installed.
+func (p *FakeProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, params map[spi.AddOnID]spi.AddOnParams) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("InstallAddons"); err != nil {
+		return 0, err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return 0, err
+	}
+
+	installed := 0
+	for _, addonID := range addonIDs {
+		found := false
+		for _, addon := range cluster.addons {
+			if addon == addonID {
+				found = true
+			}
+		}
+		if !found {
+			cluster.addons = append(cluster.addons, addonID)
+			installed++
+		}
+	}
+	return installed, nil
+}
+
+// Versions returns the configured versions.
+func (p *FakeProvider) Versions() (*spi.VersionList, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("Versions"); err != nil {
+		return nil, err
+	}
+	return spi.NewVersionListBuilder().
+		AvailableVersions(append([]*spi.Version{}, p.versions...)).
+		Build(), nil
+}
+
+// Logs returns the configured logs.
+func (p *FakeProvider) Logs(clusterID string) (map[string][]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("Logs"); err != nil {
+		return nil, err
+	}
+	return p.logs, nil
+}
+
+// Metrics always succeeds.
+func (p *FakeProvider) Metrics(clusterID string) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("Metrics"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Environment returns the fake environment.
+func (p *FakeProvider) Environment() string {
+	return p.environment
+}
+
+// UpgradeSource returns Cincinnati.
+func (p *FakeProvider) UpgradeSource() spi.UpgradeSource {
+	return spi.CincinnatiSource
+}
+
+// CincinnatiChannel returns the stable channel.
+func (p *FakeProvider) CincinnatiChannel() spi.CincinnatiChannel {
+	return spi.CincinnatiStableChannel
+}
+
+// Type returns the provider type: fake
+func (p *FakeProvider) Type() string {
+	return Name
+}
+
+// ExtendExpiry extends the expiration time of a cluster.
+func (p *FakeProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("ExtendExpiry"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	cluster.expiration = cluster.expiration.
+		Add(time.Duration(hours) * time.Hour).
+		Add(time.Duration(minutes) * time.Minute).
+		Add(time.Duration(seconds) * time.Second)
+	return nil
+}
+
+// Expire sets the expiration of a cluster to now plus the duration.
+func (p *FakeProvider) Expire(clusterID string, duration time.Duration) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("Expire"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	cluster.expiration = time.Now().Add(duration)
+	return nil
+}
+
+// Hibernate moves a ready cluster through powering down to hibernating.
+func (p *FakeProvider) Hibernate(clusterID string) error {
+	return p.transition("Hibernate", clusterID, spi.ClusterStateReady, spi.ClusterStatePoweringDown, spi.ClusterStateHibernating)
+}
+
+// Resume moves a hibernating cluster through resuming to ready.
+func (p *FakeProvider) Resume(clusterID string) error {
+	return p.transition("Resume", clusterID, spi.ClusterStateHibernating, spi.ClusterStateResuming, spi.ClusterStateReady)
+}
+
+// transition scripts the states of a cluster in the from state, like the provider does for a request.
+func (p *FakeProvider) transition(method, clusterID string, from spi.ClusterState, states ...spi.ClusterState) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call(method); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.state != from || len(cluster.script) > 0 {
+		return fmt.Errorf("cluster %s is %s, not %s", clusterID, cluster.state, from)
+	}
+	cluster.script = append([]spi.ClusterState{}, states...)
+	return nil
+}
+
+// AddProperty sets a property on a cluster.
+func (p *FakeProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("AddProperty"); err != nil {
+		return err
+	}
+
+	fc, err := p.getCluster(cluster.ID())
+	if err != nil {
+		return err
+	}
+	fc.properties[tag] = value
+	return nil
+}
+
+// GetProperty gets a property from a cluster.
+func (p *FakeProvider) GetProperty(clusterID string, property string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("GetProperty"); err != nil {
+		return "", err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.properties[property], nil
+}
+
+// Upgrade schedules an upgrade policy for the cluster.
+func (p *FakeProvider) Upgrade(clusterID string, version string, t time.Time) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("Upgrade"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	cluster.upgradePolicies = append(cluster.upgradePolicies, UpgradePolicy{
+		ID:      fmt.Sprintf("%s-upgrade-%d", clusterID, len(cluster.upgradePolicies)+1),
+		Version: version,
+		Time:    t,
+	})
+	return nil
+}
+
+// GetUpgradePolicyID returns the first upgrade policy of the cluster.
+func (p *FakeProvider) GetUpgradePolicyID(clusterID string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("GetUpgradePolicyID"); err != nil {
+		return "", err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	if len(cluster.upgradePolicies) == 0 {
+		return "", fmt.Errorf("no upgrade policy found for cluster %s", clusterID)
+	}
+	return cluster.upgradePolicies[0].ID, nil
+}
+
+// UpdateSchedule reschedules an existing upgrade policy.
+func (p *FakeProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("UpdateSchedule"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	for i := range cluster.upgradePolicies {
+		if cluster.upgradePolicies[i].ID == policyID {
+			cluster.upgradePolicies[i].Version = version
+			cluster.upgradePolicies[i].Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("upgrade policy %s not found for cluster %s", policyID, clusterID)
+}
+
+// DetermineMachineType returns a fixed machine type.
+func (p *FakeProvider) DetermineMachineType(cloudProvider string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("DetermineMachineType"); err != nil {
+		return "", err
+	}
+	return "fake.large", nil
+}
+
+// AddClusterProxy sets the cluster-wide proxy.
+func (p *FakeProvider) AddClusterProxy(clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("AddClusterProxy"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterId)
+	if err != nil {
+		return err
+	}
+	cluster.proxy = Proxy{HTTPSProxy: httpsProxy, HTTPProxy: httpProxy, UserCABundle: userCABundle}
+	return nil
+}
+
+// RemoveClusterProxy removes the cluster-wide proxy.
+func (p *FakeProvider) RemoveClusterProxy(clusterId string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("RemoveClusterProxy"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterId)
+	if err != nil {
+		return err
+	}
+	cluster.proxy = Proxy{}
+	return nil
+}
+
+// RemoveUserCABundle removes only the additional trusted CA bundle.
+func (p *FakeProvider) RemoveUserCABundle(clusterId string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("RemoveUserCABundle"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterId)
+	if err != nil {
+		return err
+	}
+	cluster.proxy.UserCABundle = ""
+	return nil
+}
+
+// LoadUserCaBundleData loads CA contents from CA cert file.
+func (p *FakeProvider) LoadUserCaBundleData(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("can't read userCABundle file '%s': %w", file, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// VersionGateLabel returns the fake version gate label.
+func (p *FakeProvider) VersionGateLabel() string {
+	return "api.openshift.com/gate-ocp"
+}
+
+// GetVersionGateID returns the gate configured for the version.
+func (p *FakeProvider) GetVersionGateID(version string, label string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("GetVersionGateID"); err != nil {
+		return "", err
+	}
+	return p.versionGates[version], nil
+}
+
+// AddGateAgreement records a gate agreement for the cluster.
+func (p *FakeProvider) AddGateAgreement(clusterID string, versionGateID string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.call("AddGateAgreement"); err != nil {
+		return err
+	}
+
+	cluster, err := p.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	cluster.gateAgreements = append(cluster.gateAgreements, versionGateID)
+	return nil
+}
@@ -0,0 +1,188 @@
+package fakeprovider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+var _ spi.Provider = &FakeProvider{}
+
+func TestLaunchCluster(t *testing.T) {
+	provider := New().WithVersions("4.17.1", "4.16.3")
+
+	id, err := provider.LaunchCluster("test")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+
+	for _, expected := range []spi.ClusterState{
+		spi.ClusterStatePending,
+		spi.ClusterStateInstalling,
+		spi.ClusterStateReady,
+		spi.ClusterStateReady,
+	} {
+		cluster, err := provider.GetCluster(id)
+		if err != nil {
+			t.Fatalf("unexpected error getting cluster: %v", err)
+		}
+		if cluster.State() != expected {
+			t.Errorf("expected state %s, got %s", expected, cluster.State())
+		}
+		if cluster.Version() != "4.17.1" {
+			t.Errorf("expected default version 4.17.1, got %s", cluster.Version())
+		}
+	}
+
+	if valid, _ := provider.IsValidClusterName("test"); valid {
+		t.Errorf("expected name of existing cluster to be invalid")
+	}
+}
+
+func TestDeleteCluster(t *testing.T) {
+	provider := New().AddCluster("existing", "existing", "4.16.0", spi.ClusterStateReady)
+
+	if err := provider.DeleteCluster("existing"); err != nil {
+		t.Fatalf("unexpected error deleting cluster: %v", err)
+	}
+
+	cluster, err := provider.GetCluster("existing")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster: %v", err)
+	}
+	if cluster.State() != spi.ClusterStateUninstalling {
+		t.Errorf("expected state %s, got %s", spi.ClusterStateUninstalling, cluster.State())
+	}
+
+	if _, err := provider.GetCluster("existing"); err == nil {
+		t.Errorf("expected deleted cluster to be gone")
+	}
+}
+
+func TestScript(t *testing.T) {
+	provider := New().WithLaunchScript(spi.ClusterStateInstalling, spi.ClusterStateError)
+	id, _ := provider.LaunchCluster("broken")
+
+	provider.GetCluster(id)
+	cluster, _ := provider.GetCluster(id)
+	if cluster.State() != spi.ClusterStateError {
+		t.Errorf("expected state %s, got %s", spi.ClusterStateError, cluster.State())
+	}
+
+	if err := provider.Script(id, spi.ClusterStateHibernating); err != nil {
+		t.Fatalf("unexpected error scripting cluster: %v", err)
+	}
+	cluster, _ = provider.GetCluster(id)
+	if cluster.State() != spi.ClusterStateHibernating {
+		t.Errorf("expected state %s, got %s", spi.ClusterStateHibernating, cluster.State())
+	}
+
+	if _, err := provider.ClusterKubeconfig(id); err == nil {
+		t.Errorf("expected no kubeconfig for a hibernating cluster")
+	}
+}
+
+func TestAddonsPropertiesAndUpgrades(t *testing.T) {
+	provider := New().
+		AddCluster("c1", "c1", "4.16.0", spi.ClusterStateReady).
+		WithVersionGate("4.17", "gate-1")
+
+	installed, err := provider.InstallAddons("c1", []spi.AddOnID{"a", "b"}, nil)
+	if err != nil || installed != 2 {
+		t.Errorf("expected 2 addons installed, got %d (%v)", installed, err)
+	}
+	installed, _ = provider.InstallAddons("c1", []spi.AddOnID{"a"}, nil)
+	if installed != 0 {
+		t.Errorf("expected already installed addon to be skipped, got %d", installed)
+	}
+
+	cluster, _ := provider.GetCluster("c1")
+	if err := provider.AddProperty(cluster, "Status", "healthy"); err != nil {
+		t.Fatalf("unexpected error adding property: %v", err)
+	}
+	if value, _ := provider.GetProperty("c1", "Status"); value != "healthy" {
+		t.Errorf("expected property healthy, got %q", value)
+	}
+
+	now := time.Now()
+	if err := provider.Upgrade("c1", "4.17.0", now); err != nil {
+		t.Fatalf("unexpected error upgrading: %v", err)
+	}
+	policyID, err := provider.GetUpgradePolicyID("c1")
+	if err != nil {
+		t.Fatalf("unexpected error getting upgrade policy: %v", err)
+	}
+	if err := provider.UpdateSchedule("c1", "4.17.1", now.Add(time.Hour), policyID); err != nil {
+		t.Fatalf("unexpected error updating schedule: %v", err)
+	}
+	if policies := provider.UpgradePolicies("c1"); len(policies) != 1 || policies[0].Version != "4.17.1" {
+		t.Errorf("expected a single rescheduled upgrade policy, got %v", policies)
+	}
+
+	gateID, _ := provider.GetVersionGateID("4.17", provider.VersionGateLabel())
+	if err := provider.AddGateAgreement("c1", gateID); err != nil {
+		t.Fatalf("unexpected error adding gate agreement: %v", err)
+	}
+	if agreements := provider.GateAgreements("c1"); len(agreements) != 1 || agreements[0] != "gate-1" {
+		t.Errorf("expected agreement to gate-1, got %v", agreements)
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	failure := errors.New("boom")
+	provider := New().FailOn("LaunchCluster", failure)
+
+	if _, err := provider.LaunchCluster("test"); !errors.Is(err, failure) {
+		t.Errorf("expected injected failure, got %v", err)
+	}
+	if !provider.Called("LaunchCluster") {
+		t.Errorf("expected failed call to be recorded")
+	}
+
+	provider.FailOn("LaunchCluster", nil)
+	if _, err := provider.LaunchCluster("test"); err != nil {
+		t.Errorf("expected failure to be cleared, got %v", err)
+	}
+}
+
+func TestHibernateAndResume(t *testing.T) {
+	provider := New().AddCluster("c1", "c1", "4.16.0", spi.ClusterStateReady)
+
+	if err := provider.Resume("c1"); err == nil {
+		t.Errorf("expected a ready cluster not to be resumed")
+	}
+	if err := provider.Hibernate("c1"); err != nil {
+		t.Fatalf("unexpected error hibernating: %v", err)
+	}
+	for _, want := range []spi.ClusterState{spi.ClusterStatePoweringDown, spi.ClusterStateHibernating} {
+		if cluster, _ := provider.GetCluster("c1"); cluster.State() != want {
+			t.Errorf("expected state %s, got %s", want, cluster.State())
+		}
+	}
+
+	if err := provider.Resume("c1"); err != nil {
+		t.Fatalf("unexpected error resuming: %v", err)
+	}
+	for _, want := range []spi.ClusterState{spi.ClusterStateResuming, spi.ClusterStateReady} {
+		if cluster, _ := provider.GetCluster("c1"); cluster.State() != want {
+			t.Errorf("expected state %s, got %s", want, cluster.State())
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	first := New()
+	Install(first)
+	second := New()
+	Install(second)
+
+	provider, err := spi.GetProvider(Name)
+	if err != nil {
+		t.Fatalf("unexpected error getting provider: %v", err)
+	}
+	if provider != second {
+		t.Errorf("expected the most recently installed provider")
+	}
+}
@@ -0,0 +1,402 @@
+package hypershiftprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// IsValidClusterName checks that no HostedCluster with the given name exists yet.
+func (h *HyperShiftProvider) IsValidClusterName(clusterName string) (bool, error) {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	_, err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).Get(ctx, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// LaunchCluster creates the pull secret, HostedCluster and NodePool of a new hosted cluster and returns
+// its name as the cluster ID. The HyperShift operator provisions it asynchronously. The HostedCluster
+// and pull secret are deleted again if the NodePool can't be created.
+func (h *HyperShiftProvider) LaunchCluster(clusterName string) (string, error) {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	releaseImage, err := h.releaseImageFor(viper.GetString(config.Cluster.Version))
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: pullSecretName(clusterName), Namespace: h.namespace},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		StringData: map[string]string{corev1.DockerConfigJsonKey: h.pullSecret},
+	}
+	if _, err := h.kube.CoreV1().Secrets(h.namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("error creating pull secret: %w", err)
+	}
+
+	log.Printf("Creating hosted cluster %s/%s with %s", h.namespace, clusterName, releaseImage)
+	if _, err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).Create(ctx, h.hostedCluster(clusterName, releaseImage), metav1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("error creating HostedCluster: %w", err)
+	}
+	if _, err := h.dynamic.Resource(nodePoolResource).Namespace(h.namespace).Create(ctx, h.nodePool(clusterName, releaseImage), metav1.CreateOptions{}); err != nil {
+		err = fmt.Errorf("error creating NodePool: %w", err)
+		// Don't leave a hosted cluster without workers behind. If that fails too, return its name so
+		// that the caller can delete it.
+		if deleteErr := h.DeleteCluster(clusterName); deleteErr != nil {
+			return clusterName, errors.Join(err, fmt.Errorf("error rolling back hosted cluster %s: %w", clusterName, deleteErr))
+		}
+		return "", err
+	}
+	return clusterName, nil
+}
+
+// DeleteCluster starts deleting the NodePools, HostedCluster and pull secret of the cluster without
+// waiting for the HyperShift operator to tear it down.
+func (h *HyperShiftProvider) DeleteCluster(clusterID string) error {
+	// Deletes aren't interrupted, so that a run cleaning up after being cancelled doesn't leak the
+	// cluster. This includes rolling back a launch whose NodePool couldn't be created.
+	if h.ctx != nil {
+		h = h.bind(context.WithoutCancel(h.ctx))
+	}
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	nodePools, err := h.nodePools(clusterID)
+	if err != nil {
+		return err
+	}
+	for _, nodePool := range nodePools {
+		if err := h.dynamic.Resource(nodePoolResource).Namespace(h.namespace).Delete(ctx, nodePool.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting NodePool %s: %w", nodePool.GetName(), err)
+		}
+	}
+	if err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).Delete(ctx, clusterID, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting HostedCluster: %w", err)
+	}
+	if err := h.kube.CoreV1().Secrets(h.namespace).Delete(ctx, pullSecretName(clusterID), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting pull secret: %w", err)
+	}
+	return nil
+}
+
+// ListClusters lists the hosted clusters osde2e created. The query is ignored as there is no server-side search.
+func (h *HyperShiftProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	list, err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).List(ctx, metav1.ListOptions{LabelSelector: ownedLabel + "=true"})
+	if err != nil {
+		return nil, fmt.Errorf("error listing HostedClusters: %w", err)
+	}
+
+	clusters := make([]*spi.Cluster, 0, len(list.Items))
+	for i := range list.Items {
+		cluster, err := h.toCluster(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// GetCluster builds the cluster from its HostedCluster and NodePools.
+func (h *HyperShiftProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return h.toCluster(hostedCluster)
+}
+
+// GetClusterRegion returns the cloud region of the hosted cluster, if its platform has one.
+func (h *HyperShiftProvider) GetClusterRegion(clusterID string) (string, error) {
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	region, _, _ := unstructured.NestedString(hostedCluster.Object, "spec", "platform", "aws", "region")
+	return region, nil
+}
+
+// ClusterKubeconfig returns the admin kubeconfig of the hosted cluster, which the HyperShift operator
+// publishes in a secret once the control plane is up.
+func (h *HyperShiftProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	secretName, _, _ := unstructured.NestedString(hostedCluster.Object, "status", "kubeconfig", "name")
+	if secretName == "" {
+		return nil, fmt.Errorf("hosted cluster %s has no kubeconfig yet", clusterID)
+	}
+
+	ctx, cancel := h.requestContext()
+	defer cancel()
+	secret, err := h.kube.CoreV1().Secrets(h.namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting kubeconfig of hosted cluster %s: %w", clusterID, err)
+	}
+	kubeconfig, ok := secret.Data["kubeconfig"]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s of hosted cluster %s has no kubeconfig", secretName, clusterID)
+	}
+	return kubeconfig, nil
+}
+
+// AddProperty stores the property as an annotation of the HostedCluster.
+func (h *HyperShiftProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	hostedCluster, err := h.getHostedCluster(cluster.ID())
+	if err != nil {
+		return err
+	}
+	annotations := hostedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[propertyPrefix+tag] = value
+	hostedCluster.SetAnnotations(annotations)
+
+	if _, err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).Update(ctx, hostedCluster, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error annotating HostedCluster: %w", err)
+	}
+	return nil
+}
+
+// GetProperty gets a property from the annotations of the HostedCluster.
+func (h *HyperShiftProvider) GetProperty(clusterID string, property string) (string, error) {
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	return hostedCluster.GetAnnotations()[propertyPrefix+property], nil
+}
+
+// Logs returns the conditions of the HostedCluster and its NodePools, which explain stuck installs.
+func (h *HyperShiftProvider) Logs(clusterID string) (map[string][]byte, error) {
+	logs := map[string][]byte{}
+
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	logs["hostedcluster-conditions"] = formatConditions(hostedCluster)
+
+	nodePools, err := h.nodePools(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodePools {
+		logs["nodepool-"+nodePools[i].GetName()+"-conditions"] = formatConditions(&nodePools[i])
+	}
+	return logs, nil
+}
+
+// getHostedCluster gets the HostedCluster of the cluster.
+func (h *HyperShiftProvider) getHostedCluster(clusterID string) (*unstructured.Unstructured, error) {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	hostedCluster, err := h.dynamic.Resource(hostedClusterResource).Namespace(h.namespace).Get(ctx, clusterID, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get hosted cluster '%s': %w", clusterID, err)
+	}
+	return hostedCluster, nil
+}
+
+// nodePools returns the NodePools of the cluster.
+func (h *HyperShiftProvider) nodePools(clusterID string) ([]unstructured.Unstructured, error) {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	list, err := h.dynamic.Resource(nodePoolResource).Namespace(h.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing NodePools: %w", err)
+	}
+
+	var nodePools []unstructured.Unstructured
+	for _, nodePool := range list.Items {
+		if name, _, _ := unstructured.NestedString(nodePool.Object, "spec", "clusterName"); name == clusterID {
+			nodePools = append(nodePools, nodePool)
+		}
+	}
+	return nodePools, nil
+}
+
+// toCluster builds the cluster from the HostedCluster and its NodePools.
+func (h *HyperShiftProvider) toCluster(hostedCluster *unstructured.Unstructured) (*spi.Cluster, error) {
+	nodePools, err := h.nodePools(hostedCluster.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	computeNodes := 0
+	for _, nodePool := range nodePools {
+		replicas, _, _ := unstructured.NestedInt64(nodePool.Object, "status", "replicas")
+		computeNodes += int(replicas)
+	}
+
+	version, _, _ := unstructured.NestedString(hostedCluster.Object, "status", "version", "desired", "version")
+	region, _, _ := unstructured.NestedString(hostedCluster.Object, "spec", "platform", "aws", "region")
+
+	properties := map[string]string{}
+	for key, value := range hostedCluster.GetAnnotations() {
+		if property, ok := strings.CutPrefix(key, propertyPrefix); ok {
+			properties[property] = value
+		}
+	}
+
+	return spi.NewClusterBuilder().
+		ID(hostedCluster.GetName()).
+		Name(hostedCluster.GetName()).
+		Version(version).
+		CloudProvider(strings.ToLower(h.platform)).
+		Product(providerType).
+		Region(region).
+		Flavour(providerType).
+		CreationTimestamp(hostedCluster.GetCreationTimestamp().Time).
+		State(clusterState(hostedCluster, nodePools)).
+		NumComputeNodes(computeNodes).
+		Properties(properties).
+		Build(), nil
+}
+
+// clusterState derives the state of the cluster from the conditions of its HostedCluster and NodePools.
+//
+// The cluster is ready once the control plane is available and every NodePool has its nodes, and in
+// error if HyperShift rejected its configuration or release image.
+func clusterState(hostedCluster *unstructured.Unstructured, nodePools []unstructured.Unstructured) spi.ClusterState {
+	if hostedCluster.GetDeletionTimestamp() != nil {
+		return spi.ClusterStateUninstalling
+	}
+
+	for _, condition := range []string{"ValidConfiguration", "ValidReleaseImage", "SupportedHostedCluster"} {
+		if conditionStatus(hostedCluster, condition) == metav1.ConditionFalse {
+			return spi.ClusterStateError
+		}
+	}
+	if conditionStatus(hostedCluster, "Available") != metav1.ConditionTrue {
+		return spi.ClusterStateInstalling
+	}
+
+	for _, nodePool := range nodePools {
+		if conditionStatus(&nodePool, "Ready") != metav1.ConditionTrue {
+			return spi.ClusterStateInstalling
+		}
+	}
+	return spi.ClusterStateReady
+}
+
+// conditionStatus returns the status of the condition of the object, or Unknown if it has none.
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) metav1.ConditionStatus {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		if status, ok := condition["status"].(string); ok {
+			return metav1.ConditionStatus(status)
+		}
+	}
+	return metav1.ConditionUnknown
+}
+
+// formatConditions lists the conditions of the object, one per line.
+func formatConditions(obj *unstructured.Unstructured) []byte {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var b strings.Builder
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]any); ok {
+			fmt.Fprintf(&b, "%v=%v %v: %v\n", condition["type"], condition["status"], condition["reason"], condition["message"])
+		}
+	}
+	return []byte(b.String())
+}
+
+// hostedCluster builds the HostedCluster of a new cluster.
+func (h *HyperShiftProvider) hostedCluster(clusterName, releaseImage string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": hostedClusterResource.GroupVersion().String(),
+		"kind":       "HostedCluster",
+		"metadata": map[string]any{
+			"name":      clusterName,
+			"namespace": h.namespace,
+			"labels":    map[string]any{ownedLabel: "true"},
+		},
+		"spec": map[string]any{
+			"release":    map[string]any{"image": releaseImage},
+			"pullSecret": map[string]any{"name": pullSecretName(clusterName)},
+			"platform":   map[string]any{"type": h.platform},
+			"dns":        map[string]any{"baseDomain": h.baseDomain},
+			"etcd":       map[string]any{"managementType": "Managed"},
+			"networking": map[string]any{
+				"networkType":    "OVNKubernetes",
+				"clusterNetwork": []any{map[string]any{"cidr": "10.132.0.0/14"}},
+				"serviceNetwork": []any{map[string]any{"cidr": "172.31.0.0/16"}},
+			},
+			"services": []any{
+				publishingStrategy("APIServer", "LoadBalancer"),
+				publishingStrategy("OAuthServer", "Route"),
+				publishingStrategy("Konnectivity", "Route"),
+				publishingStrategy("Ignition", "Route"),
+			},
+		},
+	}}
+}
+
+// nodePool builds the NodePool of the workers of a new cluster.
+func (h *HyperShiftProvider) nodePool(clusterName, releaseImage string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": nodePoolResource.GroupVersion().String(),
+		"kind":       "NodePool",
+		"metadata": map[string]any{
+			"name":      nodePoolName(clusterName),
+			"namespace": h.namespace,
+			"labels":    map[string]any{ownedLabel: "true"},
+		},
+		"spec": map[string]any{
+			"clusterName": clusterName,
+			"replicas":    h.nodePoolReplicas,
+			"release":     map[string]any{"image": releaseImage},
+			"platform":    map[string]any{"type": h.platform},
+			"management":  map[string]any{"upgradeType": "Replace"},
+		},
+	}}
+}
+
+func publishingStrategy(service, strategy string) map[string]any {
+	return map[string]any{
+		"service":                   service,
+		"servicePublishingStrategy": map[string]any{"type": strategy},
+	}
+}
+
+func pullSecretName(clusterName string) string {
+	return clusterName + "-pull-secret"
+}
+
+func nodePoolName(clusterName string) string {
+	return clusterName + "-workers"
+}
@@ -0,0 +1,63 @@
+package hypershiftprovider
+
+import (
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+const (
+	// ManagementKubeconfig is the path of the kubeconfig of the management cluster running the HyperShift operator.
+	// Env: HYPERSHIFT_MANAGEMENT_KUBECONFIG
+	ManagementKubeconfig = "hypershift.managementKubeconfig"
+
+	// Namespace is the namespace on the management cluster HostedClusters and NodePools are created in.
+	// Env: HYPERSHIFT_NAMESPACE
+	Namespace = "hypershift.namespace"
+
+	// Platform is the platform type of hosted clusters, e.g. KubeVirt, Agent, AWS or None.
+	// Env: HYPERSHIFT_PLATFORM
+	Platform = "hypershift.platform"
+
+	// BaseDomain is the DNS base domain of hosted clusters.
+	// Env: HYPERSHIFT_BASE_DOMAIN
+	BaseDomain = "hypershift.baseDomain"
+
+	// PullSecret is the pull secret of hosted clusters.
+	// Env: HYPERSHIFT_PULL_SECRET
+	PullSecret = "hypershift.pullSecret"
+
+	// Version is the OpenShift version Versions reports as the default.
+	// Env: HYPERSHIFT_VERSION
+	Version = "hypershift.version"
+
+	// ReleaseImage overrides the release image of hosted clusters, which is otherwise the release of the cluster version.
+	// Env: HYPERSHIFT_RELEASE_IMAGE
+	ReleaseImage = "hypershift.releaseImage"
+
+	// NodePoolReplicas is the number of worker nodes of hosted clusters.
+	// Env: HYPERSHIFT_NODEPOOL_REPLICAS
+	NodePoolReplicas = "hypershift.nodePoolReplicas"
+)
+
+func init() {
+	// ----- HyperShift -----
+	_ = viper.BindEnv(ManagementKubeconfig, "HYPERSHIFT_MANAGEMENT_KUBECONFIG")
+
+	viper.SetDefault(Namespace, "clusters")
+	_ = viper.BindEnv(Namespace, "HYPERSHIFT_NAMESPACE")
+
+	viper.SetDefault(Platform, "KubeVirt")
+	_ = viper.BindEnv(Platform, "HYPERSHIFT_PLATFORM")
+
+	_ = viper.BindEnv(BaseDomain, "HYPERSHIFT_BASE_DOMAIN")
+
+	_ = viper.BindEnv(PullSecret, "HYPERSHIFT_PULL_SECRET")
+	config.RegisterSecret(PullSecret, "hypershift-pull-secret")
+
+	_ = viper.BindEnv(Version, "HYPERSHIFT_VERSION")
+
+	_ = viper.BindEnv(ReleaseImage, "HYPERSHIFT_RELEASE_IMAGE")
+
+	viper.SetDefault(NodePoolReplicas, 2)
+	_ = viper.BindEnv(NodePoolReplicas, "HYPERSHIFT_NODEPOOL_REPLICAS")
+}
@@ -0,0 +1,198 @@
+// Package hypershiftprovider allows osde2e to provision hosted control plane clusters by creating
+// HostedCluster and NodePool objects directly on a HyperShift management cluster, without OCM.
+package hypershiftprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// providerType is the name the HyperShift provider is registered under.
+	providerType = "hypershift"
+
+	// ownedLabel marks the HostedClusters osde2e created.
+	ownedLabel = "osde2e.openshift.io/owned"
+
+	// propertyPrefix is the prefix of the annotations cluster properties are stored in.
+	propertyPrefix = "osde2e.openshift.io/"
+
+	// releaseRepository is where release images of OpenShift versions are published.
+	releaseRepository = "quay.io/openshift-release-dev/ocp-release"
+
+	// requestTimeout bounds every call to the management cluster.
+	requestTimeout = time.Minute
+)
+
+var (
+	hostedClusterResource = schema.GroupVersionResource{Group: "hypershift.openshift.io", Version: "v1beta1", Resource: "hostedclusters"}
+	nodePoolResource      = schema.GroupVersionResource{Group: "hypershift.openshift.io", Version: "v1beta1", Resource: "nodepools"}
+)
+
+// HyperShiftProvider manages hosted clusters on a HyperShift management cluster.
+type HyperShiftProvider struct {
+	namespace        string
+	platform         string
+	baseDomain       string
+	pullSecret       string
+	version          string
+	releaseImage     string
+	nodePoolReplicas int64
+
+	dynamic dynamic.Interface
+	kube    kubernetes.Interface
+
+	// ctx is the context the calls are bound to with Bind, none for the registered provider
+	ctx context.Context
+}
+
+// Hosted clusters don't expire and have no proxy, addon or version gate API.
+var (
+	_ spi.Lifecycle     = &HyperShiftProvider{}
+	_ spi.Upgrader      = &HyperShiftProvider{}
+	_ spi.PropertyStore = &HyperShiftProvider{}
+	_ spi.ContextBinder = &HyperShiftProvider{}
+)
+
+// New creates a new HyperShiftProvider for the management cluster of the config.
+func New() (*HyperShiftProvider, error) {
+	path := viper.GetString(ManagementKubeconfig)
+	if path == "" {
+		return nil, fmt.Errorf("%s must be set for the HyperShift provider", ManagementKubeconfig)
+	}
+	kubeconfig, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading management cluster kubeconfig: %w", err)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing management cluster kubeconfig: %w", err)
+	}
+	restConfig.Timeout = requestTimeout
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating management cluster client: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating management cluster client: %w", err)
+	}
+	return NewWithClients(dynamicClient, kubeClient), nil
+}
+
+// NewWithClients creates a new HyperShiftProvider from the config that manages hosted clusters
+// with the given management cluster clients.
+func NewWithClients(dynamicClient dynamic.Interface, kubeClient kubernetes.Interface) *HyperShiftProvider {
+	return &HyperShiftProvider{
+		namespace:        viper.GetString(Namespace),
+		platform:         viper.GetString(Platform),
+		baseDomain:       viper.GetString(BaseDomain),
+		pullSecret:       viper.GetString(PullSecret),
+		version:          viper.GetString(Version),
+		releaseImage:     viper.GetString(ReleaseImage),
+		nodePoolReplicas: viper.GetInt64(NodePoolReplicas),
+		dynamic:          dynamicClient,
+		kube:             kubeClient,
+	}
+}
+
+// Type returns the provider type.
+func (h *HyperShiftProvider) Type() string {
+	return providerType
+}
+
+// Environment returns the namespace hosted clusters are created in.
+func (h *HyperShiftProvider) Environment() string {
+	return h.namespace
+}
+
+// Metrics returns the metrics of the cluster.
+func (h *HyperShiftProvider) Metrics(clusterID string) (bool, error) {
+	return true, nil
+}
+
+// UpgradeSource returns the release controller, hosted clusters can run any release image.
+func (h *HyperShiftProvider) UpgradeSource() spi.UpgradeSource {
+	return spi.ReleaseControllerSource
+}
+
+// CincinnatiChannel returns the stable channel.
+func (h *HyperShiftProvider) CincinnatiChannel() spi.CincinnatiChannel {
+	return spi.CincinnatiStableChannel
+}
+
+// CheckQuota always succeeds, the management cluster has no quota.
+func (h *HyperShiftProvider) CheckQuota(sku string) (bool, error) {
+	return true, nil
+}
+
+// DetermineMachineType is not supported, node pools use the defaults of their platform.
+func (h *HyperShiftProvider) DetermineMachineType(cloudProvider string) (string, error) {
+	return "", spi.NewNotSupportedError(providerType, "DetermineMachineType")
+}
+
+// Versions returns the configured version, the management cluster doesn't list installable releases.
+func (h *HyperShiftProvider) Versions() (*spi.VersionList, error) {
+	if h.version == "" {
+		return nil, fmt.Errorf("%s must be set to select a version for the HyperShift provider", Version)
+	}
+	version, err := semver.NewVersion(h.version)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse HyperShift version '%s': %w", h.version, err)
+	}
+
+	return spi.NewVersionListBuilder().
+		AvailableVersions([]*spi.Version{
+			spi.NewVersionBuilder().
+				Version(version).
+				Default(true).
+				Build(),
+		}).
+		Build(), nil
+}
+
+// releaseImageFor returns the release image of the given version, or the configured override.
+func (h *HyperShiftProvider) releaseImageFor(version string) (string, error) {
+	if h.releaseImage != "" {
+		return h.releaseImage, nil
+	}
+	if version == "" {
+		return "", fmt.Errorf("a cluster version or %s must be set to create hosted clusters", ReleaseImage)
+	}
+	return fmt.Sprintf("%s:%s-multi", releaseRepository, strings.TrimPrefix(version, util.VersionPrefix)), nil
+}
+
+// Bind returns the provider with its calls to the management cluster bound to ctx, so cancelling it
+// interrupts them.
+func (h *HyperShiftProvider) Bind(ctx context.Context) spi.Lifecycle {
+	return h.bind(ctx)
+}
+
+func (h *HyperShiftProvider) bind(ctx context.Context) *HyperShiftProvider {
+	bound := *h
+	bound.ctx = ctx
+	return &bound
+}
+
+// requestContext bounds a call to the management cluster by requestTimeout and the context the
+// provider is bound to.
+func (h *HyperShiftProvider) requestContext() (context.Context, context.CancelFunc) {
+	ctx := h.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, requestTimeout)
+}
@@ -0,0 +1,294 @@
+package hypershiftprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestProvider(t *testing.T) *HyperShiftProvider {
+	viper.Set(config.Cluster.Version, "openshift-v4.16.1")
+	viper.Set(PullSecret, `{"auths":{}}`)
+	viper.Set(BaseDomain, "example.com")
+	t.Cleanup(func() {
+		viper.Set(config.Cluster.Version, "")
+		viper.Set(PullSecret, "")
+		viper.Set(BaseDomain, "")
+	})
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		hostedClusterResource: "HostedClusterList",
+		nodePoolResource:      "NodePoolList",
+	})
+	return NewWithClients(dynamicClient, kubefake.NewSimpleClientset())
+}
+
+// setStatus sets the status of the object, as the HyperShift operator would.
+func setStatus(t *testing.T, h *HyperShiftProvider, resource schema.GroupVersionResource, name string, status map[string]any) {
+	ctx := context.Background()
+	obj, err := h.dynamic.Resource(resource).Namespace(h.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj.Object["status"] = status
+	if _, err := h.dynamic.Resource(resource).Namespace(h.namespace).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func condition(conditionType, status string) map[string]any {
+	return map[string]any{"type": conditionType, "status": status}
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	provider := newTestProvider(t)
+	ctx := context.Background()
+
+	if valid, err := provider.IsValidClusterName("osde2e-abc"); !valid || err != nil {
+		t.Errorf("expected new cluster name to be valid, got %v (%v)", valid, err)
+	}
+
+	id, err := provider.LaunchCluster("osde2e-abc")
+	if err != nil || id != "osde2e-abc" {
+		t.Fatalf("expected the cluster to be created, got %q (%v)", id, err)
+	}
+	if valid, _ := provider.IsValidClusterName("osde2e-abc"); valid {
+		t.Errorf("expected the name of an existing cluster to be invalid")
+	}
+
+	hostedCluster, err := provider.getHostedCluster(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image, _, _ := unstructured.NestedString(hostedCluster.Object, "spec", "release", "image"); image != releaseRepository+":4.16.1-multi" {
+		t.Errorf("expected the release image of the cluster version, got %q", image)
+	}
+	if _, err := provider.kube.CoreV1().Secrets(provider.namespace).Get(ctx, "osde2e-abc-pull-secret", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the pull secret to be created: %v", err)
+	}
+
+	cluster, err := provider.GetCluster(id)
+	if err != nil || cluster.State() != spi.ClusterStateInstalling {
+		t.Errorf("expected an installing cluster, got %+v (%v)", cluster, err)
+	}
+
+	setStatus(t, provider, hostedClusterResource, id, map[string]any{
+		"conditions": []any{condition("Available", "True")},
+		"version":    map[string]any{"desired": map[string]any{"version": "4.16.1"}},
+		"kubeconfig": map[string]any{"name": "osde2e-abc-admin-kubeconfig"},
+	})
+	setStatus(t, provider, nodePoolResource, "osde2e-abc-workers", map[string]any{
+		"conditions": []any{condition("Ready", "True")},
+		"replicas":   int64(2),
+	})
+	cluster, err = provider.GetCluster(id)
+	if err != nil || cluster.State() != spi.ClusterStateReady || cluster.NumComputeNodes() != 2 || cluster.Version() != "4.16.1" {
+		t.Errorf("expected a ready 4.16.1 cluster with 2 workers, got %+v (%v)", cluster, err)
+	}
+
+	if _, err := provider.kube.CoreV1().Secrets(provider.namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "osde2e-abc-admin-kubeconfig", Namespace: provider.namespace},
+		Data:       map[string][]byte{"kubeconfig": []byte("kubeconfig of osde2e-abc")},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kubeconfig, err := provider.ClusterKubeconfig(id)
+	if err != nil || string(kubeconfig) != "kubeconfig of osde2e-abc" {
+		t.Errorf("expected the admin kubeconfig, got %q (%v)", kubeconfig, err)
+	}
+
+	if err := provider.AddProperty(cluster, "Status", "healthy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := provider.GetProperty(id, "Status"); value != "healthy" {
+		t.Errorf("expected the property to be stored as an annotation, got %q", value)
+	}
+
+	clusters, err := provider.ListClusters("")
+	if err != nil || len(clusters) != 1 || clusters[0].Properties()["Status"] != "healthy" {
+		t.Errorf("expected one cluster with its properties, got %v (%v)", clusters, err)
+	}
+
+	if err := provider.DeleteCluster(id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := provider.GetCluster(id); err == nil {
+		t.Errorf("expected the hosted cluster to be deleted")
+	}
+	if nodePools, _ := provider.nodePools(id); len(nodePools) != 0 {
+		t.Errorf("expected the NodePools to be deleted, got %d", len(nodePools))
+	}
+}
+
+func TestLaunchClusterRollback(t *testing.T) {
+	provider := newTestProvider(t)
+	nodePoolError := errors.New("admission webhook denied the request")
+	provider.dynamic.(*dynamicfake.FakeDynamicClient).PrependReactor("create", "nodepools", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nodePoolError
+	})
+
+	id, err := provider.LaunchCluster("osde2e-abc")
+	if !errors.Is(err, nodePoolError) || id != "" {
+		t.Fatalf("expected the NodePool error and no cluster, got %q (%v)", id, err)
+	}
+	if _, err := provider.getHostedCluster("osde2e-abc"); err == nil {
+		t.Errorf("expected the HostedCluster to be rolled back")
+	}
+	if _, err := provider.kube.CoreV1().Secrets(provider.namespace).Get(context.Background(), "osde2e-abc-pull-secret", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the pull secret to be rolled back")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	provider := newTestProvider(t)
+	provider.dynamic = contextClient{provider.dynamic}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := provider.Bind(ctx).GetCluster("osde2e-abc"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected calls to be cancelled with the bound context, got %v", err)
+	}
+}
+
+func TestLaunchClusterRollbackCancelled(t *testing.T) {
+	provider := newTestProvider(t)
+	fake := provider.dynamic.(*dynamicfake.FakeDynamicClient)
+	provider.dynamic = contextClient{fake}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The run is interrupted while the NodePool is being created
+	fake.PrependReactor("create", "nodepools", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cancel()
+		return true, nil, context.Canceled
+	})
+
+	if id, err := provider.Bind(ctx).LaunchCluster("osde2e-abc"); id != "" {
+		t.Fatalf("expected the hosted cluster to be rolled back, got %q (%v)", id, err)
+	}
+	if _, err := provider.getHostedCluster("osde2e-abc"); err == nil {
+		t.Errorf("expected the HostedCluster to be rolled back")
+	}
+}
+
+// contextClient fails the calls to the management cluster once their context is done, which the fake
+// client doesn't do.
+type contextClient struct {
+	dynamic.Interface
+}
+
+func (c contextClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return contextResource{c.Interface.Resource(resource)}
+}
+
+type contextResource struct {
+	dynamic.NamespaceableResourceInterface
+}
+
+func (r contextResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return contextNamespacedResource{r.NamespaceableResourceInterface.Namespace(namespace)}
+}
+
+type contextNamespacedResource struct {
+	dynamic.ResourceInterface
+}
+
+func (r contextNamespacedResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Create(ctx, obj, options, subresources...)
+}
+
+func (r contextNamespacedResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Get(ctx, name, options, subresources...)
+}
+
+func (r contextNamespacedResource) List(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.List(ctx, options)
+}
+
+func (r contextNamespacedResource) Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.ResourceInterface.Delete(ctx, name, options, subresources...)
+}
+
+func TestClusterState(t *testing.T) {
+	tests := []struct {
+		name          string
+		conditions    []any
+		nodePoolReady string
+		want          spi.ClusterState
+	}{
+		{"installing", nil, "False", spi.ClusterStateInstalling},
+		{"control plane only", []any{condition("Available", "True")}, "False", spi.ClusterStateInstalling},
+		{"ready", []any{condition("Available", "True")}, "True", spi.ClusterStateReady},
+		{"invalid release", []any{condition("ValidReleaseImage", "False")}, "False", spi.ClusterStateError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostedCluster := &unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"conditions": tt.conditions}}}
+			nodePool := unstructured.Unstructured{Object: map[string]any{"status": map[string]any{"conditions": []any{condition("Ready", tt.nodePoolReady)}}}}
+			if got := clusterState(hostedCluster, []unstructured.Unstructured{nodePool}); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	provider := newTestProvider(t)
+	if _, err := provider.LaunchCluster("osde2e-abc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := provider.Upgrade("osde2e-abc", "4.17.0", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := releaseRepository + ":4.17.0-multi"
+	hostedCluster, _ := provider.getHostedCluster("osde2e-abc")
+	if image, _, _ := unstructured.NestedString(hostedCluster.Object, "spec", "release", "image"); image != want {
+		t.Errorf("expected the HostedCluster to be upgraded to %s, got %s", want, image)
+	}
+	nodePools, _ := provider.nodePools("osde2e-abc")
+	if image, _, _ := unstructured.NestedString(nodePools[0].Object, "spec", "release", "image"); image != want {
+		t.Errorf("expected the NodePool to be upgraded to %s, got %s", want, image)
+	}
+
+	if policyID, err := provider.GetUpgradePolicyID("osde2e-abc"); policyID != "" || err != nil {
+		t.Errorf("expected no upgrade policy, got %q (%v)", policyID, err)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	provider := spi.Complete(&HyperShiftProvider{})
+	if _, ok := spi.As[spi.Upgrader](provider); !ok {
+		t.Errorf("expected HyperShift to upgrade clusters")
+	}
+	if _, ok := spi.As[spi.ExpiryManager](provider); ok {
+		t.Errorf("expected hosted clusters to not expire")
+	}
+}
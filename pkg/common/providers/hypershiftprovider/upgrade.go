@@ -0,0 +1,68 @@
+package hypershiftprovider
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Upgrade starts upgrading the control plane and NodePools of the cluster to the given version.
+//
+// HyperShift has no upgrade policies, the release image is changed right away, so t is ignored.
+func (h *HyperShiftProvider) Upgrade(clusterID string, version string, t time.Time) error {
+	releaseImage, err := h.releaseImageFor(version)
+	if err != nil {
+		return err
+	}
+	if time.Until(t) > 0 {
+		log.Printf("HyperShift upgrades can't be scheduled, upgrading %s to %s now", clusterID, version)
+	}
+
+	hostedCluster, err := h.getHostedCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if err := h.setReleaseImage(hostedClusterResource, hostedCluster, releaseImage); err != nil {
+		return fmt.Errorf("error upgrading HostedCluster: %w", err)
+	}
+
+	nodePools, err := h.nodePools(clusterID)
+	if err != nil {
+		return err
+	}
+	for i := range nodePools {
+		if err := h.setReleaseImage(nodePoolResource, &nodePools[i], releaseImage); err != nil {
+			return fmt.Errorf("error upgrading NodePool %s: %w", nodePools[i].GetName(), err)
+		}
+	}
+
+	log.Printf("Requested upgrade of hosted cluster %s to %s", clusterID, releaseImage)
+	return nil
+}
+
+// GetUpgradePolicyID returns no policy, HyperShift has no upgrade policies.
+func (h *HyperShiftProvider) GetUpgradePolicyID(clusterID string) (string, error) {
+	return "", nil
+}
+
+// UpdateSchedule is not supported, HyperShift upgrades can't be scheduled.
+func (h *HyperShiftProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	return spi.NewNotSupportedError(providerType, "UpdateSchedule")
+}
+
+// setReleaseImage updates the release image of a HostedCluster or NodePool.
+func (h *HyperShiftProvider) setReleaseImage(resource schema.GroupVersionResource, obj *unstructured.Unstructured, releaseImage string) error {
+	ctx, cancel := h.requestContext()
+	defer cancel()
+
+	if err := unstructured.SetNestedField(obj.Object, releaseImage, "spec", "release", "image"); err != nil {
+		return err
+	}
+	_, err := h.dynamic.Resource(resource).Namespace(h.namespace).Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,271 @@
+package localprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// controlPlaneTaint is the taint that keeps workloads off dedicated control plane nodes.
+const controlPlaneTaint = "node-role.kubernetes.io/control-plane"
+
+// IsValidClusterName checks that no local cluster with the given name exists yet.
+func (l *LocalProvider) IsValidClusterName(clusterName string) (bool, error) {
+	if l.tool == ToolExisting {
+		return true, nil
+	}
+
+	names, err := l.clusterNames(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range names {
+		if name == clusterName {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// LaunchCluster creates a kind or k3d cluster and returns its name as the cluster ID.
+//
+// Unlike OCM, kind and k3d create clusters synchronously, so the cluster is reachable once
+// this returns. For an existing cluster nothing is created and the name is used as the ID.
+func (l *LocalProvider) LaunchCluster(clusterName string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.createTimeout)
+	defer cancel()
+
+	switch l.tool {
+	case ToolKind:
+		args := []string{"create", "cluster", "--name", clusterName, "--wait", l.createTimeout.String()}
+		if l.nodeImage != "" {
+			args = append(args, "--image", l.nodeImage)
+		}
+		log.Printf("Creating kind cluster %s", clusterName)
+		if _, err := l.run(ctx, ToolKind, args...); err != nil {
+			return "", fmt.Errorf("error creating kind cluster: %v", err)
+		}
+	case ToolK3d:
+		args := []string{"cluster", "create", clusterName, "--wait", "--timeout", l.createTimeout.String()}
+		if l.nodeImage != "" {
+			args = append(args, "--image", l.nodeImage)
+		}
+		log.Printf("Creating k3d cluster %s", clusterName)
+		if _, err := l.run(ctx, ToolK3d, args...); err != nil {
+			return "", fmt.Errorf("error creating k3d cluster: %v", err)
+		}
+	default:
+		if _, err := os.Stat(l.kubeconfigPath); err != nil {
+			return "", fmt.Errorf("kubeconfig for existing cluster is not readable: %v", err)
+		}
+		log.Printf("Using existing cluster from %s as %s", l.kubeconfigPath, clusterName)
+	}
+
+	store.record(clusterName)
+	return clusterName, nil
+}
+
+// DeleteCluster deletes a kind or k3d cluster. Existing clusters are never deleted.
+func (l *LocalProvider) DeleteCluster(clusterID string) error {
+	ctx := context.Background()
+
+	switch l.tool {
+	case ToolKind:
+		if _, err := l.run(ctx, ToolKind, "delete", "cluster", "--name", clusterID); err != nil {
+			return fmt.Errorf("error deleting kind cluster: %v", err)
+		}
+	case ToolK3d:
+		if _, err := l.run(ctx, ToolK3d, "cluster", "delete", clusterID); err != nil {
+			return fmt.Errorf("error deleting k3d cluster: %v", err)
+		}
+	default:
+		log.Printf("Not deleting existing cluster %s", clusterID)
+	}
+
+	store.forget(clusterID)
+	return nil
+}
+
+// ListClusters lists local clusters. The query is ignored as there is no server-side search.
+func (l *LocalProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+	var names []string
+	if l.tool == ToolExisting {
+		names = store.ids()
+	} else {
+		var err error
+		if names, err = l.clusterNames(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	clusters := make([]*spi.Cluster, 0, len(names))
+	for _, name := range names {
+		cluster, err := l.GetCluster(name)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// GetCluster builds the cluster from the live API server.
+//
+// The cluster is reported as installing until the API server answers and every node is ready.
+func (l *LocalProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+	kubeconfig, err := l.ClusterKubeconfig(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get local cluster '%s': %v", clusterID, err)
+	}
+
+	builder := spi.NewClusterBuilder().
+		ID(clusterID).
+		Name(clusterID).
+		CloudProvider(providerType).
+		Product(providerType).
+		Region(environment).
+		Flavour(l.tool).
+		CreationTimestamp(store.record(clusterID).created).
+		Properties(store.properties(clusterID)).
+		State(spi.ClusterStateInstalling)
+
+	client, err := l.newClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create client for local cluster '%s': %v", clusterID, err)
+	}
+
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		log.Printf("Local cluster %s API server is not reachable yet: %v", clusterID, err)
+		return builder.Build(), nil
+	}
+	builder.Version(strings.TrimPrefix(info.GitVersion, "v"))
+
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Unable to list nodes for local cluster %s: %v", clusterID, err)
+		return builder.Build(), nil
+	}
+
+	ready := len(nodes.Items) > 0
+	computeNodes := 0
+	for _, node := range nodes.Items {
+		if !nodeReady(node) {
+			ready = false
+		}
+		if schedulable(node) {
+			computeNodes++
+		}
+	}
+	builder.NumComputeNodes(computeNodes)
+
+	if ready {
+		builder.State(spi.ClusterStateReady)
+	}
+	return builder.Build(), nil
+}
+
+// GetClusterRegion returns the local region.
+func (l *LocalProvider) GetClusterRegion(clusterID string) (string, error) {
+	return environment, nil
+}
+
+// ClusterKubeconfig returns the admin kubeconfig for the cluster.
+func (l *LocalProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	ctx := context.Background()
+
+	switch l.tool {
+	case ToolKind:
+		return l.run(ctx, ToolKind, "get", "kubeconfig", "--name", clusterID)
+	case ToolK3d:
+		return l.run(ctx, ToolK3d, "kubeconfig", "get", clusterID)
+	default:
+		return os.ReadFile(l.kubeconfigPath)
+	}
+}
+
+// AddProperty adds a property to the cluster. Properties only live as long as the osde2e process.
+func (l *LocalProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+	store.setProperty(cluster.ID(), tag, value)
+	return nil
+}
+
+// GetProperty gets a property from the cluster.
+func (l *LocalProvider) GetProperty(clusterID string, property string) (string, error) {
+	return store.properties(clusterID)[property], nil
+}
+
+// Logs returns no logs, as local clusters have no provider-side install logs.
+func (l *LocalProvider) Logs(clusterID string) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
+}
+
+// clusterNames lists the clusters known to kind or k3d.
+func (l *LocalProvider) clusterNames(ctx context.Context) ([]string, error) {
+	var names []string
+
+	switch l.tool {
+	case ToolKind:
+		out, err := l.run(ctx, ToolKind, "get", "clusters")
+		if err != nil {
+			return nil, fmt.Errorf("error listing kind clusters: %v", err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			line = strings.TrimSpace(line)
+			// kind prints "No kind clusters found." when there are none.
+			if line != "" && !strings.Contains(line, " ") {
+				names = append(names, line)
+			}
+		}
+	case ToolK3d:
+		out, err := l.run(ctx, ToolK3d, "cluster", "list", "--output", "json")
+		if err != nil {
+			return nil, fmt.Errorf("error listing k3d clusters: %v", err)
+		}
+		var clusters []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(out, &clusters); err != nil {
+			return nil, fmt.Errorf("error parsing k3d cluster list: %v", err)
+		}
+		for _, cluster := range clusters {
+			names = append(names, cluster.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// nodeReady returns true if the node reports the Ready condition.
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// schedulable returns true if workloads can be scheduled on the node. Single node kind and k3d
+// clusters run workloads on their control plane, so roles alone can't tell compute nodes apart.
+func schedulable(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == controlPlaneTaint && taint.Effect == corev1.TaintEffectNoSchedule {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package localprovider
+
+import (
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+)
+
+const (
+	// Tool is the tool used to manage the local cluster: kind, k3d or existing.
+	// Env: LOCAL_CLUSTER_TOOL
+	Tool = "local.tool"
+
+	// Kubeconfig is the path of the kubeconfig for an existing local cluster.
+	// Only used when the tool is "existing". Falls back to TEST_KUBECONFIG, KUBECONFIG and ~/.kube/config.
+	// Env: LOCAL_KUBECONFIG
+	Kubeconfig = "local.kubeconfig"
+
+	// NodeImage is the node image used when creating kind or k3d clusters.
+	// Env: LOCAL_NODE_IMAGE
+	NodeImage = "local.nodeImage"
+
+	// Version is the Kubernetes version reported by Versions for kind and k3d clusters.
+	// Env: LOCAL_VERSION
+	Version = "local.version"
+
+	// CreateTimeout is how long to wait for kind or k3d to create a cluster.
+	// Env: LOCAL_CREATE_TIMEOUT
+	CreateTimeout = "local.createTimeout"
+)
+
+const (
+	// ToolKind creates and deletes clusters with kind.
+	ToolKind = "kind"
+
+	// ToolK3d creates and deletes clusters with k3d.
+	ToolK3d = "k3d"
+
+	// ToolExisting uses an already running API server and never creates or deletes it.
+	ToolExisting = "existing"
+)
+
+func init() {
+	// ----- Local -----
+	viper.SetDefault(Tool, ToolExisting)
+	_ = viper.BindEnv(Tool, "LOCAL_CLUSTER_TOOL")
+
+	_ = viper.BindEnv(Kubeconfig, "LOCAL_KUBECONFIG")
+
+	_ = viper.BindEnv(NodeImage, "LOCAL_NODE_IMAGE")
+
+	viper.SetDefault(Version, "1.31.0")
+	_ = viper.BindEnv(Version, "LOCAL_VERSION")
+
+	viper.SetDefault(CreateTimeout, "10m")
+	_ = viper.BindEnv(CreateTimeout, "LOCAL_CREATE_TIMEOUT")
+}
@@ -0,0 +1,216 @@
+// Package localprovider allows osde2e to run against a local kind, k3d or existing kubeconfig-backed cluster.
+package localprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// providerType is the name the local provider is registered under.
+	providerType = "local"
+
+	// environment is the only environment a local cluster runs in.
+	environment = "local"
+)
+
+// commandRunner runs an external command and returns its standard output.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// clientBuilder builds a Kubernetes client from raw kubeconfig contents.
+type clientBuilder func(kubeconfig []byte) (kubernetes.Interface, error)
+
+// LocalProvider manages clusters running on the local machine.
+type LocalProvider struct {
+	tool           string
+	kubeconfigPath string
+	nodeImage      string
+	version        string
+	createTimeout  time.Duration
+
+	run       commandRunner
+	newClient clientBuilder
+}
+
+// clusterRecord tracks osde2e-owned metadata for a local cluster.
+type clusterRecord struct {
+	created    time.Time
+	properties map[string]string
+}
+
+// clusterStore holds cluster metadata for the lifetime of the process. Providers are
+// created on every spi.GetProvider call, so this cannot live on LocalProvider itself.
+type clusterStore struct {
+	mutex    sync.Mutex
+	clusters map[string]*clusterRecord
+}
+
+var store = &clusterStore{
+	clusters: map[string]*clusterRecord{},
+}
+
+// record returns the record for the given cluster, creating it if necessary.
+func (s *clusterStore) record(clusterID string) *clusterRecord {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec, ok := s.clusters[clusterID]
+	if !ok {
+		rec = &clusterRecord{
+			created:    time.Now(),
+			properties: map[string]string{},
+		}
+		s.clusters[clusterID] = rec
+	}
+	return rec
+}
+
+// setProperty sets a property for the given cluster.
+func (s *clusterStore) setProperty(clusterID, key, value string) {
+	rec := s.record(clusterID)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	rec.properties[key] = value
+}
+
+// properties returns a copy of the properties for the given cluster.
+func (s *clusterStore) properties(clusterID string) map[string]string {
+	rec := s.record(clusterID)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	properties := make(map[string]string, len(rec.properties))
+	for k, v := range rec.properties {
+		properties[k] = v
+	}
+	return properties
+}
+
+// ids returns the sorted IDs of all known clusters.
+func (s *clusterStore) ids() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ids := make([]string, 0, len(s.clusters))
+	for id := range s.clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// forget removes the given cluster from the store.
+func (s *clusterStore) forget(clusterID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.clusters, clusterID)
+}
+
+// New creates a new LocalProvider from the config.
+func New() (*LocalProvider, error) {
+	tool := viper.GetString(Tool)
+	switch tool {
+	case ToolKind, ToolK3d, ToolExisting:
+	default:
+		return nil, fmt.Errorf("unsupported local cluster tool %q, must be one of %s, %s or %s", tool, ToolKind, ToolK3d, ToolExisting)
+	}
+
+	createTimeout, err := time.ParseDuration(viper.GetString(CreateTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing local create timeout: %v", err)
+	}
+
+	if tool != ToolExisting {
+		if _, err := exec.LookPath(tool); err != nil {
+			return nil, fmt.Errorf("%s binary not found in $PATH: %v", tool, err)
+		}
+	}
+
+	return &LocalProvider{
+		tool:           tool,
+		kubeconfigPath: kubeconfigPath(),
+		nodeImage:      viper.GetString(NodeImage),
+		version:        viper.GetString(Version),
+		createTimeout:  createTimeout,
+		run:            runCommand,
+		newClient:      newKubeClient,
+	}, nil
+}
+
+// kubeconfigPath resolves the kubeconfig used for an existing cluster.
+func kubeconfigPath() string {
+	for _, path := range []string{
+		viper.GetString(Kubeconfig),
+		viper.GetString(config.Kubeconfig.Path),
+		os.Getenv(clientcmd.RecommendedConfigPathEnvVar),
+	} {
+		if path != "" {
+			return path
+		}
+	}
+	return clientcmd.RecommendedHomeFile
+}
+
+// runCommand runs an external command and returns its standard output.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// newKubeClient builds a Kubernetes clientset from kubeconfig contents.
+func newKubeClient(kubeconfig []byte) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating rest config: %v", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Type returns the provisioner type: local
+func (l *LocalProvider) Type() string {
+	return providerType
+}
+
+// Environment returns the local environment.
+func (l *LocalProvider) Environment() string {
+	return environment
+}
+
+// Metrics is a no-op for local clusters.
+func (l *LocalProvider) Metrics(clusterID string) (bool, error) {
+	return true, nil
+}
+
+// UpgradeSource returns the release controller, as local clusters are never upgraded through Cincinnati.
+func (l *LocalProvider) UpgradeSource() spi.UpgradeSource {
+	return spi.ReleaseControllerSource
+}
+
+// CincinnatiChannel returns the stable channel. It is unused for local clusters.
+func (l *LocalProvider) CincinnatiChannel() spi.CincinnatiChannel {
+	return spi.CincinnatiStableChannel
+}
+
+// CheckQuota always succeeds as local clusters have no quota.
+func (l *LocalProvider) CheckQuota(sku string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,231 @@
+package localprovider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func node(name string, ready bool, taints ...corev1.Taint) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newTestProvider(tool string, commands *[]string, output string, nodes ...*corev1.Node) *LocalProvider {
+	return &LocalProvider{
+		tool:           tool,
+		kubeconfigPath: "testdata/kubeconfig",
+		version:        "1.31.0",
+		createTimeout:  time.Minute,
+		run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			*commands = append(*commands, name+" "+strings.Join(args, " "))
+			return []byte(output), nil
+		},
+		newClient: func(kubeconfig []byte) (kubernetes.Interface, error) {
+			client := fake.NewSimpleClientset()
+			for _, n := range nodes {
+				_, _ = client.CoreV1().Nodes().Create(context.Background(), n, metav1.CreateOptions{})
+			}
+			client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.2"}
+			return client, nil
+		},
+	}
+}
+
+func TestLaunchAndDeleteCluster(t *testing.T) {
+	tests := []struct {
+		tool     string
+		image    string
+		expected []string
+	}{
+		{
+			tool:  ToolKind,
+			image: "kindest/node:v1.31.2",
+			expected: []string{
+				"kind create cluster --name test --wait 1m0s --image kindest/node:v1.31.2",
+				"kind delete cluster --name test",
+			},
+		},
+		{
+			tool: ToolK3d,
+			expected: []string{
+				"k3d cluster create test --wait --timeout 1m0s",
+				"k3d cluster delete test",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			var commands []string
+			provider := newTestProvider(tt.tool, &commands, "")
+			provider.nodeImage = tt.image
+
+			id, err := provider.LaunchCluster("test")
+			if err != nil {
+				t.Fatalf("unexpected error launching cluster: %v", err)
+			}
+			if id != "test" {
+				t.Errorf("expected cluster ID test, got %s", id)
+			}
+			if err := provider.DeleteCluster(id); err != nil {
+				t.Fatalf("unexpected error deleting cluster: %v", err)
+			}
+
+			if strings.Join(commands, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected commands:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(commands, "\n"))
+			}
+		})
+	}
+}
+
+func TestGetCluster(t *testing.T) {
+	controlPlane := corev1.Taint{Key: controlPlaneTaint, Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name          string
+		nodes         []*corev1.Node
+		expectedState spi.ClusterState
+		expectedNodes int
+	}{
+		{
+			name:          "single node cluster is ready",
+			nodes:         []*corev1.Node{node("control-plane", true)},
+			expectedState: spi.ClusterStateReady,
+			expectedNodes: 1,
+		},
+		{
+			name:          "tainted control plane is not a compute node",
+			nodes:         []*corev1.Node{node("control-plane", true, controlPlane), node("worker", true)},
+			expectedState: spi.ClusterStateReady,
+			expectedNodes: 1,
+		},
+		{
+			name:          "not ready node keeps cluster installing",
+			nodes:         []*corev1.Node{node("control-plane", true), node("worker", false)},
+			expectedState: spi.ClusterStateInstalling,
+			expectedNodes: 2,
+		},
+		{
+			name:          "no nodes keeps cluster installing",
+			expectedState: spi.ClusterStateInstalling,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			provider := newTestProvider(ToolKind, &commands, "apiVersion: v1", tt.nodes...)
+
+			cluster, err := provider.GetCluster("test")
+			if err != nil {
+				t.Fatalf("unexpected error getting cluster: %v", err)
+			}
+			if cluster.State() != tt.expectedState {
+				t.Errorf("expected state %s, got %s", tt.expectedState, cluster.State())
+			}
+			if cluster.NumComputeNodes() != tt.expectedNodes {
+				t.Errorf("expected %d compute nodes, got %d", tt.expectedNodes, cluster.NumComputeNodes())
+			}
+			if cluster.Version() != "1.31.2" {
+				t.Errorf("expected version 1.31.2, got %s", cluster.Version())
+			}
+		})
+	}
+}
+
+func TestProperties(t *testing.T) {
+	var commands []string
+	provider := newTestProvider(ToolKind, &commands, "apiVersion: v1")
+
+	cluster, err := provider.GetCluster("props")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster: %v", err)
+	}
+	if err := provider.AddProperty(cluster, "Status", "healthy"); err != nil {
+		t.Fatalf("unexpected error adding property: %v", err)
+	}
+
+	value, err := provider.GetProperty("props", "Status")
+	if err != nil || value != "healthy" {
+		t.Errorf("expected property healthy, got %q (%v)", value, err)
+	}
+
+	cluster, _ = provider.GetCluster("props")
+	if cluster.Properties()["Status"] != "healthy" {
+		t.Errorf("expected property to be set on cluster, got %v", cluster.Properties())
+	}
+}
+
+func TestClusterNames(t *testing.T) {
+	var commands []string
+	provider := newTestProvider(ToolKind, &commands, "beta\nalpha\n")
+	names, err := provider.clusterNames(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing kind clusters: %v", err)
+	}
+	if strings.Join(names, ",") != "alpha,beta" {
+		t.Errorf("expected alpha,beta got %v", names)
+	}
+
+	provider = newTestProvider(ToolK3d, &commands, `[{"name":"one"},{"name":"two"}]`)
+	if valid, err := provider.IsValidClusterName("one"); err != nil || valid {
+		t.Errorf("expected existing k3d cluster name to be invalid, got %v (%v)", valid, err)
+	}
+	if valid, err := provider.IsValidClusterName("three"); err != nil || !valid {
+		t.Errorf("expected new k3d cluster name to be valid, got %v (%v)", valid, err)
+	}
+}
+
+func TestNotSupported(t *testing.T) {
+	var commands []string
+	provider := spi.Complete(newTestProvider(ToolExisting, &commands, ""))
+
+	if _, ok := spi.As[spi.Upgrader](provider); ok {
+		t.Errorf("expected local provider to lack the upgrades capability")
+	}
+	if _, ok := spi.As[spi.PropertyStore](provider); !ok {
+		t.Errorf("expected local provider to store properties")
+	}
+
+	errs := map[string]error{
+		"Upgrade":          provider.Upgrade("test", "1.32.0", time.Now()),
+		"AddClusterProxy":  provider.AddClusterProxy("test", "", "", ""),
+		"AddGateAgreement": provider.AddGateAgreement("test", "gate"),
+	}
+	_, errs["GetVersionGateID"] = provider.GetVersionGateID("1.32", "")
+	_, errs["InstallAddons"] = provider.InstallAddons("test", []spi.AddOnID{"addon"}, nil)
+
+	for operation, err := range errs {
+		if !errors.Is(err, spi.ErrNotSupported) {
+			t.Errorf("expected %s to return ErrNotSupported, got %v", operation, err)
+		}
+		var notSupported *spi.NotSupportedError
+		if !errors.As(err, &notSupported) || notSupported.Operation != operation {
+			t.Errorf("expected NotSupportedError for %s, got %v", operation, err)
+		}
+	}
+
+	if _, err := provider.InstallAddons("test", nil, nil); err != nil {
+		t.Errorf("expected no error when no addons are requested, got %v", err)
+	}
+}
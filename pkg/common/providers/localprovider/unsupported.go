@@ -0,0 +1,17 @@
+package localprovider
+
+import (
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Local clusters only have the Lifecycle and PropertyStore capabilities. The provider is registered
+// with spi.RegisterLifecycleProvider, so the other capabilities return spi.NotSupportedError.
+
+// DetermineMachineType is not supported, local clusters have no cloud machine types.
+func (l *LocalProvider) DetermineMachineType(cloudProvider string) (string, error) {
+	return "", l.notSupported("DetermineMachineType")
+}
+
+func (l *LocalProvider) notSupported(operation string) error {
+	return spi.NewNotSupportedError(providerType, operation)
+}
@@ -0,0 +1,59 @@
+package localprovider
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Versions returns the single Kubernetes version the local provider offers.
+//
+// For an existing cluster this is the version of the running API server. For kind and k3d
+// it is the configured version, which should match the node image in use.
+func (l *LocalProvider) Versions() (*spi.VersionList, error) {
+	rawVersion := l.version
+	if l.tool == ToolExisting {
+		if serverVersion, err := l.serverVersion(); err != nil {
+			log.Printf("Unable to get version of existing cluster, using %s: %v", rawVersion, err)
+		} else {
+			rawVersion = serverVersion
+		}
+	}
+
+	version, err := semver.NewVersion(rawVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse local version '%s': %v", rawVersion, err)
+	}
+
+	return spi.NewVersionListBuilder().
+		AvailableVersions([]*spi.Version{
+			spi.NewVersionBuilder().
+				Version(version).
+				Default(true).
+				Build(),
+		}).
+		Build(), nil
+}
+
+// serverVersion returns the version of the API server behind the existing kubeconfig.
+func (l *LocalProvider) serverVersion() (string, error) {
+	kubeconfig, err := os.ReadFile(l.kubeconfigPath)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := l.newClient(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(info.GitVersion, "v"), nil
+}
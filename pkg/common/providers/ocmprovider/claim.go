@@ -0,0 +1,224 @@
+package ocmprovider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+// claimSettleDelay is how long a claim is left to settle before it is read back. OCM has no
+// compare-and-swap for cluster properties, so concurrent claims of the same cluster all succeed
+// and the last write wins. Every claimer reads back the claim token after the competing writes
+// have landed, and only the one whose token was kept owns the cluster.
+var claimSettleDelay = 5 * time.Second
+
+// ErrClaimLost is returned when a competing claim replaced the claim of this run, or it was released.
+var ErrClaimLost = errors.New("lost claim")
+
+// ClaimClusterFromReserve claims a reserved cluster of the version, cloud provider and product for
+// this test run and returns its ID, or an empty string if none could be claimed.
+//
+// Ready clusters are preferred over installing ones. A claim writes a claim token unique to this
+// run together with a lease expiry, and is only kept if the token reads back after the settle
+// delay and again before the cluster is prepared. This is best-effort: without compare-and-swap
+// a competing claim delayed past both reads still wins, so two jobs rarely, but possibly, end up
+// with the same cluster. The lease must be renewed with RenewClaim while the cluster is in use.
+func (o *OCMProvider) ClaimClusterFromReserve(originalVersion string, cloudProvider string, product string) string {
+	listResponse, err := o.QueryReserve(originalVersion, cloudProvider, product)
+	if err != nil {
+		log.Printf("Error querying the cluster reserve: %v", err)
+		return ""
+	}
+
+	token := newClaimToken()
+	lease := time.Duration(viper.GetInt(config.Cluster.ClaimLeaseInMinutes)) * time.Minute
+	for _, candidate := range reserveCandidates(listResponse.Items().Slice()) {
+		claimed, err := o.claimCluster(candidate.ID(), token, lease)
+		if err != nil {
+			log.Printf("Error claiming reserved cluster %s: %v", candidate.ID(), err)
+			continue
+		}
+		if !claimed {
+			log.Printf("Reserved cluster %s was claimed by another job", candidate.ID())
+			continue
+		}
+
+		// Verify the claim once more before touching the cluster, in case a competing claim landed
+		// after the settle delay
+		if holder, err := o.claimHolder(candidate.ID()); err != nil || holder != token {
+			log.Printf("Lost claim on reserved cluster %s to %q (%v)", candidate.ID(), holder, err)
+			continue
+		}
+
+		if err := o.prepareClaimedCluster(candidate, token); err != nil {
+			log.Printf("Error preparing claimed cluster %s: %v", candidate.ID(), err)
+			if errors.Is(err, ErrClaimLost) {
+				continue
+			}
+			if err := o.releaseClaim(candidate.ID(), token, clusterproperties.Used); err != nil {
+				log.Printf("Error releasing claim on cluster %s: %v", candidate.ID(), err)
+			}
+			continue
+		}
+
+		log.Printf("Claimed reserved cluster %s with token %s", candidate.ID(), token)
+		viper.Set(config.Cluster.ClaimedFromReserve, true)
+		viper.Set(config.Cluster.ClaimToken, token)
+		if candidate.AWS().STS().RoleARN() != "" {
+			viper.Set("rosa.STS", true)
+		}
+		return candidate.ID()
+	}
+
+	// continue the test with a new cluster
+	log.Println("No reserved cluster available. Creating a new cluster instead")
+	return ""
+}
+
+// reserveCandidates orders the reserved clusters ready first, then installing. Each group is
+// shuffled so that concurrent jobs spread their claims instead of all racing for the same cluster.
+func reserveCandidates(clusters []*v1.Cluster) []*v1.Cluster {
+	var ready, installing []*v1.Cluster
+	for _, c := range clusters {
+		switch c.State() {
+		case v1.ClusterStateReady:
+			ready = append(ready, c)
+		case v1.ClusterStateInstalling:
+			installing = append(installing, c)
+		}
+	}
+	for _, group := range [][]*v1.Cluster{ready, installing} {
+		rand.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+	}
+	return append(ready, installing...)
+}
+
+// newClaimToken returns a claim token unique to this test run.
+func newClaimToken() string {
+	jobID := viper.GetString(config.JobID)
+	if jobID == "" {
+		jobID = "local"
+	}
+	return fmt.Sprintf("%s-%s", jobID, util.RandomStr(8))
+}
+
+// claimCluster claims the cluster for the token if it is still reserved, and reports whether the
+// claim was kept once concurrent claims settled.
+func (o *OCMProvider) claimCluster(clusterID, token string, lease time.Duration) (bool, error) {
+	cluster, err := o.GetOCMCluster(clusterID)
+	if err != nil {
+		return false, err
+	}
+	properties := copyProperties(cluster.Properties())
+	if properties[clusterproperties.Availability] != clusterproperties.Reserved {
+		return false, nil
+	}
+
+	properties[clusterproperties.Availability] = clusterproperties.Claimed
+	properties[clusterproperties.ClaimToken] = token
+	properties[clusterproperties.ClaimLeaseExpiry] = time.Now().Add(lease).UTC().Format(time.RFC3339)
+	if _, err := o.setProperties(clusterID, properties); err != nil {
+		return false, err
+	}
+
+	time.Sleep(claimSettleDelay)
+
+	holder, err := o.claimHolder(clusterID)
+	if err != nil {
+		return false, err
+	}
+	return holder == token, nil
+}
+
+// claimHolder returns the token of the claim on the cluster, or an empty string if it isn't claimed.
+func (o *OCMProvider) claimHolder(clusterID string) (string, error) {
+	cluster, err := o.GetOCMCluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	if cluster.Properties()[clusterproperties.Availability] != clusterproperties.Claimed {
+		return "", nil
+	}
+	return cluster.Properties()[clusterproperties.ClaimToken], nil
+}
+
+// prepareClaimedCluster records which run is using a claimed cluster and extends its expiration so
+// the tests can finish. The cluster is left untouched if the claim of the token was lost.
+func (o *OCMProvider) prepareClaimedCluster(candidate *v1.Cluster, token string) error {
+	// Read the cluster again so the claim isn't overwritten by the properties of the candidate
+	cluster, err := o.GetOCMCluster(candidate.ID())
+	if err != nil {
+		return err
+	}
+	properties := copyProperties(cluster.Properties())
+	if holder := properties[clusterproperties.ClaimToken]; holder != token {
+		return fmt.Errorf("%w to %q", ErrClaimLost, holder)
+	}
+
+	// add useful properties to track clusters
+	properties[clusterproperties.AdHocTestImages] = config.GetAdHocTestImagesAsString()
+	properties[clusterproperties.AWSAccount] = viper.GetString(config.AWSAccountId)
+	if _, err := o.setProperties(candidate.ID(), properties); err != nil {
+		return fmt.Errorf("error adding tracking properties: %w", err)
+	}
+
+	if candidate.ExpirationTimestamp().Before(time.Now().Add(2 * time.Hour)) {
+		// extend expiration for sufficient time to allow for e2e tests to finish after claiming
+		if err := o.Expire(candidate.ID(), 2*time.Hour); err != nil {
+			return fmt.Errorf("error extending cluster: %w", err)
+		}
+	}
+	return nil
+}
+
+// RenewClaim extends the lease of the claim of the token on the cluster to lease from now. It fails
+// with ErrClaimLost if the cluster isn't claimed with the token anymore.
+func (o *OCMProvider) RenewClaim(clusterID, token string, lease time.Duration) error {
+	cluster, err := o.GetOCMCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	properties := copyProperties(cluster.Properties())
+	if properties[clusterproperties.Availability] != clusterproperties.Claimed || properties[clusterproperties.ClaimToken] != token {
+		return fmt.Errorf("%w to %q", ErrClaimLost, properties[clusterproperties.ClaimToken])
+	}
+
+	properties[clusterproperties.ClaimLeaseExpiry] = time.Now().Add(lease).UTC().Format(time.RFC3339)
+	_, err = o.setProperties(clusterID, properties)
+	return err
+}
+
+// releaseClaim ends the claim of the token on the cluster and sets its availability. Claims held
+// by another token are left untouched.
+func (o *OCMProvider) releaseClaim(clusterID, token, availability string) error {
+	cluster, err := o.GetOCMCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	properties := copyProperties(cluster.Properties())
+	if holder := properties[clusterproperties.ClaimToken]; holder != token {
+		return fmt.Errorf("cluster %s is claimed by %q, not %q", clusterID, holder, token)
+	}
+
+	properties[clusterproperties.Availability] = availability
+	properties[clusterproperties.ClaimToken] = ""
+	properties[clusterproperties.ClaimLeaseExpiry] = ""
+	_, err = o.setProperties(clusterID, properties)
+	return err
+}
+
+func copyProperties(properties map[string]string) map[string]string {
+	copied := make(map[string]string, len(properties)+3)
+	for key, value := range properties {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,313 @@
+package ocmprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+const clustersPath = "/api/clusters_mgmt/v1/clusters"
+
+// fakeReserve is a stateful fake of the OCM cluster API holding a reserve of clusters. Like OCM,
+// it replaces the properties of a cluster on every update, so the last concurrent write wins.
+type fakeReserve struct {
+	mutex      sync.Mutex
+	states     map[string]string
+	properties map[string]map[string]string
+
+	// afterUpdate is called after every property update, e.g. to let a competing job write its claim.
+	afterUpdate func(clusterID string)
+}
+
+func newFakeReserve() *fakeReserve {
+	return &fakeReserve{states: map[string]string{}, properties: map[string]map[string]string{}}
+}
+
+func (f *fakeReserve) add(clusterID, state string) {
+	f.states[clusterID] = state
+	f.properties[clusterID] = map[string]string{
+		clusterproperties.MadeByOSDe2e: "true",
+		clusterproperties.Availability: clusterproperties.Reserved,
+	}
+}
+
+func (f *fakeReserve) property(clusterID, property string) string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.properties[clusterID][property]
+}
+
+func (f *fakeReserve) setProperty(clusterID, property, value string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.properties[clusterID][property] = value
+}
+
+// clusterJSON must be called with the mutex held.
+func (f *fakeReserve) clusterJSON(clusterID string) string {
+	properties, _ := json.Marshal(f.properties[clusterID])
+	return fmt.Sprintf(`{"kind":"Cluster","id":"%s","state":"%s","expiration_timestamp":"%s","properties":%s}`,
+		clusterID, f.states[clusterID], time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339), properties)
+}
+
+func (f *fakeReserve) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		tokenHandler(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	f.mutex.Lock()
+	if r.URL.Path == clustersPath {
+		var items []string
+		for id, properties := range f.properties {
+			if properties[clusterproperties.Availability] == clusterproperties.Reserved {
+				items = append(items, f.clusterJSON(id))
+			}
+		}
+		f.mutex.Unlock()
+		fmt.Fprintf(w, `{"kind":"ClusterList","page":1,"size":%d,"total":%d,"items":[%s]}`, len(items), len(items), strings.Join(items, ","))
+		return
+	}
+
+	clusterID := strings.TrimPrefix(r.URL.Path, clustersPath+"/")
+	if _, ok := f.states[clusterID]; !ok {
+		f.mutex.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"kind":"Error","reason":"not found"}`)
+		return
+	}
+	if r.Method == http.MethodPatch {
+		var update struct {
+			Properties map[string]string `json:"properties"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		if update.Properties != nil {
+			f.properties[clusterID] = update.Properties
+		}
+	}
+	body := f.clusterJSON(clusterID)
+	afterUpdate := f.afterUpdate
+	f.mutex.Unlock()
+
+	fmt.Fprint(w, body)
+	if r.Method == http.MethodPatch && afterUpdate != nil {
+		afterUpdate(clusterID)
+	}
+}
+
+func setupClaimTest(t *testing.T) {
+	settleDelay := claimSettleDelay
+	claimSettleDelay = 50 * time.Millisecond
+	viper.Set(config.JobID, "1234")
+	t.Cleanup(func() {
+		claimSettleDelay = settleDelay
+		viper.Set(config.JobID, "")
+		viper.Set(config.Cluster.ClaimedFromReserve, false)
+		viper.Set(config.Cluster.ClaimToken, "")
+	})
+}
+
+func TestClaimClusterFromReserve(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("installing-cluster", "installing")
+	reserve.add("ready-cluster", "ready")
+	provider := newTestProvider(t, reserve)
+
+	clusterID := provider.ClaimClusterFromReserve("openshift-v4.16.1", "aws", "rosa")
+	if clusterID != "ready-cluster" {
+		t.Fatalf("expected the ready cluster to be claimed, got %q", clusterID)
+	}
+
+	token := viper.GetString(config.Cluster.ClaimToken)
+	if !strings.HasPrefix(token, "1234-") || !viper.GetBool(config.Cluster.ClaimedFromReserve) {
+		t.Errorf("expected the claim of job 1234 to be recorded, got %q", token)
+	}
+	if reserve.property(clusterID, clusterproperties.Availability) != clusterproperties.Claimed ||
+		reserve.property(clusterID, clusterproperties.ClaimToken) != token {
+		t.Errorf("expected the cluster to be claimed with %q, got %v", token, reserve.properties[clusterID])
+	}
+	lease, err := time.Parse(time.RFC3339, reserve.property(clusterID, clusterproperties.ClaimLeaseExpiry))
+	if err != nil || time.Until(lease) < 59*time.Minute {
+		t.Errorf("expected a 60 minute lease, got %v (%v)", lease, err)
+	}
+	if reserve.property("installing-cluster", clusterproperties.Availability) != clusterproperties.Reserved {
+		t.Errorf("expected the installing cluster to stay reserved")
+	}
+}
+
+func TestClaimClusterFromReserve_LosesRace(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+	reserve.add("cluster-b", "installing")
+	provider := newTestProvider(t, reserve)
+
+	// Another job claims cluster-a right after this one did, so its claim is the one kept
+	var once sync.Once
+	reserve.afterUpdate = func(clusterID string) {
+		if clusterID == "cluster-a" {
+			once.Do(func() { reserve.setProperty("cluster-a", clusterproperties.ClaimToken, "5678-other") })
+		}
+	}
+
+	if clusterID := provider.ClaimClusterFromReserve("openshift-v4.16.1", "aws", "rosa"); clusterID != "cluster-b" {
+		t.Fatalf("expected cluster-b to be claimed after losing cluster-a, got %q", clusterID)
+	}
+	if holder := reserve.property("cluster-a", clusterproperties.ClaimToken); holder != "5678-other" {
+		t.Errorf("expected the other job to keep its claim on cluster-a, got %q", holder)
+	}
+}
+
+func TestClaimClusterFromReserve_LosesRaceAfterSettling(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+
+	// A competing claim lands late, right after this job read back its claim once it settled
+	var reads atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reserve.ServeHTTP(w, r)
+		if r.Method == http.MethodGet && r.URL.Path == clustersPath+"/cluster-a" {
+			if reads.Add(1) == 2 {
+				reserve.setProperty("cluster-a", clusterproperties.ClaimToken, "5678-other")
+			}
+		}
+	})
+	provider := newTestProvider(t, handler)
+	viper.Set(config.AWSAccountId, "123456789012")
+	t.Cleanup(func() { viper.Set(config.AWSAccountId, "") })
+
+	if clusterID := provider.ClaimClusterFromReserve("openshift-v4.16.1", "aws", "rosa"); clusterID != "" {
+		t.Fatalf("expected no cluster to be claimed, got %q", clusterID)
+	}
+	if viper.GetBool(config.Cluster.ClaimedFromReserve) {
+		t.Errorf("expected the lost claim not to be recorded")
+	}
+	if holder := reserve.property("cluster-a", clusterproperties.ClaimToken); holder != "5678-other" {
+		t.Errorf("expected the other job to keep its claim, got %q", holder)
+	}
+	if account := reserve.property("cluster-a", clusterproperties.AWSAccount); account != "" {
+		t.Errorf("expected the cluster of the other job not to be prepared, got AWS account %q", account)
+	}
+}
+
+func TestClaimClusterFromReserve_AlreadyClaimed(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+	provider := newTestProvider(t, reserve)
+
+	// The cluster is listed as reserved, but claimed before this job reads it
+	var once sync.Once
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == clustersPath+"/cluster-a" {
+			once.Do(func() {
+				reserve.setProperty("cluster-a", clusterproperties.Availability, clusterproperties.Claimed)
+				reserve.setProperty("cluster-a", clusterproperties.ClaimToken, "5678-other")
+			})
+		}
+		reserve.ServeHTTP(w, r)
+	})
+	provider = newTestProvider(t, handler)
+
+	if clusterID := provider.ClaimClusterFromReserve("openshift-v4.16.1", "aws", "rosa"); clusterID != "" {
+		t.Fatalf("expected the claimed cluster to be skipped, got %q", clusterID)
+	}
+	if holder := reserve.property("cluster-a", clusterproperties.ClaimToken); holder != "5678-other" {
+		t.Errorf("expected the existing claim to be untouched, got %q", holder)
+	}
+}
+
+func TestClaimClusterFromReserve_Concurrent(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+	reserve.add("cluster-b", "ready")
+
+	const jobs = 5
+	providers := make([]*OCMProvider, jobs)
+	for i := range providers {
+		providers[i] = newTestProvider(t, reserve)
+	}
+
+	claimed := make([]string, jobs)
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed[i] = provider.ClaimClusterFromReserve("openshift-v4.16.1", "aws", "rosa")
+		}()
+	}
+	wg.Wait()
+
+	owners := map[string]int{}
+	for _, clusterID := range claimed {
+		if clusterID != "" {
+			owners[clusterID]++
+		}
+	}
+	if len(owners) != 2 || owners["cluster-a"] != 1 || owners["cluster-b"] != 1 {
+		t.Errorf("expected each cluster to be claimed by exactly one job, got %v", claimed)
+	}
+}
+
+func TestReleaseClaim(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+	reserve.properties["cluster-a"][clusterproperties.Availability] = clusterproperties.Claimed
+	reserve.properties["cluster-a"][clusterproperties.ClaimToken] = "1234-abc"
+	provider := newTestProvider(t, reserve)
+
+	if err := provider.releaseClaim("cluster-a", "5678-other", clusterproperties.Used); err == nil {
+		t.Errorf("expected releasing the claim of another job to fail")
+	}
+	if reserve.property("cluster-a", clusterproperties.Availability) != clusterproperties.Claimed {
+		t.Errorf("expected the claim of another job to be untouched")
+	}
+
+	if err := provider.releaseClaim("cluster-a", "1234-abc", clusterproperties.Used); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reserve.property("cluster-a", clusterproperties.Availability) != clusterproperties.Used || reserve.property("cluster-a", clusterproperties.ClaimToken) != "" {
+		t.Errorf("expected the cluster to be released as used, got %v", reserve.properties["cluster-a"])
+	}
+}
+
+func TestRenewClaim(t *testing.T) {
+	setupClaimTest(t)
+	reserve := newFakeReserve()
+	reserve.add("cluster-a", "ready")
+	reserve.properties["cluster-a"][clusterproperties.Availability] = clusterproperties.Claimed
+	reserve.properties["cluster-a"][clusterproperties.ClaimToken] = "1234-abc"
+	reserve.properties["cluster-a"][clusterproperties.ClaimLeaseExpiry] = time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	provider := newTestProvider(t, reserve)
+
+	if err := provider.RenewClaim("cluster-a", "1234-abc", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expiry, err := time.Parse(time.RFC3339, reserve.property("cluster-a", clusterproperties.ClaimLeaseExpiry))
+	if err != nil || time.Until(expiry) < 59*time.Minute {
+		t.Errorf("expected the lease to be renewed for an hour, got %v (%v)", expiry, err)
+	}
+	if reserve.property("cluster-a", clusterproperties.ClaimToken) != "1234-abc" {
+		t.Errorf("expected the claim to be kept, got %v", reserve.properties["cluster-a"])
+	}
+
+	if err := provider.RenewClaim("cluster-a", "5678-other", time.Hour); !errors.Is(err, ErrClaimLost) {
+		t.Errorf("expected renewing the claim of another job to fail, got %v", err)
+	}
+}
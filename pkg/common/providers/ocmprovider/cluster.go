@@ -74,7 +74,7 @@ func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
 		if enoughQuota, err := o.CheckQuota(skuID); err != nil {
 			log.Printf("Failed to check if enough quota is available: %v", err)
 		} else if !enoughQuota {
-			return "", fmt.Errorf("currently not enough quota exists to run this test")
+			return "", ErrNotEnoughQuota
 		}
 	} else {
 		// If no SKU specified, just continue on with no validation, but log it
@@ -334,63 +334,7 @@ func (o *OCMProvider) QueryReserve(originalVersion string, cloudProvider string,
 	return o.conn.ClustersMgmt().V1().Clusters().List().Search(query).Send()
 }
 
-func (o *OCMProvider) ClaimClusterFromReserve(originalVersion string, cloudProvider string, product string) string {
-	listResponse, err := o.QueryReserve(originalVersion, cloudProvider, product)
-	var candidateCluster *v1.Cluster
-	if err == nil && listResponse.Total() > 0 {
-		for _, c := range listResponse.Items().Slice() {
-			if c.State() == v1.ClusterStateReady {
-				candidateCluster = c
-				break
-			} else if c.State() == v1.ClusterStateInstalling {
-				candidateCluster = c
-			}
-		}
-	}
-	if candidateCluster == nil {
-		// continue the test with a new cluster
-		log.Println("No reserved cluster available. Creating a new cluster instead")
-		return ""
-	} else {
-		spiCandidateCluster, err := o.ocmToSPICluster(candidateCluster)
-		if err != nil {
-			log.Printf("Error converting reserved cluster to an SPI Cluster: %s", err.Error())
-			return ""
-		}
-
-		if candidateCluster.ExpirationTimestamp().Before(time.Now().Add(2 * time.Hour)) {
-			// extend expiration for sufficient time to allow for e2e tests to finish after claiming
-			err = o.Expire(spiCandidateCluster.ID(), 2*time.Hour)
-			if err != nil {
-				log.Printf("Error extending cluster %s: %s", spiCandidateCluster.ID(), err.Error())
-				return ""
-			}
-		}
-
-		log.Printf("Claiming reserved cluster: %s\n", spiCandidateCluster.ID())
-		err = o.AddProperty(spiCandidateCluster, clusterproperties.Availability, clusterproperties.Claimed)
-		if err != nil {
-			log.Printf("Error claiming cluster: %s", err.Error())
-			return ""
-		}
-		viper.Set(config.Cluster.ClaimedFromReserve, true)
-		if candidateCluster.AWS().STS().RoleARN() != "" {
-			viper.Set("rosa.STS", true)
-		}
-
-		// add useful properties to track clusters
-		err = o.AddProperty(spiCandidateCluster, clusterproperties.AdHocTestImages, config.GetAdHocTestImagesAsString())
-		if err != nil {
-			log.Printf("Error adding AdHocTestImages to cluster property: %s", err.Error())
-		}
-		err = o.AddProperty(spiCandidateCluster, clusterproperties.AWSAccount, viper.GetString(config.AWSAccountId))
-		if err != nil {
-			log.Printf("Error adding AWSAccount to cluster property: %s", err.Error())
-		}
-
-		return candidateCluster.ID()
-	}
-}
+var _ spi.RegionChooser = &OCMProvider{}
 
 // DetermineRegion will return the region provided by configs. This mainly wraps the random functionality for use
 // by the ROSA provider.
@@ -1000,6 +944,10 @@ func (o *OCMProvider) ocmToSPICluster(ocmCluster *v1.Cluster) (*spi.Cluster, err
 		cluster.Properties(properties)
 	}
 
+	if status, ok := ocmCluster.GetStatus(); ok && status.ProvisionErrorCode() != "" {
+		cluster.ProvisionError(fmt.Sprintf("%s: %s", status.ProvisionErrorCode(), status.ProvisionErrorMessage()))
+	}
+
 	if !viper.GetBool(config.Addons.SkipAddonList) {
 		var addonsResp *v1.AddOnInstallationsListResponse
 		err = retryer().Do(func() error {
@@ -1057,6 +1005,8 @@ func ocmStateToInternalState(state v1.ClusterState) spi.ClusterState {
 		return spi.ClusterStateReady
 	case v1.ClusterStateUninstalling:
 		return spi.ClusterStateUninstalling
+	case v1.ClusterStatePoweringDown:
+		return spi.ClusterStatePoweringDown
 	case v1.ClusterStateHibernating:
 		return spi.ClusterStateHibernating
 	case v1.ClusterStateResuming:
@@ -1185,8 +1135,6 @@ func (o *OCMProvider) Expire(clusterID string, duration time.Duration) error {
 
 // AddProperty adds a new property to the properties field of an existing cluster
 func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
-	var resp *v1.ClusterUpdateResponse
-
 	clusterproperties := cluster.Properties()
 
 	// Apparently, if cluster properties are empty in OCM, the clusterproperties are nil, In this case, we'll just make our own
@@ -1197,18 +1145,33 @@ func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string
 
 	clusterproperties[tag] = value
 
-	modifiedCluster, err := v1.NewCluster().Properties(clusterproperties).Build()
+	updated, err := o.setProperties(cluster.ID(), clusterproperties)
 	if err != nil {
-		return fmt.Errorf("error while building updated modified cluster object with new property: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully added property[%s] - %s \n", tag, updated.Properties()[tag])
+
+	// We need to update the cache post-update
+	return o.updateClusterCache(updated)
+}
+
+// setProperties replaces the properties of an existing cluster in a single update.
+func (o *OCMProvider) setProperties(clusterID string, properties map[string]string) (*v1.Cluster, error) {
+	var resp *v1.ClusterUpdateResponse
+
+	modifiedCluster, err := v1.NewCluster().Properties(properties).Build()
+	if err != nil {
+		return nil, fmt.Errorf("error while building updated modified cluster object with new property: %v", err)
 	}
 
 	err = retryer().Do(func() error {
 		var err error
-		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).Update().
+		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Update().
 			Body(modifiedCluster).
 			Send()
 		if err != nil {
-			err = fmt.Errorf("couldn't update cluster '%s': %v", cluster.ID(), err)
+			err = fmt.Errorf("couldn't update cluster '%s': %v", clusterID, err)
 			log.Printf("%v", err)
 			return err
 		}
@@ -1221,17 +1184,14 @@ func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if resp.Error() != nil {
-		return resp.Error()
+		return nil, resp.Error()
 	}
 
-	log.Printf("Successfully added property[%s] - %s \n", tag, resp.Body().Properties()[tag])
-
-	// We need to update the cache post-update
-	return o.updateClusterCache(resp.Body())
+	return resp.Body(), nil
 }
 
 // Get a specific cluster property
@@ -0,0 +1,61 @@
+package ocmprovider
+
+import (
+	"fmt"
+	"log"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+var _ spi.Hibernator = &OCMProvider{}
+
+// Hibernate starts hibernating a ready cluster.
+func (o *OCMProvider) Hibernate(clusterID string) error {
+	var resp *v1.ClusterHibernateResponse
+
+	err := retryer().Do(func() error {
+		var err error
+		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Hibernate().Send()
+		if err != nil {
+			return fmt.Errorf("couldn't hibernate cluster '%s': %v", clusterID, err)
+		}
+
+		if resp != nil && resp.Error() != nil {
+			return errResp(resp.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Hibernating cluster %s", clusterID)
+	return nil
+}
+
+// Resume starts resuming a hibernating cluster.
+func (o *OCMProvider) Resume(clusterID string) error {
+	var resp *v1.ClusterResumeResponse
+
+	err := retryer().Do(func() error {
+		var err error
+		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Resume().Send()
+		if err != nil {
+			return fmt.Errorf("couldn't resume cluster '%s': %v", clusterID, err)
+		}
+
+		if resp != nil && resp.Error() != nil {
+			return errResp(resp.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Resuming cluster %s", clusterID)
+	return nil
+}
@@ -0,0 +1,49 @@
+package ocmprovider
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+var _ spi.InflightChecker = &OCMProvider{}
+
+// InflightChecks returns the inflight checks OCM ran for the cluster so far.
+func (o *OCMProvider) InflightChecks(clusterID string) ([]spi.InflightCheck, error) {
+	var resp *v1.InflightChecksListResponse
+
+	err := retryer().Do(func() error {
+		var err error
+		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
+			InflightChecks().
+			List().
+			Send()
+		if err != nil {
+			return err
+		}
+
+		if resp != nil && resp.Error() != nil {
+			return errResp(resp.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't retrieve inflight checks for cluster '%s': %v", clusterID, err)
+	}
+
+	var checks []spi.InflightCheck
+	resp.Items().Each(func(c *v1.InflightCheck) bool {
+		checks = append(checks, spi.InflightCheck{
+			Name:      c.Name(),
+			State:     string(c.State()),
+			StartedAt: c.StartedAt(),
+			EndedAt:   c.EndedAt(),
+			Restarts:  c.Restarts(),
+			Details:   c.Details(),
+		})
+		return true
+	})
+	return checks, nil
+}
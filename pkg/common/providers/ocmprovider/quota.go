@@ -10,6 +10,9 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 )
 
+// ErrNotEnoughQuota is returned by LaunchCluster when the organization lacks the quota for the cluster.
+var ErrNotEnoughQuota = errors.New("currently not enough quota exists to run this test")
+
 // CheckQuota determines if enough quota is available to launch with cfg.
 func (o *OCMProvider) CheckQuota(skuRuleID string) (bool, error) {
 	// get flavour being deployed
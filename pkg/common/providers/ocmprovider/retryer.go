@@ -2,29 +2,24 @@ package ocmprovider
 
 import (
 	"log"
-	"sync"
 	"time"
 
 	"github.com/adamliesko/retry"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 )
 
-var (
-	ocmOnce    = sync.Once{}
-	ocmRetryer *retry.Retryer
-)
-
 // Retryer returns a retryer meant for OCM interactions.
+//
+// A retryer tracks the attempts of its current Do, so every call gets its own one to keep
+// concurrent OCM calls, e.g. racing reserve claims, from sharing that state.
 func retryer() *retry.Retryer {
-	ocmOnce.Do(func() {
-		ocmRetryer = retry.New(retry.SleepFn(func(attempts int) {
-			time.Sleep(time.Duration(1<<attempts) * time.Second)
-		}))
-		ocmRetryer.Tries = viper.GetInt(NumRetries)
-		ocmRetryer.AfterEachFailFn = func(err error) {
-			log.Printf("error during OCM attempt: %v", err)
-		}
-	})
+	ocmRetryer := retry.New(retry.SleepFn(func(attempts int) {
+		time.Sleep(time.Duration(1<<attempts) * time.Second)
+	}))
+	ocmRetryer.Tries = viper.GetInt(NumRetries)
+	ocmRetryer.AfterEachFailFn = func(err error) {
+		log.Printf("error during OCM attempt: %v", err)
+	}
 
 	return ocmRetryer
 }
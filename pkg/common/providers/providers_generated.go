@@ -3,6 +3,9 @@ package providers
 // DO NOT EDIT THIS FILE. It is generated by the Makefile.
 // This import list is necessary due to the statically linked nature of go
 import (
+	_ "github.com/openshift/osde2e/pkg/common/providers/aroprovider"
+	_ "github.com/openshift/osde2e/pkg/common/providers/hypershiftprovider"
+	_ "github.com/openshift/osde2e/pkg/common/providers/localprovider"
 	_ "github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
 	_ "github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
 )
@@ -13,6 +13,11 @@ func (m *ROSAProvider) ListClusters(query string) ([]*spi.Cluster, error) {
 	return m.ocmProvider.ListClusters(query)
 }
 
+// ListOwnedClusters will call ListOwnedClusters from the OCM provider.
+func (m *ROSAProvider) ListOwnedClusters(query string) ([]*spi.Cluster, error) {
+	return m.ocmProvider.ListOwnedClusters(query)
+}
+
 // GetCluster will call GetCluster from the OCM provider.
 func (m *ROSAProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
 	return m.ocmProvider.GetCluster(clusterID)
@@ -49,6 +54,21 @@ func (m *ROSAProvider) Logs(clusterID string) (map[string][]byte, error) {
 	return m.ocmProvider.Logs(clusterID)
 }
 
+// InflightChecks will call InflightChecks from the OCM provider.
+func (m *ROSAProvider) InflightChecks(clusterID string) ([]spi.InflightCheck, error) {
+	return m.ocmProvider.InflightChecks(clusterID)
+}
+
+// Hibernate will call Hibernate from the OCM provider.
+func (m *ROSAProvider) Hibernate(clusterID string) error {
+	return m.ocmProvider.Hibernate(clusterID)
+}
+
+// Resume will call Resume from the OCM provider.
+func (m *ROSAProvider) Resume(clusterID string) error {
+	return m.ocmProvider.Resume(clusterID)
+}
+
 // Environment will call Environment from the OCM provider.
 func (m *ROSAProvider) Environment() string {
 	return m.ocmProvider.Environment()
@@ -0,0 +1,249 @@
+// Package runmanifest describes an osde2e execution in a single versioned run.json file, so downstream
+// dashboards can read the outcome of a run without scraping logs and JUnit files.
+package runmanifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// SchemaVersion is increased whenever a field is removed or changes its meaning. Added fields don't change it.
+	SchemaVersion = 1
+
+	// FileName is the name of the manifest in the report directory.
+	FileName = "run.json"
+)
+
+// Manifest describes a single osde2e execution.
+type Manifest struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	JobName       string    `json:"jobName,omitempty"`
+	JobID         string    `json:"jobID,omitempty"`
+	JobURL        string    `json:"jobURL,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+	FinishedAt    time.Time `json:"finishedAt"`
+	ExitCode      int       `json:"exitCode"`
+	Passed        bool      `json:"passed"`
+
+	Cluster      Cluster       `json:"cluster"`
+	Versions     Versions      `json:"versions"`
+	Phases       []Phase       `json:"phases"`
+	Suites       []Suite       `json:"suites"`
+	HealthChecks []HealthCheck `json:"healthChecks"`
+	Provisions   []Provision   `json:"provisions"`
+	Artifacts    []Artifact    `json:"artifacts"`
+	Analysis     Analysis      `json:"analysis"`
+	Errors       []string      `json:"errors,omitempty"`
+}
+
+// Cluster is the cluster the tests ran against.
+type Cluster struct {
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Provider      string `json:"provider,omitempty"`
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Hypershift    bool   `json:"hypershift"`
+}
+
+// Versions are the install and upgrade versions and the selectors that chose them.
+type Versions struct {
+	Install         string `json:"install,omitempty"`
+	InstallSelector string `json:"installSelector,omitempty"`
+	Upgrade         string `json:"upgrade,omitempty"`
+	UpgradeSelector string `json:"upgradeSelector,omitempty"`
+}
+
+// Phase is a test phase, e.g. install or upgrade.
+type Phase struct {
+	Name            string    `json:"name"`
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Passed          bool      `json:"passed"`
+}
+
+// Suite is the result of a Ginkgo suite or a test suite image.
+type Suite struct {
+	Name            string   `json:"name"`
+	Phase           string   `json:"phase,omitempty"`
+	Image           string   `json:"image,omitempty"`
+	Passed          bool     `json:"passed"`
+	Attempts        int      `json:"attempts,omitempty"`
+	Tests           int      `json:"tests"`
+	PassedTests     int      `json:"passedTests"`
+	FailedTests     int      `json:"failedTests"`
+	SkippedTests    int      `json:"skippedTests"`
+	ErrorTests      int      `json:"errorTests"`
+	DurationSeconds float64  `json:"durationSeconds"`
+	Failures        []string `json:"failures,omitempty"`
+	Flaky           []string `json:"flaky,omitempty"`
+	Informing       []string `json:"informing,omitempty"`
+	// Analysis is the status of the log analysis of the suite, empty if it wasn't analyzed
+	Analysis  string     `json:"analysis,omitempty"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+}
+
+// HealthCheck is the outcome of waiting for the cluster to become healthy.
+type HealthCheck struct {
+	Phase           string    `json:"phase"`
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Passed          bool      `json:"passed"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Provision is an attempt to provision the cluster. Failed attempts carry the classification of
+// the failure, which decided whether provisioning was retried.
+type Provision struct {
+	Attempt         int       `json:"attempt"`
+	ClusterID       string    `json:"clusterID,omitempty"`
+	Region          string    `json:"region,omitempty"`
+	MachineType     string    `json:"machineType,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Passed          bool      `json:"passed"`
+	FailureCategory string    `json:"failureCategory,omitempty"`
+	Retryable       bool      `json:"retryable,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Artifact is an uploaded artifact.
+type Artifact struct {
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+	URL  string `json:"url,omitempty"`
+	Size int64  `json:"size"`
+}
+
+// Analysis is the status of the log analysis of the run.
+type Analysis struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Write writes the manifest to FileName in dir. The file is replaced atomically so readers never see
+// a partial manifest.
+func Write(dir string, manifest *Manifest) error {
+	manifest.SchemaVersion = SchemaVersion
+	// Lists are always written as arrays so readers don't have to handle null
+	if manifest.Phases == nil {
+		manifest.Phases = []Phase{}
+	}
+	if manifest.Suites == nil {
+		manifest.Suites = []Suite{}
+	}
+	if manifest.HealthChecks == nil {
+		manifest.HealthChecks = []HealthCheck{}
+	}
+	if manifest.Provisions == nil {
+		manifest.Provisions = []Provision{}
+	}
+	if manifest.Artifacts == nil {
+		manifest.Artifacts = []Artifact{}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+FileName+"-*")
+	if err != nil {
+		return fmt.Errorf("creating run manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing run manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing run manifest: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("writing run manifest: %w", err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, FileName))
+}
+
+// Read reads a manifest written by Write.
+func Read(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("decoding run manifest: %w", err)
+	}
+	if manifest.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("run manifest schema version %d is newer than the supported version %d", manifest.SchemaVersion, SchemaVersion)
+	}
+	return manifest, nil
+}
+
+// recorded holds the results that are recorded by the packages that produce them during a run
+var recorded struct {
+	sync.Mutex
+	suites       []Suite
+	healthChecks []HealthCheck
+	provisions   []Provision
+}
+
+// RecordSuite records the result of a suite for the manifest of the current run.
+func RecordSuite(suite Suite) {
+	recorded.Lock()
+	defer recorded.Unlock()
+	recorded.suites = append(recorded.suites, suite)
+}
+
+// RecordHealthCheck records the outcome of a health check for the manifest of the current run.
+func RecordHealthCheck(check HealthCheck) {
+	recorded.Lock()
+	defer recorded.Unlock()
+	recorded.healthChecks = append(recorded.healthChecks, check)
+}
+
+// RecordProvision records an attempt to provision the cluster for the manifest of the current run.
+func RecordProvision(provision Provision) {
+	recorded.Lock()
+	defer recorded.Unlock()
+	recorded.provisions = append(recorded.provisions, provision)
+}
+
+// Suites returns the suites recorded so far, in the order they were recorded.
+func Suites() []Suite {
+	recorded.Lock()
+	defer recorded.Unlock()
+	return append([]Suite(nil), recorded.suites...)
+}
+
+// HealthChecks returns the health checks recorded so far, in the order they were recorded.
+func HealthChecks() []HealthCheck {
+	recorded.Lock()
+	defer recorded.Unlock()
+	return append([]HealthCheck(nil), recorded.healthChecks...)
+}
+
+// Provisions returns the provisioning attempts recorded so far, in the order they were recorded.
+func Provisions() []Provision {
+	recorded.Lock()
+	defer recorded.Unlock()
+	return append([]Provision(nil), recorded.provisions...)
+}
+
+// Reset forgets all recorded results.
+func Reset() {
+	recorded.Lock()
+	defer recorded.Unlock()
+	recorded.suites = nil
+	recorded.healthChecks = nil
+	recorded.provisions = nil
+}
@@ -0,0 +1,89 @@
+package runmanifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &Manifest{
+		JobName:   "nightly",
+		StartedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ExitCode:  1,
+		Cluster:   Cluster{ID: "abc", Provider: "ocm", Version: "4.20.1"},
+		Versions:  Versions{Install: "4.20.1", InstallSelector: "latest version", Upgrade: "openshift-v4.21.0", UpgradeSelector: "latest y"},
+		Suites:    []Suite{{Name: "OSD e2e suite", Phase: "install", Tests: 3, FailedTests: 1, Failures: []string{"spec"}}},
+	}
+
+	if err := Write(dir, manifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read, err := Read(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if read.SchemaVersion != SchemaVersion || read.Cluster.ID != "abc" || read.Versions.UpgradeSelector != "latest y" {
+		t.Errorf("unexpected manifest: %+v", read)
+	}
+	if len(read.Suites) != 1 || read.Suites[0].Failures[0] != "spec" || !read.StartedAt.Equal(manifest.StartedAt) {
+		t.Errorf("unexpected suites: %+v", read.Suites)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"healthChecks": []`) || !strings.Contains(string(data), `"provisions": []`) {
+		t.Errorf("expected empty lists to be written as arrays:\n%s", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the manifest in the directory, got %v", entries)
+	}
+}
+
+func TestReadNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"schemaVersion": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Errorf("expected an error for a newer schema version")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RecordSuite(Suite{Name: "suite"})
+		}()
+	}
+	wg.Wait()
+	RecordHealthCheck(HealthCheck{Phase: "install", Passed: true})
+	RecordProvision(Provision{Attempt: 1, FailureCategory: "capacity", Retryable: true})
+
+	if len(Suites()) != 10 || len(HealthChecks()) != 1 || len(Provisions()) != 1 {
+		t.Errorf("unexpected recorded results: %d suites, %d health checks, %d provisions", len(Suites()), len(HealthChecks()), len(Provisions()))
+	}
+
+	Reset()
+	if len(Suites()) != 0 || len(HealthChecks()) != 0 || len(Provisions()) != 0 {
+		t.Errorf("expected no recorded results after reset")
+	}
+}
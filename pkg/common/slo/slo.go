@@ -0,0 +1,348 @@
+// Package slo evaluates declarative, Prometheus backed health assertions against a cluster.
+package slo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// StageHealth is the stage of the assertions evaluated by the cluster health checks.
+	StageHealth = "health"
+	// StageGate is the stage of the assertions evaluated once all tests ran.
+	StageGate = "gate"
+
+	// alertsName is the name of the result of the alert policy.
+	alertsName = "alerts"
+
+	// alertsQuery returns the firing alerts, but for the one that always fires.
+	alertsQuery = `ALERTS{alertstate="firing",alertname!="Watchdog"}`
+)
+
+//go:embed default.yaml
+var defaultConfig []byte
+
+// Config is the set of assertions and the alert policy of a cluster.
+type Config struct {
+	Assertions []Assertion  `yaml:"assertions"`
+	Alerts     *AlertPolicy `yaml:"alerts"`
+}
+
+// Selector limits what clusters an assertion or allowance applies to. Empty fields match all clusters.
+type Selector struct {
+	Providers    []string `yaml:"providers"`
+	Environments []string `yaml:"environments"`
+	// HCP limits it to clusters with or without a hosted control plane
+	HCP *bool `yaml:"hcp"`
+}
+
+// Assertion is a PromQL query whose every series must stay within bounds.
+type Assertion struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Query       string `yaml:"query"`
+	// Min and Max bound the value of every series the query returns. A query without series passes.
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+	// Stages are when the assertion is evaluated, the gate only by default
+	Stages   []string `yaml:"stages"`
+	Selector `yaml:",inline"`
+}
+
+// AlertPolicy fails on firing alerts of some severities, but for the ones allowed to fire.
+type AlertPolicy struct {
+	// Severities are the severities of the alerts that must not fire, critical by default
+	Severities []string `yaml:"severities"`
+	// Stages are when the policy is evaluated, the gate only by default
+	Stages []string         `yaml:"stages"`
+	Allow  []AlertAllowance `yaml:"allow"`
+}
+
+// AlertAllowance lists the alerts known to fire on some clusters.
+type AlertAllowance struct {
+	Alerts   []string `yaml:"alerts"`
+	Selector `yaml:",inline"`
+}
+
+// Cluster is what selectors match against.
+type Cluster struct {
+	// Provider is the type of the provider of the cluster, e.g. ocm or rosa
+	Provider string
+	// Environment is the environment of the provider, e.g. int or prod
+	Environment string
+	HCP         bool
+}
+
+// Result is the outcome of evaluating an assertion or the alert policy.
+type Result struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Query       string      `json:"query"`
+	Passed      bool        `json:"passed"`
+	Error       string      `json:"error,omitempty"`
+	Violations  []Violation `json:"violations,omitempty"`
+}
+
+// Violation is a series that is out of bounds, or an alert that fired.
+type Violation struct {
+	Series string `json:"series"`
+	// Value is nil for NaN, which JSON can't hold
+	Value  *float64 `json:"value,omitempty"`
+	Reason string   `json:"reason"`
+}
+
+// Querier runs instant PromQL queries, as the Prometheus v1 API does.
+type Querier interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error)
+}
+
+// Load loads the config from a YAML file, or the default config if the path is empty.
+func Load(path string) (*Config, error) {
+	data := defaultConfig
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("error reading SLO config: %w", err)
+		}
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a YAML config.
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// Catch misspelled fields rather than ignoring them
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error parsing SLO config: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid SLO config: %w", err)
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	names := map[string]bool{alertsName: true}
+	for _, assertion := range c.Assertions {
+		switch {
+		case assertion.Name == "":
+			return fmt.Errorf("assertion without a name")
+		case names[assertion.Name]:
+			return fmt.Errorf("duplicate assertion %q", assertion.Name)
+		case assertion.Query == "":
+			return fmt.Errorf("assertion %q has no query", assertion.Name)
+		case assertion.Min == nil && assertion.Max == nil:
+			return fmt.Errorf("assertion %q has neither a min nor a max", assertion.Name)
+		}
+		if err := validateStages(assertion.Stages); err != nil {
+			return fmt.Errorf("assertion %q: %w", assertion.Name, err)
+		}
+		names[assertion.Name] = true
+	}
+	if c.Alerts != nil {
+		if err := validateStages(c.Alerts.Stages); err != nil {
+			return fmt.Errorf("alerts: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateStages(stages []string) error {
+	for _, stage := range stages {
+		if stage != StageHealth && stage != StageGate {
+			return fmt.Errorf("unknown stage %q, expected %s or %s", stage, StageHealth, StageGate)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the selector applies to the cluster.
+func (s Selector) Matches(cluster Cluster) bool {
+	if len(s.Providers) > 0 && !slices.Contains(s.Providers, cluster.Provider) {
+		return false
+	}
+	if len(s.Environments) > 0 && !slices.Contains(s.Environments, cluster.Environment) {
+		return false
+	}
+	return s.HCP == nil || *s.HCP == cluster.HCP
+}
+
+// Allowed reports whether the alert is allowed to fire on the cluster.
+func (p *AlertPolicy) Allowed(cluster Cluster, alert string) bool {
+	for _, allowance := range p.Allow {
+		if allowance.Matches(cluster) && slices.Contains(allowance.Alerts, alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fails reports whether a firing alert of the severity fails the policy.
+func (p *AlertPolicy) Fails(severity string) bool {
+	if len(p.Severities) == 0 {
+		return severity == "critical"
+	}
+	return slices.Contains(p.Severities, severity)
+}
+
+func inStage(stages []string, stage string) bool {
+	if len(stages) == 0 {
+		return stage == StageGate
+	}
+	return slices.Contains(stages, stage)
+}
+
+// Applies reports whether any assertion or the alert policy is evaluated for the cluster in the stage.
+func (c *Config) Applies(cluster Cluster, stage string) bool {
+	for _, assertion := range c.Assertions {
+		if inStage(assertion.Stages, stage) && assertion.Matches(cluster) {
+			return true
+		}
+	}
+	return c.Alerts != nil && inStage(c.Alerts.Stages, stage)
+}
+
+// Evaluate evaluates the assertions and the alert policy of the stage that apply to the cluster.
+func Evaluate(ctx context.Context, querier Querier, config *Config, cluster Cluster, stage string, logger *log.Logger) []Result {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	var results []Result
+	for _, assertion := range config.Assertions {
+		if !inStage(assertion.Stages, stage) || !assertion.Matches(cluster) {
+			continue
+		}
+		results = append(results, evaluateAssertion(ctx, querier, assertion, logger))
+	}
+	if config.Alerts != nil && inStage(config.Alerts.Stages, stage) {
+		results = append(results, evaluateAlerts(ctx, querier, config.Alerts, cluster, logger))
+	}
+	return results
+}
+
+// Passed reports whether all results passed.
+func Passed(results []Result) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func evaluateAssertion(ctx context.Context, querier Querier, assertion Assertion, logger *log.Logger) Result {
+	result := Result{Name: assertion.Name, Description: assertion.Description, Query: assertion.Query}
+	samples, err := query(ctx, querier, assertion.Query)
+	if err != nil {
+		logger.Printf("Error evaluating SLO %s: %v", assertion.Name, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	for _, sample := range samples {
+		value := float64(sample.Value)
+		var reason string
+		switch {
+		// NaN compares false against both bounds, but it means the query couldn't compute the value, e.g. a ratio of no requests
+		case math.IsNaN(value):
+			reason = "the value is NaN"
+		case assertion.Min != nil && value < *assertion.Min:
+			reason = fmt.Sprintf("%g is below the minimum of %g", value, *assertion.Min)
+		case assertion.Max != nil && value > *assertion.Max:
+			reason = fmt.Sprintf("%g is above the maximum of %g", value, *assertion.Max)
+		default:
+			continue
+		}
+		logger.Printf("SLO %s not met by %s: %s", assertion.Name, sample.Metric, reason)
+		violation := Violation{Series: sample.Metric.String(), Reason: reason}
+		if !math.IsNaN(value) {
+			violation.Value = &value
+		}
+		result.Violations = append(result.Violations, violation)
+	}
+	result.Passed = len(result.Violations) == 0
+	return result
+}
+
+func evaluateAlerts(ctx context.Context, querier Querier, policy *AlertPolicy, cluster Cluster, logger *log.Logger) Result {
+	result := Result{Name: alertsName, Description: "No unexpected alerts are firing", Query: alertsQuery}
+	samples, err := query(ctx, querier, alertsQuery)
+	if err != nil {
+		logger.Printf("Error querying alerts: %v", err)
+		result.Error = err.Error()
+		return result
+	}
+
+	for _, sample := range samples {
+		name := string(sample.Metric[model.AlertNameLabel])
+		severity := string(sample.Metric["severity"])
+		switch {
+		case !policy.Fails(severity):
+			logger.Printf("Active alert: %s, Severity: %s", name, severity)
+		case policy.Allowed(cluster, name):
+			logger.Printf("Active alert: %s, Severity: %s (allowed to fire, ignoring)", name, severity)
+		default:
+			logger.Printf("Active alert: %s, Severity: %s", name, severity)
+			value := float64(sample.Value)
+			result.Violations = append(result.Violations, Violation{
+				Series: sample.Metric.String(),
+				Value:  &value,
+				Reason: fmt.Sprintf("%s alert %s is firing", severity, name),
+			})
+		}
+	}
+	result.Passed = len(result.Violations) == 0
+	return result
+}
+
+// query runs an instant query and returns its samples sorted by series.
+func query(ctx context.Context, querier Querier, promQL string) (model.Vector, error) {
+	value, _, err := querier.Query(ctx, promQL, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error running query %q: %w", promQL, err)
+	}
+
+	var samples model.Vector
+	switch value := value.(type) {
+	case model.Vector:
+		samples = value
+	case *model.Scalar:
+		samples = model.Vector{{Metric: model.Metric{}, Value: value.Value, Timestamp: value.Timestamp}}
+	default:
+		return nil, fmt.Errorf("query %q returned a %s, expected a vector or a scalar", promQL, value.Type())
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Metric.String() < samples[j].Metric.String()
+	})
+	return samples, nil
+}
+
+// Summary describes the results that didn't pass on a single line.
+func Summary(results []Result) string {
+	var failures []string
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Name, result.Error))
+		case !result.Passed:
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Name, result.Violations[0].Reason))
+		}
+	}
+	return strings.Join(failures, "; ")
+}
@@ -0,0 +1,175 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// fakeQuerier returns canned values by query.
+type fakeQuerier map[string]model.Value
+
+func (f fakeQuerier) Query(_ context.Context, query string, _ time.Time, _ ...v1.Option) (model.Value, v1.Warnings, error) {
+	value, ok := f[query]
+	if !ok {
+		return nil, nil, errors.New("unexpected query")
+	}
+	return value, nil, nil
+}
+
+func sample(value float64, labels ...string) *model.Sample {
+	metric := model.Metric{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		metric[model.LabelName(labels[i])] = model.LabelValue(labels[i+1])
+	}
+	return &model.Sample{Metric: metric, Value: model.SampleValue(value)}
+}
+
+func resultNames(results []Result) []string {
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	return names
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Assertions) == 0 || config.Alerts == nil {
+		t.Fatalf("expected assertions and an alert policy, got %+v", config)
+	}
+	if config.Applies(Cluster{Provider: "rosa"}, StageHealth) {
+		t.Errorf("expected no built in assertion to hold up the health checks")
+	}
+	if !config.Alerts.Allowed(Cluster{Provider: "ocm", Environment: "int"}, "MetricsClientSendFailingSRE") {
+		t.Errorf("expected MetricsClientSendFailingSRE to be allowed on ocm int")
+	}
+	if config.Alerts.Allowed(Cluster{Provider: "ocm", Environment: "prod"}, "MetricsClientSendFailingSRE") {
+		t.Errorf("expected MetricsClientSendFailingSRE not to be allowed on ocm prod")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no name":       "assertions:\n- query: up\n  max: 1\n",
+		"no query":      "assertions:\n- name: up\n  max: 1\n",
+		"no bounds":     "assertions:\n- name: up\n  query: up\n",
+		"duplicate":     "assertions:\n- name: up\n  query: up\n  max: 1\n- name: up\n  query: up\n  max: 1\n",
+		"reserved name": "assertions:\n- name: alerts\n  query: up\n  max: 1\n",
+		"unknown stage": "assertions:\n- name: up\n  query: up\n  max: 1\n  stages: [install]\n",
+		"not yaml":      "assertions: {",
+		"unknown field": "assertions:\n- name: up\n  query: up\n  maximum: 1\n",
+	}
+	for description, data := range tests {
+		t.Run(description, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	config, err := Parse([]byte(`
+assertions:
+- name: error-rate
+  query: errors
+  max: 0.01
+  stages: [health, gate]
+- name: restarts
+  query: restarts
+  max: 10
+- name: targets-up
+  query: up
+  min: 1
+- name: classic-only
+  query: classic
+  max: 1
+  hcp: false
+alerts:
+  allow:
+  - providers: [ocm]
+    alerts: [KnownCritical]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	querier := fakeQuerier{
+		"errors":   &model.Scalar{Value: 0.001},
+		"restarts": model.Vector{sample(12, "namespace", "openshift-dns"), sample(3, "namespace", "openshift-ingress")},
+		"up":       model.Vector{},
+		alertsQuery: model.Vector{
+			sample(1, "alertname", "KnownCritical", "severity", "critical"),
+			sample(1, "alertname", "SomeWarning", "severity", "warning"),
+			sample(1, "alertname", "NewCritical", "severity", "critical"),
+		},
+	}
+	cluster := Cluster{Provider: "ocm", Environment: "prod", HCP: true}
+
+	health := Evaluate(context.Background(), querier, config, cluster, StageHealth, nil)
+	if names := resultNames(health); !slices.Equal(names, []string{"error-rate"}) || !Passed(health) {
+		t.Errorf("expected only the error rate to be evaluated and pass, got %+v", health)
+	}
+
+	gate := Evaluate(context.Background(), querier, config, cluster, StageGate, nil)
+	if names := resultNames(gate); !slices.Equal(names, []string{"error-rate", "restarts", "targets-up", "alerts"}) {
+		t.Fatalf("unexpected results %v", names)
+	}
+	if Passed(gate) {
+		t.Errorf("expected the gate to fail")
+	}
+	restarts := gate[1]
+	if restarts.Passed || len(restarts.Violations) != 1 || *restarts.Violations[0].Value != 12 {
+		t.Errorf("expected openshift-dns to restart too often, got %+v", restarts)
+	}
+	if !gate[2].Passed {
+		t.Errorf("expected a query without series to pass, got %+v", gate[2])
+	}
+	alerts := gate[3]
+	if alerts.Passed || len(alerts.Violations) != 1 || alerts.Violations[0].Reason != "critical alert NewCritical is firing" {
+		t.Errorf("expected only NewCritical to fail the alerts, got %+v", alerts)
+	}
+	if summary := Summary(gate); summary != "restarts: 12 is above the maximum of 10; alerts: critical alert NewCritical is firing" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+}
+
+func TestEvaluateNaN(t *testing.T) {
+	config, err := Parse([]byte("assertions:\n- name: error-rate\n  query: errors\n  max: 0.01\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	querier := fakeQuerier{"errors": model.Vector{sample(math.NaN(), "job", "apiserver"), sample(0.001, "job", "oauth")}}
+
+	results := Evaluate(context.Background(), querier, config, Cluster{}, StageGate, nil)
+	if len(results) != 1 || results[0].Passed || len(results[0].Violations) != 1 {
+		t.Fatalf("expected the NaN series to fail the assertion, got %+v", results)
+	}
+	if violation := results[0].Violations[0]; violation.Value != nil || violation.Reason != "the value is NaN" {
+		t.Errorf("expected a NaN violation without a value, got %+v", violation)
+	}
+	if _, err := json.Marshal(results); err != nil {
+		t.Errorf("expected the results to encode as JSON, got %v", err)
+	}
+}
+
+func TestEvaluateQueryError(t *testing.T) {
+	config, err := Parse([]byte("assertions:\n- name: missing\n  query: missing\n  max: 1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results := Evaluate(context.Background(), fakeQuerier{}, config, Cluster{}, StageGate, nil)
+	if len(results) != 1 || results[0].Passed || results[0].Error == "" {
+		t.Errorf("expected the query error in the result, got %+v", results)
+	}
+}
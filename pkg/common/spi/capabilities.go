@@ -0,0 +1,157 @@
+package spi
+
+import (
+	"time"
+)
+
+// Names of the capabilities, used as the operation of the NotSupportedError for a provider that lacks one.
+const (
+	CapabilityUpgrades       = "managed upgrade"
+	CapabilityClusterProxies = "cluster proxy"
+	CapabilityExpiry         = "cluster expiry"
+	CapabilityProperties     = "cluster property"
+	CapabilityAddons         = "addon installation"
+	CapabilityVersionGates   = "version gate agreement"
+	CapabilityHibernation    = "cluster hibernation"
+)
+
+// As returns the capability C of the provider, e.g. As[Upgrader](provider), and whether the provider
+// has it. Providers registered with RegisterLifecycleProvider only have the capabilities they implement.
+func As[C any](provider Provider) (C, bool) {
+	if p, ok := provider.(*partialProvider); ok {
+		c, ok := p.Lifecycle.(C)
+		return c, ok
+	}
+	c, ok := provider.(C)
+	return c, ok
+}
+
+// Complete adapts a provider that only implements some capabilities to a Provider. Methods of the
+// capabilities it lacks return a NotSupportedError, and As reports them as missing.
+func Complete(provider Lifecycle) Provider {
+	if p, ok := provider.(Provider); ok {
+		return p
+	}
+	return &partialProvider{Lifecycle: provider}
+}
+
+// partialProvider implements Provider for a provider with some of the capabilities
+type partialProvider struct {
+	Lifecycle
+}
+
+var _ Provider = &partialProvider{}
+
+func (p *partialProvider) Upgrade(clusterID string, version string, t time.Time) error {
+	if c, ok := p.Lifecycle.(Upgrader); ok {
+		return c.Upgrade(clusterID, version, t)
+	}
+	return p.missing("Upgrade")
+}
+
+func (p *partialProvider) GetUpgradePolicyID(clusterID string) (string, error) {
+	if c, ok := p.Lifecycle.(Upgrader); ok {
+		return c.GetUpgradePolicyID(clusterID)
+	}
+	return "", p.missing("GetUpgradePolicyID")
+}
+
+func (p *partialProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	if c, ok := p.Lifecycle.(Upgrader); ok {
+		return c.UpdateSchedule(clusterID, version, t, policyID)
+	}
+	return p.missing("UpdateSchedule")
+}
+
+func (p *partialProvider) AddClusterProxy(clusterID string, httpsProxy string, httpProxy string, userCABundle string) error {
+	if c, ok := p.Lifecycle.(ProxyManager); ok {
+		return c.AddClusterProxy(clusterID, httpsProxy, httpProxy, userCABundle)
+	}
+	return p.missing("AddClusterProxy")
+}
+
+func (p *partialProvider) RemoveClusterProxy(clusterID string) error {
+	if c, ok := p.Lifecycle.(ProxyManager); ok {
+		return c.RemoveClusterProxy(clusterID)
+	}
+	return p.missing("RemoveClusterProxy")
+}
+
+func (p *partialProvider) RemoveUserCABundle(clusterID string) error {
+	if c, ok := p.Lifecycle.(ProxyManager); ok {
+		return c.RemoveUserCABundle(clusterID)
+	}
+	return p.missing("RemoveUserCABundle")
+}
+
+func (p *partialProvider) LoadUserCaBundleData(file string) (string, error) {
+	if c, ok := p.Lifecycle.(ProxyManager); ok {
+		return c.LoadUserCaBundleData(file)
+	}
+	return "", p.missing("LoadUserCaBundleData")
+}
+
+func (p *partialProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	if c, ok := p.Lifecycle.(ExpiryManager); ok {
+		return c.ExtendExpiry(clusterID, hours, minutes, seconds)
+	}
+	return p.missing("ExtendExpiry")
+}
+
+func (p *partialProvider) Expire(clusterID string, duration time.Duration) error {
+	if c, ok := p.Lifecycle.(ExpiryManager); ok {
+		return c.Expire(clusterID, duration)
+	}
+	return p.missing("Expire")
+}
+
+func (p *partialProvider) AddProperty(cluster *Cluster, tag string, value string) error {
+	if c, ok := p.Lifecycle.(PropertyStore); ok {
+		return c.AddProperty(cluster, tag, value)
+	}
+	return p.missing("AddProperty")
+}
+
+func (p *partialProvider) GetProperty(clusterID string, property string) (string, error) {
+	if c, ok := p.Lifecycle.(PropertyStore); ok {
+		return c.GetProperty(clusterID, property)
+	}
+	return "", p.missing("GetProperty")
+}
+
+// InstallAddons succeeds without addons, so clusters can be provisioned without the capability.
+func (p *partialProvider) InstallAddons(clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error) {
+	if c, ok := p.Lifecycle.(AddonInstaller); ok {
+		return c.InstallAddons(clusterID, addonIDs, params)
+	}
+	if len(addonIDs) == 0 {
+		return 0, nil
+	}
+	return 0, p.missing("InstallAddons")
+}
+
+// VersionGateLabel is empty without the capability.
+func (p *partialProvider) VersionGateLabel() string {
+	if c, ok := p.Lifecycle.(VersionGater); ok {
+		return c.VersionGateLabel()
+	}
+	return ""
+}
+
+func (p *partialProvider) GetVersionGateID(version string, label string) (string, error) {
+	if c, ok := p.Lifecycle.(VersionGater); ok {
+		return c.GetVersionGateID(version, label)
+	}
+	return "", p.missing("GetVersionGateID")
+}
+
+func (p *partialProvider) AddGateAgreement(clusterID string, versionGateID string) error {
+	if c, ok := p.Lifecycle.(VersionGater); ok {
+		return c.AddGateAgreement(clusterID, versionGateID)
+	}
+	return p.missing("AddGateAgreement")
+}
+
+func (p *partialProvider) missing(operation string) error {
+	return NewNotSupportedError(p.Type(), operation)
+}
@@ -0,0 +1,72 @@
+package spi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// lifecycleProvider only has the Lifecycle capability
+type lifecycleProvider struct {
+	Lifecycle
+}
+
+func (l *lifecycleProvider) Type() string { return "lifecycle" }
+
+// propertyProvider also has the PropertyStore capability
+type propertyProvider struct {
+	lifecycleProvider
+	properties map[string]string
+}
+
+func (p *propertyProvider) AddProperty(cluster *Cluster, tag string, value string) error {
+	p.properties[tag] = value
+	return nil
+}
+
+func (p *propertyProvider) GetProperty(clusterID string, property string) (string, error) {
+	return p.properties[property], nil
+}
+
+func TestComplete(t *testing.T) {
+	provider := Complete(&propertyProvider{properties: map[string]string{}})
+
+	if _, ok := As[Upgrader](provider); ok {
+		t.Errorf("expected the provider to lack the upgrades capability")
+	}
+	store, ok := As[PropertyStore](provider)
+	if !ok {
+		t.Fatalf("expected the provider to store properties")
+	}
+	if err := provider.AddProperty(nil, "status", "healthy"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if value, _ := store.GetProperty("abc", "status"); value != "healthy" {
+		t.Errorf("expected the property to be stored by the provider, got %q", value)
+	}
+
+	err := provider.Upgrade("abc", "4.18.0", time.Time{})
+	var notSupported *NotSupportedError
+	if !errors.As(err, &notSupported) || notSupported.Provider != "lifecycle" || notSupported.Operation != "Upgrade" {
+		t.Errorf("expected Upgrade to be not supported, got %v", err)
+	}
+	if _, err := provider.InstallAddons("abc", nil, nil); err != nil {
+		t.Errorf("expected no error when no addons are requested, got %v", err)
+	}
+	if _, err := provider.InstallAddons("abc", []AddOnID{"addon"}, nil); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected addons to be not supported, got %v", err)
+	}
+}
+
+func TestAs(t *testing.T) {
+	provider := &blockingProvider{}
+	if _, ok := As[Upgrader](provider); !ok {
+		t.Errorf("expected a complete provider to have every capability")
+	}
+	if Complete(provider) != Provider(provider) {
+		t.Errorf("expected a complete provider to be returned unchanged")
+	}
+	if _, ok := As[VersionGater](Complete(&lifecycleProvider{})); ok {
+		t.Errorf("expected a lifecycle provider to lack version gates")
+	}
+}
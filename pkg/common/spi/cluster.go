@@ -51,6 +51,9 @@ type Cluster struct {
 
 	// Custom tags for a cluster are included in the properties field
 	properties map[string]string
+
+	// provisionError is the reason the provider gave for a failed install
+	provisionError string
 }
 
 // ID returns the cluster ID.
@@ -123,6 +126,12 @@ func (c *Cluster) Properties() map[string]string {
 	return c.properties
 }
 
+// ProvisionError returns the reason the provider gave for a failed install, e.g. an error code and
+// message, or an empty string.
+func (c *Cluster) ProvisionError() string {
+	return c.provisionError
+}
+
 // ClusterBuilder is a struct that can create cluster objects.
 type ClusterBuilder struct {
 	id                  string
@@ -139,6 +148,7 @@ type ClusterBuilder struct {
 	addons              []string
 	numComputeNodes     int
 	properties          map[string]string
+	provisionError      string
 }
 
 // NewClusterBuilder creates a new cluster builder that can create a new cluster.
@@ -239,6 +249,12 @@ func (cb *ClusterBuilder) Properties(properties map[string]string) *ClusterBuild
 	return cb
 }
 
+// ProvisionError sets the reason the provider gave for a failed install.
+func (cb *ClusterBuilder) ProvisionError(provisionError string) *ClusterBuilder {
+	cb.provisionError = provisionError
+	return cb
+}
+
 // Build will create the cluster from the cluster build.
 func (cb *ClusterBuilder) Build() *Cluster {
 	return &Cluster{
@@ -256,5 +272,6 @@ func (cb *ClusterBuilder) Build() *Cluster {
 		addons:              cb.addons,
 		numComputeNodes:     cb.numComputeNodes,
 		properties:          cb.properties,
+		provisionError:      cb.provisionError,
 	}
 }
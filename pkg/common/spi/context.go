@@ -0,0 +1,386 @@
+package spi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ContextProvider is the context-first revision of Provider. Every method that calls out to the
+// provider takes a context, so cancellation and deadlines interrupt in-flight calls and wait loops.
+//
+// Existing providers are adapted with WithContext. New providers should implement ContextProvider
+// and register with RegisterContextProvider, so they can still be used where a Provider is expected.
+type ContextProvider interface {
+	IsValidClusterName(ctx context.Context, clusterName string) (bool, error)
+	LaunchCluster(ctx context.Context, clusterName string) (string, error)
+	DeleteCluster(ctx context.Context, clusterID string) error
+	ListClusters(ctx context.Context, query string) ([]*Cluster, error)
+	GetCluster(ctx context.Context, clusterID string) (*Cluster, error)
+	GetClusterRegion(ctx context.Context, clusterID string) (string, error)
+	ClusterKubeconfig(ctx context.Context, clusterID string) ([]byte, error)
+	CheckQuota(ctx context.Context, sku string) (bool, error)
+	InstallAddons(ctx context.Context, clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error)
+	Versions(ctx context.Context) (*VersionList, error)
+	Logs(ctx context.Context, clusterID string) (map[string][]byte, error)
+	Metrics(ctx context.Context, clusterID string) (bool, error)
+	Environment() string
+	UpgradeSource() UpgradeSource
+	CincinnatiChannel() CincinnatiChannel
+	Type() string
+	ExtendExpiry(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error
+	Expire(ctx context.Context, clusterID string, duration time.Duration) error
+	AddProperty(ctx context.Context, cluster *Cluster, tag string, value string) error
+	GetProperty(ctx context.Context, clusterID string, property string) (string, error)
+	Upgrade(ctx context.Context, clusterID string, version string, t time.Time) error
+	GetUpgradePolicyID(ctx context.Context, clusterID string) (string, error)
+	UpdateSchedule(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error
+	DetermineMachineType(ctx context.Context, cloudProvider string) (string, error)
+	AddClusterProxy(ctx context.Context, clusterID string, httpsProxy string, httpProxy string, userCABundle string) error
+	RemoveClusterProxy(ctx context.Context, clusterID string) error
+	RemoveUserCABundle(ctx context.Context, clusterID string) error
+	LoadUserCaBundleData(file string) (string, error)
+	VersionGateLabel() string
+	GetVersionGateID(ctx context.Context, version string, label string) (string, error)
+	AddGateAgreement(ctx context.Context, clusterID string, versionGateID string) error
+}
+
+// ContextBinder is implemented by providers whose calls can run with the context of the caller, e.g.
+// ones registered with RegisterLifecycleProvider. WithContext binds every call to its context, so
+// cancelling it interrupts the call.
+type ContextBinder interface {
+	// Bind returns the provider with its calls bound to ctx.
+	Bind(ctx context.Context) Lifecycle
+}
+
+// WithContext adapts a Provider to a ContextProvider. Calls of a ContextBinder get the context.
+// Calls of other providers return as soon as the context is done, even though the underlying call
+// keeps running in the background until it returns.
+func WithContext(provider Provider) ContextProvider {
+	if p, ok := provider.(*providerAdapter); ok {
+		return p.ContextProvider
+	}
+
+	var lifecycle Lifecycle = provider
+	if p, ok := provider.(*partialProvider); ok {
+		lifecycle = p.Lifecycle
+	}
+	binder, _ := lifecycle.(ContextBinder)
+	return &contextAdapter{provider: provider, binder: binder}
+}
+
+// WithoutContext adapts a ContextProvider to a Provider for callers that don't pass a context yet.
+// The calls use context.Background().
+func WithoutContext(provider ContextProvider) Provider {
+	if p, ok := provider.(*contextAdapter); ok {
+		return p.provider
+	}
+	return &providerAdapter{ContextProvider: provider}
+}
+
+// await runs call and returns its result, or the error of the context if it's done first
+func await[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// contextAdapter implements ContextProvider for a Provider
+type contextAdapter struct {
+	provider Provider
+	// binder binds the calls to their context, if the provider supports that
+	binder ContextBinder
+}
+
+// call runs fn with the provider bound to ctx if it supports that, and awaits it otherwise
+func call[T any](a *contextAdapter, ctx context.Context, fn func(Provider) (T, error)) (T, error) {
+	if a.binder == nil {
+		return await(ctx, func() (T, error) { return fn(a.provider) })
+	}
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return fn(Complete(a.binder.Bind(ctx)))
+}
+
+// callErr is call for calls that only return an error
+func callErr(a *contextAdapter, ctx context.Context, fn func(Provider) error) error {
+	_, err := call(a, ctx, func(p Provider) (struct{}, error) { return struct{}{}, fn(p) })
+	return err
+}
+
+func (a *contextAdapter) IsValidClusterName(ctx context.Context, clusterName string) (bool, error) {
+	return call(a, ctx, func(p Provider) (bool, error) { return p.IsValidClusterName(clusterName) })
+}
+
+// LaunchCluster waits for a launch that can't be interrupted even once the context is done, and
+// deletes the cluster it created then, so that no cluster is left behind that nothing records.
+func (a *contextAdapter) LaunchCluster(ctx context.Context, clusterName string) (string, error) {
+	if a.binder != nil {
+		return call(a, ctx, func(p Provider) (string, error) { return p.LaunchCluster(clusterName) })
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	type result struct {
+		clusterID string
+		err       error
+	}
+	done := make(chan result, 1)
+	go func() {
+		clusterID, err := a.provider.LaunchCluster(clusterName)
+		done <- result{clusterID, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.clusterID, r.err
+	case <-ctx.Done():
+	}
+
+	r := <-done
+	if r.clusterID != "" {
+		log.Printf("Deleting cluster %s launched after the context was done", r.clusterID)
+		if err := a.provider.DeleteCluster(r.clusterID); err != nil {
+			return "", fmt.Errorf("%w, and deleting cluster %s launched meanwhile failed: %v", ctx.Err(), r.clusterID, err)
+		}
+	}
+	return "", ctx.Err()
+}
+
+func (a *contextAdapter) DeleteCluster(ctx context.Context, clusterID string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.DeleteCluster(clusterID) })
+}
+
+func (a *contextAdapter) ListClusters(ctx context.Context, query string) ([]*Cluster, error) {
+	return call(a, ctx, func(p Provider) ([]*Cluster, error) { return p.ListClusters(query) })
+}
+
+func (a *contextAdapter) GetCluster(ctx context.Context, clusterID string) (*Cluster, error) {
+	return call(a, ctx, func(p Provider) (*Cluster, error) { return p.GetCluster(clusterID) })
+}
+
+func (a *contextAdapter) GetClusterRegion(ctx context.Context, clusterID string) (string, error) {
+	return call(a, ctx, func(p Provider) (string, error) { return p.GetClusterRegion(clusterID) })
+}
+
+func (a *contextAdapter) ClusterKubeconfig(ctx context.Context, clusterID string) ([]byte, error) {
+	return call(a, ctx, func(p Provider) ([]byte, error) { return p.ClusterKubeconfig(clusterID) })
+}
+
+func (a *contextAdapter) CheckQuota(ctx context.Context, sku string) (bool, error) {
+	return call(a, ctx, func(p Provider) (bool, error) { return p.CheckQuota(sku) })
+}
+
+func (a *contextAdapter) InstallAddons(ctx context.Context, clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error) {
+	return call(a, ctx, func(p Provider) (int, error) { return p.InstallAddons(clusterID, addonIDs, params) })
+}
+
+func (a *contextAdapter) Versions(ctx context.Context) (*VersionList, error) {
+	return call(a, ctx, Provider.Versions)
+}
+
+func (a *contextAdapter) Logs(ctx context.Context, clusterID string) (map[string][]byte, error) {
+	return call(a, ctx, func(p Provider) (map[string][]byte, error) { return p.Logs(clusterID) })
+}
+
+func (a *contextAdapter) Metrics(ctx context.Context, clusterID string) (bool, error) {
+	return call(a, ctx, func(p Provider) (bool, error) { return p.Metrics(clusterID) })
+}
+
+func (a *contextAdapter) Environment() string { return a.provider.Environment() }
+
+func (a *contextAdapter) UpgradeSource() UpgradeSource { return a.provider.UpgradeSource() }
+
+func (a *contextAdapter) CincinnatiChannel() CincinnatiChannel { return a.provider.CincinnatiChannel() }
+
+func (a *contextAdapter) Type() string { return a.provider.Type() }
+
+func (a *contextAdapter) ExtendExpiry(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	return callErr(a, ctx, func(p Provider) error { return p.ExtendExpiry(clusterID, hours, minutes, seconds) })
+}
+
+func (a *contextAdapter) Expire(ctx context.Context, clusterID string, duration time.Duration) error {
+	return callErr(a, ctx, func(p Provider) error { return p.Expire(clusterID, duration) })
+}
+
+func (a *contextAdapter) AddProperty(ctx context.Context, cluster *Cluster, tag string, value string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.AddProperty(cluster, tag, value) })
+}
+
+func (a *contextAdapter) GetProperty(ctx context.Context, clusterID string, property string) (string, error) {
+	return call(a, ctx, func(p Provider) (string, error) { return p.GetProperty(clusterID, property) })
+}
+
+func (a *contextAdapter) Upgrade(ctx context.Context, clusterID string, version string, t time.Time) error {
+	return callErr(a, ctx, func(p Provider) error { return p.Upgrade(clusterID, version, t) })
+}
+
+func (a *contextAdapter) GetUpgradePolicyID(ctx context.Context, clusterID string) (string, error) {
+	return call(a, ctx, func(p Provider) (string, error) { return p.GetUpgradePolicyID(clusterID) })
+}
+
+func (a *contextAdapter) UpdateSchedule(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.UpdateSchedule(clusterID, version, t, policyID) })
+}
+
+func (a *contextAdapter) DetermineMachineType(ctx context.Context, cloudProvider string) (string, error) {
+	return call(a, ctx, func(p Provider) (string, error) { return p.DetermineMachineType(cloudProvider) })
+}
+
+func (a *contextAdapter) AddClusterProxy(ctx context.Context, clusterID string, httpsProxy string, httpProxy string, userCABundle string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.AddClusterProxy(clusterID, httpsProxy, httpProxy, userCABundle) })
+}
+
+func (a *contextAdapter) RemoveClusterProxy(ctx context.Context, clusterID string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.RemoveClusterProxy(clusterID) })
+}
+
+func (a *contextAdapter) RemoveUserCABundle(ctx context.Context, clusterID string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.RemoveUserCABundle(clusterID) })
+}
+
+func (a *contextAdapter) LoadUserCaBundleData(file string) (string, error) {
+	return a.provider.LoadUserCaBundleData(file)
+}
+
+func (a *contextAdapter) VersionGateLabel() string { return a.provider.VersionGateLabel() }
+
+func (a *contextAdapter) GetVersionGateID(ctx context.Context, version string, label string) (string, error) {
+	return call(a, ctx, func(p Provider) (string, error) { return p.GetVersionGateID(version, label) })
+}
+
+func (a *contextAdapter) AddGateAgreement(ctx context.Context, clusterID string, versionGateID string) error {
+	return callErr(a, ctx, func(p Provider) error { return p.AddGateAgreement(clusterID, versionGateID) })
+}
+
+// providerAdapter implements Provider for a ContextProvider
+type providerAdapter struct {
+	ContextProvider
+}
+
+func (a *providerAdapter) IsValidClusterName(clusterName string) (bool, error) {
+	return a.ContextProvider.IsValidClusterName(context.Background(), clusterName)
+}
+
+func (a *providerAdapter) LaunchCluster(clusterName string) (string, error) {
+	return a.ContextProvider.LaunchCluster(context.Background(), clusterName)
+}
+
+func (a *providerAdapter) DeleteCluster(clusterID string) error {
+	return a.ContextProvider.DeleteCluster(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) ListClusters(query string) ([]*Cluster, error) {
+	return a.ContextProvider.ListClusters(context.Background(), query)
+}
+
+func (a *providerAdapter) GetCluster(clusterID string) (*Cluster, error) {
+	return a.ContextProvider.GetCluster(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) GetClusterRegion(clusterID string) (string, error) {
+	return a.ContextProvider.GetClusterRegion(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	return a.ContextProvider.ClusterKubeconfig(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) CheckQuota(sku string) (bool, error) {
+	return a.ContextProvider.CheckQuota(context.Background(), sku)
+}
+
+func (a *providerAdapter) InstallAddons(clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error) {
+	return a.ContextProvider.InstallAddons(context.Background(), clusterID, addonIDs, params)
+}
+
+func (a *providerAdapter) Versions() (*VersionList, error) {
+	return a.ContextProvider.Versions(context.Background())
+}
+
+func (a *providerAdapter) Logs(clusterID string) (map[string][]byte, error) {
+	return a.ContextProvider.Logs(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) Metrics(clusterID string) (bool, error) {
+	return a.ContextProvider.Metrics(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	return a.ContextProvider.ExtendExpiry(context.Background(), clusterID, hours, minutes, seconds)
+}
+
+func (a *providerAdapter) Expire(clusterID string, duration time.Duration) error {
+	return a.ContextProvider.Expire(context.Background(), clusterID, duration)
+}
+
+func (a *providerAdapter) AddProperty(cluster *Cluster, tag string, value string) error {
+	return a.ContextProvider.AddProperty(context.Background(), cluster, tag, value)
+}
+
+func (a *providerAdapter) GetProperty(clusterID string, property string) (string, error) {
+	return a.ContextProvider.GetProperty(context.Background(), clusterID, property)
+}
+
+func (a *providerAdapter) Upgrade(clusterID string, version string, t time.Time) error {
+	return a.ContextProvider.Upgrade(context.Background(), clusterID, version, t)
+}
+
+func (a *providerAdapter) GetUpgradePolicyID(clusterID string) (string, error) {
+	return a.ContextProvider.GetUpgradePolicyID(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	return a.ContextProvider.UpdateSchedule(context.Background(), clusterID, version, t, policyID)
+}
+
+func (a *providerAdapter) DetermineMachineType(cloudProvider string) (string, error) {
+	return a.ContextProvider.DetermineMachineType(context.Background(), cloudProvider)
+}
+
+func (a *providerAdapter) AddClusterProxy(clusterID string, httpsProxy string, httpProxy string, userCABundle string) error {
+	return a.ContextProvider.AddClusterProxy(context.Background(), clusterID, httpsProxy, httpProxy, userCABundle)
+}
+
+func (a *providerAdapter) RemoveClusterProxy(clusterID string) error {
+	return a.ContextProvider.RemoveClusterProxy(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) RemoveUserCABundle(clusterID string) error {
+	return a.ContextProvider.RemoveUserCABundle(context.Background(), clusterID)
+}
+
+func (a *providerAdapter) GetVersionGateID(version string, label string) (string, error) {
+	return a.ContextProvider.GetVersionGateID(context.Background(), version, label)
+}
+
+func (a *providerAdapter) AddGateAgreement(clusterID string, versionGateID string) error {
+	return a.ContextProvider.AddGateAgreement(context.Background(), clusterID, versionGateID)
+}
+
+var (
+	_ ContextProvider = &contextAdapter{}
+	_ Provider        = &providerAdapter{}
+)
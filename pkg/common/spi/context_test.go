@@ -0,0 +1,132 @@
+package spi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingProvider is a provider without context support whose GetCluster blocks until released
+type blockingProvider struct {
+	Provider
+	release chan struct{}
+}
+
+func (b *blockingProvider) GetCluster(clusterID string) (*Cluster, error) {
+	<-b.release
+	return NewClusterBuilder().ID(clusterID).Build(), nil
+}
+
+func (b *blockingProvider) Type() string { return "blocking" }
+
+func TestWithContextCancel(t *testing.T) {
+	provider := &blockingProvider{release: make(chan struct{})}
+	defer close(provider.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := WithContext(provider).GetCluster(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to be interrupted by the deadline, got %v", err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	provider := &blockingProvider{release: make(chan struct{})}
+	close(provider.release)
+
+	cluster, err := WithContext(provider).GetCluster(context.Background(), "abc")
+	if err != nil || cluster.ID() != "abc" {
+		t.Errorf("expected the cluster of the provider, got %v, %v", cluster, err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WithContext(provider).GetCluster(canceled, "abc"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected no call with a canceled context, got %v", err)
+	}
+
+	if WithoutContext(WithContext(provider)) != Provider(provider) {
+		t.Errorf("expected adapting back to return the original provider")
+	}
+	native := &nativeProvider{ContextProvider: WithContext(provider)}
+	if WithContext(WithoutContext(native)) != ContextProvider(native) {
+		t.Errorf("expected adapting back to return the original context provider")
+	}
+	if WithoutContext(native).Type() != "blocking" {
+		t.Errorf("expected calls without a context to be passed through")
+	}
+}
+
+// nativeProvider stands in for a provider that implements ContextProvider itself
+type nativeProvider struct {
+	ContextProvider
+}
+
+// launchingProvider is a provider without context support whose LaunchCluster blocks until released
+type launchingProvider struct {
+	Provider
+	release chan struct{}
+	deleted []string
+}
+
+func (l *launchingProvider) LaunchCluster(clusterName string) (string, error) {
+	<-l.release
+	return clusterName, nil
+}
+
+func (l *launchingProvider) DeleteCluster(clusterID string) error {
+	l.deleted = append(l.deleted, clusterID)
+	return nil
+}
+
+func TestWithContextLaunchCancel(t *testing.T) {
+	provider := &launchingProvider{release: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WithContext(provider).LaunchCluster(ctx, "abc"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected no launch with a canceled context, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		close(provider.release)
+	}()
+	if _, err := WithContext(provider).LaunchCluster(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the launch to be interrupted by the deadline, got %v", err)
+	}
+	if len(provider.deleted) != 1 || provider.deleted[0] != "abc" {
+		t.Errorf("expected the cluster launched after the deadline to be deleted, got %v", provider.deleted)
+	}
+}
+
+// bindingProvider is a partial provider whose calls take the context they are bound to
+type bindingProvider struct {
+	Lifecycle
+	ctx context.Context
+}
+
+func (b *bindingProvider) Bind(ctx context.Context) Lifecycle {
+	return &bindingProvider{ctx: ctx}
+}
+
+func (b *bindingProvider) GetCluster(clusterID string) (*Cluster, error) {
+	if b.ctx == nil {
+		return nil, errors.New("no context bound")
+	}
+	<-b.ctx.Done()
+	return nil, b.ctx.Err()
+}
+
+func TestWithContextBind(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := WithContext(Complete(&bindingProvider{})).GetCluster(ctx, "abc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to get the context, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package spi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSupported is matched by errors.Is for any NotSupportedError.
+var ErrNotSupported = errors.New("operation not supported by provider")
+
+// NotSupportedError is returned by providers for operations they do not implement,
+// e.g. OCM-only operations such as version gates or upgrade policies on a local cluster.
+type NotSupportedError struct {
+	// Provider is the provider type that rejected the operation.
+	Provider string
+	// Operation is the name of the unsupported operation.
+	Operation string
+}
+
+// NewNotSupportedError creates a new NotSupportedError for the given provider and operation.
+func NewNotSupportedError(provider, operation string) *NotSupportedError {
+	return &NotSupportedError{
+		Provider:  provider,
+		Operation: operation,
+	}
+}
+
+// Error implements the error interface.
+func (e *NotSupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by the %s provider", e.Operation, e.Provider)
+}
+
+// Is allows errors.Is(err, ErrNotSupported) to match a NotSupportedError.
+func (e *NotSupportedError) Is(target error) bool {
+	return target == ErrNotSupported
+}
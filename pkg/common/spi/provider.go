@@ -12,7 +12,22 @@ type AddOnID = string
 type AddOnParams = map[string]string
 
 // Provider is the interface that must be implemented in order to provision clusters in osde2e.
+//
+// It is the combination of all capabilities. Providers that only implement some of them register
+// with RegisterLifecycleProvider, and callers find out which capabilities are available with As.
 type Provider interface {
+	Lifecycle
+	Upgrader
+	ProxyManager
+	ExpiryManager
+	PropertyStore
+	AddonInstaller
+	VersionGater
+}
+
+// Lifecycle is the capability every provider has: creating, finding and deleting clusters and
+// describing the versions and environment they run in.
+type Lifecycle interface {
 	// IsValidClusterName validates that the proposed name used for creating the cluster.
 	//
 	// Currently this validates if the proposed clusterName already exists before attempting to
@@ -58,12 +73,8 @@ type Provider interface {
 	// is currently expected to be configured by the global config object.
 	CheckQuota(sku string) (bool, error)
 
-	// InstallAddons will install addons onto the cluster.
-	//
-	// OpenShift dedicated has the notion of addon installation, which users can request from
-	// the OCM API. If you wish to emulate this support, the provider will need to support a similar
-	// mechanism.
-	InstallAddons(clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error)
+	// DetermineMachineType selects a random machine type for a given cluster.
+	DetermineMachineType(cloudProvider string) (string, error)
 
 	// Versions returns a sorted list of supported OpenShift versions.
 	//
@@ -103,19 +114,10 @@ type Provider interface {
 	//
 	// This simply returns the name of the Provider
 	Type() string
+}
 
-	// ExtendExpiry extends the expiration time of an existing cluster.
-	ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error
-
-	// Expire sets the expiration of an existing cluster to the current time.
-	Expire(clusterID string, duration time.Duration) error
-
-	// AddProperty adds a new property to the properties field of an existing cluster.
-	AddProperty(cluster *Cluster, tag string, value string) error
-
-	// GetProperty gets a property from the properties field of an existing cluster.
-	GetProperty(clusterID string, property string) (string, error)
-
+// Upgrader is implemented by providers that schedule cluster upgrades through upgrade policies.
+type Upgrader interface {
 	// Upgrade requests the provider initiate a cluster upgrade to the given version
 	Upgrade(clusterID string, version string, t time.Time) error
 
@@ -124,10 +126,10 @@ type Provider interface {
 
 	// UpdateSchedule updates the existing upgrade policy for re-scheduling
 	UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error
+}
 
-	// DetermineMachineType selects a random machine type for a given cluster.
-	DetermineMachineType(cloudProvider string) (string, error)
-
+// ProxyManager is implemented by providers that can configure a cluster-wide proxy.
+type ProxyManager interface {
 	// AddClusterProxy adds a cluster-wide proxy to the cluster.
 	AddClusterProxy(clusterId string, httpsProxy string, httpProxy string, userCABundle string) error
 
@@ -139,7 +141,38 @@ type Provider interface {
 
 	// LoadUserCaBundleData loads CA contents from CA cert file
 	LoadUserCaBundleData(file string) (string, error)
+}
 
+// ExpiryManager is implemented by providers whose clusters expire.
+type ExpiryManager interface {
+	// ExtendExpiry extends the expiration time of an existing cluster.
+	ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error
+
+	// Expire sets the expiration of an existing cluster to the current time.
+	Expire(clusterID string, duration time.Duration) error
+}
+
+// PropertyStore is implemented by providers that can store properties on a cluster.
+type PropertyStore interface {
+	// AddProperty adds a new property to the properties field of an existing cluster.
+	AddProperty(cluster *Cluster, tag string, value string) error
+
+	// GetProperty gets a property from the properties field of an existing cluster.
+	GetProperty(clusterID string, property string) (string, error)
+}
+
+// AddonInstaller is implemented by providers that can install addons onto a cluster.
+type AddonInstaller interface {
+	// InstallAddons will install addons onto the cluster.
+	//
+	// OpenShift dedicated has the notion of addon installation, which users can request from
+	// the OCM API. If you wish to emulate this support, the provider will need to support a similar
+	// mechanism.
+	InstallAddons(clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error)
+}
+
+// VersionGater is implemented by providers that require version gate agreements for upgrades.
+type VersionGater interface {
 	// VersionGateLabel returns the provider version gate label
 	VersionGateLabel() string
 
@@ -149,3 +182,37 @@ type Provider interface {
 	// AddGateAgreement adds gate agreement to the cluster to acknowledge cluster upgrade
 	AddGateAgreement(clusterID string, versionGateID string) error
 }
+
+// Hibernator is implemented by providers that can hibernate a cluster, shutting down its machines
+// while keeping its state, and resume it. It isn't part of Provider, so callers look it up with As.
+type Hibernator interface {
+	// Hibernate starts hibernating a ready cluster. It is powering down until it's hibernating.
+	Hibernate(clusterID string) error
+
+	// Resume starts resuming a hibernating cluster. It is resuming until it's ready.
+	Resume(clusterID string) error
+}
+
+// InflightChecker is implemented by providers that run checks, e.g. of the network egress, while a
+// cluster is provisioning. It isn't part of Provider, so callers look it up with As.
+type InflightChecker interface {
+	// InflightChecks returns the checks run for the cluster so far.
+	InflightChecks(clusterID string) ([]InflightCheck, error)
+}
+
+// RegionChooser is implemented by providers that pick the region of a new cluster, e.g. a random one.
+// It isn't part of Provider, so callers look it up with As.
+type RegionChooser interface {
+	// DetermineRegion returns the region new clusters of the cloud provider are launched in.
+	DetermineRegion(cloudProvider string) (string, error)
+}
+
+// InflightCheck is the result of a check run while a cluster is provisioning.
+type InflightCheck struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	StartedAt time.Time `json:"startedAt,omitempty"`
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+	Restarts  int       `json:"restarts,omitempty"`
+	Details   any       `json:"details,omitempty"`
+}
@@ -24,6 +24,30 @@ func RegisterProvider(name string, providerCreate func() (Provider, error)) {
 	registry.providerCreation[name] = providerCreate
 }
 
+// RegisterContextProvider registers a provider that implements the context-first ContextProvider interface.
+// Where a Provider is expected it's adapted with WithoutContext, and WithContext returns it unchanged.
+func RegisterContextProvider(name string, providerCreate func() (ContextProvider, error)) {
+	RegisterProvider(name, func() (Provider, error) {
+		provider, err := providerCreate()
+		if err != nil {
+			return nil, err
+		}
+		return WithoutContext(provider), nil
+	})
+}
+
+// RegisterLifecycleProvider registers a provider that only implements some of the capabilities of a
+// Provider. It's adapted with Complete, so As reports the capabilities it lacks.
+func RegisterLifecycleProvider(name string, providerCreate func() (Lifecycle, error)) {
+	RegisterProvider(name, func() (Provider, error) {
+		provider, err := providerCreate()
+		if err != nil {
+			return nil, err
+		}
+		return Complete(provider), nil
+	})
+}
+
 // GetProvider will retrieve a provider with the given name.
 func GetProvider(name string) (Provider, error) {
 	if providerCreate, ok := registry.providerCreation[name]; ok {
@@ -0,0 +1,60 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// triggerProviderUpgrade asks the provider to upgrade the cluster right away, for providers such as ARO
+// that upgrade through the ClusterVersion of the cluster instead of the managed-upgrade-operator.
+func triggerProviderUpgrade(ctx context.Context, provider spi.Provider) (*configv1.Update, error) {
+	if viper.GetString(config.Upgrade.Image) != "" {
+		return nil, fmt.Errorf("the %s provider can't upgrade to a release image", provider.Type())
+	}
+
+	version, err := util.OpenshiftVersionToSemver(viper.GetString(config.Upgrade.ReleaseName))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release name into semver: %w", err)
+	}
+
+	clusterID := viper.GetString(config.Cluster.ID)
+	if err := spi.WithContext(provider).Upgrade(ctx, clusterID, version.String(), time.Now()); err != nil {
+		return nil, fmt.Errorf("error initiating upgrade from provider: %w", err)
+	}
+	return &configv1.Update{Version: version.String()}, nil
+}
+
+// isClusterVersionUpgradeDone reports whether the ClusterVersion of the cluster finished upgrading to
+// the desired version, with a status message while it hasn't.
+func isClusterVersionUpgradeDone(ctx context.Context, client configclient.Interface, desired *configv1.Update) (done bool, msg string, err error) {
+	clusterVersion, err := client.ConfigV1().ClusterVersions().Get(ctx, ClusterVersionName, metav1.GetOptions{})
+	if err != nil {
+		// The API server is unavailable at times during an upgrade, try again next time
+		return false, fmt.Sprintf("couldn't get cluster version: %v", err), nil
+	}
+
+	history := clusterVersion.Status.History
+	if len(history) == 0 || history[0].Version != desired.Version {
+		return false, "upgrade yet to commence", nil
+	}
+	if history[0].State == configv1.CompletedUpdate {
+		return true, "", nil
+	}
+
+	msg = fmt.Sprintf("upgrade to %s in progress since %s", desired.Version, history[0].StartedTime)
+	for _, condition := range clusterVersion.Status.Conditions {
+		if condition.Type == configv1.ClusterStatusConditionType("Failing") && condition.Status == configv1.ConditionTrue {
+			msg = fmt.Sprintf("%s, failing: %s", msg, condition.Message)
+		}
+	}
+	return false, msg, nil
+}
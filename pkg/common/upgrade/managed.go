@@ -64,22 +64,22 @@ const (
 )
 
 // TriggerManagedUpgrade initiates an upgrade using the managed-upgrade-operator
-func TriggerManagedUpgrade(h *helper.H) (*configv1.Update, error) {
+func TriggerManagedUpgrade(ctx context.Context, h *helper.H) (*configv1.Update, error) {
 	// Create any pre-upgrade workloads to test the managed-upgrade-operator with
-	err := createUpgradeClusterWorkloads(h)
+	err := createUpgradeClusterWorkloads(ctx, h)
 	if err != nil {
 		return nil, err
 	}
 
 	// override the Hive-managed operator config with our testing one, so that
 	// we don't give worker upgrades the same upgrade grace periods
-	err = overrideOperatorConfig(h)
+	err = overrideOperatorConfig(ctx, h)
 	if err != nil {
 		return nil, fmt.Errorf("unable to override operator configuration: %v", err)
 	}
 
 	// Create the upgrade config and initiate the upgrade process
-	err = scheduleUpgradeWithProvider(h)
+	err = scheduleUpgradeWithProvider(ctx, h)
 	if err != nil {
 		return nil, fmt.Errorf("can't initiate managed upgrade: %v", err)
 	}
@@ -87,22 +87,24 @@ func TriggerManagedUpgrade(h *helper.H) (*configv1.Update, error) {
 	// We need to force the operator to resync with its provider. Whilst this would happen naturally,
 	// it's too long to wait for E2E (could be up to 1 hour). In lieu of a better way to trigger this,
 	// let's bounce the deployment to hurry that process.
-	err = restartOperator(h, muoNamespace)
+	err = restartOperator(ctx, h, muoNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("error restarting managed-upgrade-operator: %v", err)
 	}
 
 	// wait for a few seconds to get the upgradeconfig synced from upgradepolicy
 	for c := 0; c < 6; c++ {
-		ucCreated, _ := isUpgradeConfigCreated(h)
+		ucCreated, _ := isUpgradeConfigCreated(ctx, h)
 		if !ucCreated {
-			time.Sleep(30 * time.Second)
+			if err := util.Sleep(ctx, 30*time.Second); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	// Reschedule the upgrade if flag specified
 	if viper.GetBool(config.Upgrade.ManagedUpgradeRescheduled) {
-		err = updateUpgradeWithProvider()
+		err = updateUpgradeWithProvider(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("can't reschedule upgrade: %v", err)
 		}
@@ -129,9 +131,9 @@ func TriggerManagedUpgrade(h *helper.H) (*configv1.Update, error) {
 
 // Override the managed-upgrade-operator's existing configmap with an e2e-focused one, if
 // the existing configmap contains different values
-func overrideOperatorConfig(h *helper.H) error {
+func overrideOperatorConfig(ctx context.Context, h *helper.H) error {
 	// Retrieve the existing operator config data
-	cm, err := h.Kube().CoreV1().ConfigMaps(muoNamespace).Get(context.TODO(), "managed-upgrade-operator-config", metav1.GetOptions{})
+	cm, err := h.Kube().CoreV1().ConfigMaps(muoNamespace).Get(ctx, "managed-upgrade-operator-config", metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("managed-upgrade-operator configmap not found: %v", err)
 	}
@@ -175,7 +177,7 @@ func overrideOperatorConfig(h *helper.H) error {
 	// only update if we observe a difference between current config and testing config
 	if cfgData != configOverride {
 		cm.Data["config.yaml"] = configOverride
-		_, err = h.Kube().CoreV1().ConfigMaps(muoNamespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+		_, err = h.Kube().CoreV1().ConfigMaps(muoNamespace).Update(ctx, cm, metav1.UpdateOptions{})
 		if err != nil {
 			return fmt.Errorf("managed-upgrade-operator configmap could not be updated: %v", err)
 		}
@@ -187,7 +189,7 @@ func overrideOperatorConfig(h *helper.H) error {
 // Override the cluster's UpgradeConfig with custom values where needed.
 // This is primarily being used to override the Pod Disruption Budget timeout
 // in order to minimize the worker upgrade length.
-func overrideUpgradeConfig(uc upgradev1alpha1.UpgradeConfig, h *helper.H) error {
+func overrideUpgradeConfig(ctx context.Context, uc upgradev1alpha1.UpgradeConfig, h *helper.H) error {
 	// only update if we need to
 	if uc.Spec.PDBForceDrainTimeout == configPdbDrainTimeoutOverride {
 		return nil
@@ -203,14 +205,14 @@ func overrideUpgradeConfig(uc upgradev1alpha1.UpgradeConfig, h *helper.H) error
 		Group:    "upgrade.managed.openshift.io",
 		Version:  "v1alpha1",
 		Resource: "upgradeconfigs",
-	}).Namespace(muoNamespace).Update(context.TODO(), &uobj, metav1.UpdateOptions{})
+	}).Namespace(muoNamespace).Update(ctx, &uobj, metav1.UpdateOptions{})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func createManagedUpgradeWorkload(workLoadName string, workLoadDir string, podPrefixes []string, h *helper.H) error {
+func createManagedUpgradeWorkload(ctx context.Context, workLoadName string, workLoadDir string, podPrefixes []string, h *helper.H) error {
 	if _, ok := h.GetWorkload(workLoadName); ok {
 		return nil
 	}
@@ -226,11 +228,13 @@ func createManagedUpgradeWorkload(workLoadName string, workLoadDir string, podPr
 	log.Printf("%v object(s) created for %s workload from %s path\n", len(obj), workLoadName, workLoadDir)
 
 	// Give the cluster a second to churn before checking
-	time.Sleep(workloadCreationWaitTime * time.Second)
+	if err := util.Sleep(ctx, workloadCreationWaitTime*time.Second); err != nil {
+		return err
+	}
 
 	// Wait for all pods to come up healthy
-	err = wait.PollUntilContextTimeout(context.TODO(), 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
-		if check, err := healthchecks.CheckPodHealth(h.Kube().CoreV1(), nil, h.CurrentProject(), podPrefixes...); !check || err != nil {
+	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
+		if check, err := healthchecks.CheckPodHealth(ctx, h.Kube().CoreV1(), nil, h.CurrentProject(), podPrefixes...); !check || err != nil {
 			return false, nil
 		}
 		return true, nil
@@ -246,11 +250,11 @@ func createManagedUpgradeWorkload(workLoadName string, workLoadDir string, podPr
 }
 
 // IsManagedUpgradeDone returns with done true when a managed upgrade is complete.
-func isManagedUpgradeDone(h *helper.H) (done bool, msg string, err error) {
+func isManagedUpgradeDone(ctx context.Context, h *helper.H) (done bool, msg string, err error) {
 	// retrieve UpgradeConfig
 	ucList, err := h.Dynamic().Resource(schema.GroupVersionResource{
 		Group: "upgrade.managed.openshift.io", Version: "v1alpha1", Resource: "upgradeconfigs",
-	}).Namespace(muoNamespace).List(context.TODO(), metav1.ListOptions{})
+	}).Namespace(muoNamespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		// The API may sometimes be unavailable, this is fine. We don't want
 		// to return an error because there's every chance the upgrade is still going
@@ -267,7 +271,7 @@ func isManagedUpgradeDone(h *helper.H) (done bool, msg string, err error) {
 			// We shouldn't be upgrading if there's no cluster provider
 			return false, "", fmt.Errorf("error getting clusterprovider for upgrade: %v", err)
 		}
-		policyID, err := clusterProvider.GetUpgradePolicyID(clusterID)
+		policyID, err := spi.WithContext(clusterProvider).GetUpgradePolicyID(ctx, clusterID)
 		if err != nil {
 			// The provider errored when attempting to query its upgrade policies; try again next time
 			return false, fmt.Sprintf("couldn't check upgrade policy state from provider: %v", err), nil
@@ -295,7 +299,7 @@ func isManagedUpgradeDone(h *helper.H) (done bool, msg string, err error) {
 	}
 
 	// First attempt any necessary UpgradeConfig overrides we need to do during our regular sync/check
-	err = overrideUpgradeConfig(upgradeConfig, h)
+	err = overrideUpgradeConfig(ctx, upgradeConfig, h)
 	if err != nil {
 		// log it, but this isn't a problem worth failing out over
 		log.Printf("could not apply UpgradeConfig overrides: %v", err)
@@ -334,19 +338,19 @@ func isManagedUpgradeDone(h *helper.H) (done bool, msg string, err error) {
 }
 
 // Requests a cluster upgrade from the cluster provider
-func scheduleUpgradeWithProvider(h *helper.H) error {
+func scheduleUpgradeWithProvider(ctx context.Context, h *helper.H) error {
 	switch getProviderSource() {
 	case providerOCM:
-		return scheduleOCMUpgrade()
+		return scheduleOCMUpgrade(ctx)
 	case providerLocal:
-		return scheduleLocalUpgrade(h)
+		return scheduleLocalUpgrade(ctx, h)
 	default:
 		return fmt.Errorf("unable to determine upgrade policy provider to schedule upgrade")
 	}
 }
 
 // Reschedule the upgrade via the provider
-func updateUpgradeWithProvider() error {
+func updateUpgradeWithProvider(ctx context.Context) error {
 	// Only supported by OCM
 	if getProviderSource() == providerLocal {
 		return nil
@@ -363,7 +367,7 @@ func updateUpgradeWithProvider() error {
 	if err != nil {
 		return fmt.Errorf("error getting clusterprovider for upgrade: %v", err)
 	}
-	policyID, err := clusterProvider.GetUpgradePolicyID(clusterID)
+	policyID, err := spi.WithContext(clusterProvider).GetUpgradePolicyID(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("unable to retrieve upgrade policy ID to update: %v", err)
 	}
@@ -372,7 +376,7 @@ func updateUpgradeWithProvider() error {
 	}
 
 	newT := time.Now().UTC().Add(300 * time.Minute)
-	err = clusterProvider.UpdateSchedule(clusterID, upgradeVersion.String(), newT, policyID)
+	err = spi.WithContext(clusterProvider).UpdateSchedule(ctx, clusterID, upgradeVersion.String(), newT, policyID)
 	if err != nil {
 		return fmt.Errorf("error updating the upgrade schedule via provider: %v", err)
 	}
@@ -381,10 +385,10 @@ func updateUpgradeWithProvider() error {
 }
 
 // Scales down and scales up the operator deployment to initiate a pod restart
-func restartOperator(h *helper.H, ns string) error {
+func restartOperator(ctx context.Context, h *helper.H, ns string) error {
 	log.Printf("restarting managed-upgrade-operator to force upgrade resync..")
 
-	err := wait.PollUntilContextTimeout(context.TODO(), 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
 		// scale down
 		s, err := h.Kube().AppsV1().Deployments(ns).GetScale(ctx, "managed-upgrade-operator", metav1.GetOptions{})
 		if err != nil {
@@ -418,10 +422,10 @@ func restartOperator(h *helper.H, ns string) error {
 }
 
 // this makes sure that the upgradeconfig has been synced from provider to the cluster
-func isUpgradeConfigCreated(h *helper.H) (bool, error) {
+func isUpgradeConfigCreated(ctx context.Context, h *helper.H) (bool, error) {
 	ucList, err := h.Dynamic().Resource(schema.GroupVersionResource{
 		Group: "upgrade.managed.openshift.io", Version: "v1alpha1", Resource: "upgradeconfigs",
-	}).Namespace(muoNamespace).List(context.TODO(), metav1.ListOptions{})
+	}).Namespace(muoNamespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, err
 	}
@@ -432,11 +436,11 @@ func isUpgradeConfigCreated(h *helper.H) (bool, error) {
 }
 
 // check the upgradeconfig status to determine if the upgrade is started
-func isUpgradeTriggered(h *helper.H, desired *configv1.Update) (bool, error) {
+func isUpgradeTriggered(ctx context.Context, h *helper.H, desired *configv1.Update) (bool, error) {
 	// retrieve UpgradeConfig
 	ucObj, err := h.Dynamic().Resource(schema.GroupVersionResource{
 		Group: "upgrade.managed.openshift.io", Version: "v1alpha1", Resource: "upgradeconfigs",
-	}).Namespace(muoNamespace).Get(context.TODO(), upgradeConfigName, metav1.GetOptions{})
+	}).Namespace(muoNamespace).Get(ctx, upgradeConfigName, metav1.GetOptions{})
 	if err != nil {
 		// The API may sometimes be unavailable, this is fine. We don't want
 		// to return an error because there's every chance the upgrade is still going
@@ -466,11 +470,11 @@ func isUpgradeTriggered(h *helper.H, desired *configv1.Update) (bool, error) {
 
 // create cluster workloads prior to upgrade commencement which will test
 // manage-upgrade-operator's drain strategy functions
-func createUpgradeClusterWorkloads(h *helper.H) error {
+func createUpgradeClusterWorkloads(ctx context.Context, h *helper.H) error {
 	// Create Pod Disruption Budget test workloads if desired
 	if viper.GetBool(config.Upgrade.ManagedUpgradeTestPodDisruptionBudgets) {
 		pdbPodPrefixes := []string{"pdb"}
-		err := createManagedUpgradeWorkload(pdbWorkloadName, pdbWorkloadDir, pdbPodPrefixes, h)
+		err := createManagedUpgradeWorkload(ctx, pdbWorkloadName, pdbWorkloadDir, pdbPodPrefixes, h)
 		if err != nil {
 			return fmt.Errorf("unable to setup PDB workload for upgrade: %v", err)
 		}
@@ -479,7 +483,7 @@ func createUpgradeClusterWorkloads(h *helper.H) error {
 	// Create Node Drain test workloads if desired
 	if viper.GetBool(config.Upgrade.ManagedUpgradeTestNodeDrain) {
 		drainPodPrefixes := []string{"node-drain-test"}
-		err := createManagedUpgradeWorkload(drainWorkloadName, drainWorkloadDir, drainPodPrefixes, h)
+		err := createManagedUpgradeWorkload(ctx, drainWorkloadName, drainWorkloadDir, drainPodPrefixes, h)
 		if err != nil {
 			return fmt.Errorf("unable to setup node drain test workload for upgrade: %v", err)
 		}
@@ -499,9 +503,11 @@ func getProviderSource() string {
 
 type upgradeManager struct {
 	clusterID       string
-	clusterProvider spi.Provider
-	clusterVersion  *semver.Version
-	upgradeVersion  *semver.Version
+	clusterProvider spi.ContextProvider
+	// versionGates is whether the provider has the version gates capability
+	versionGates   bool
+	clusterVersion *semver.Version
+	upgradeVersion *semver.Version
 }
 
 // initUpgradeManager handles configuring an upgrade manager struct that contains
@@ -531,9 +537,12 @@ func initUpgradeManager() (*upgradeManager, error) {
 		return nil, fmt.Errorf("error getting clusterprovider for upgrade: %v", err)
 	}
 
+	_, hasVersionGates := spi.As[spi.VersionGater](clusterProvider)
+
 	return &upgradeManager{
 		clusterID:       clusterID,
-		clusterProvider: clusterProvider,
+		clusterProvider: spi.WithContext(clusterProvider),
+		versionGates:    hasVersionGates,
 		clusterVersion:  clusterVersion,
 		upgradeVersion:  upgradeVersion,
 	}, nil
@@ -541,24 +550,28 @@ func initUpgradeManager() (*upgradeManager, error) {
 
 // applyVersionGateAgreement adds the cluster version gate agreement for certain
 // cluster upgrade paths
-func applyVersionGateAgreement(upgradeManager *upgradeManager) error {
+func applyVersionGateAgreement(ctx context.Context, upgradeManager *upgradeManager) error {
 	clusterID := upgradeManager.clusterID
 	clusterProvider := upgradeManager.clusterProvider
 	clusterVersion := upgradeManager.clusterVersion
 	upgradeVersion := upgradeManager.upgradeVersion
 
 	if clusterVersion.Minor() < upgradeVersion.Minor() {
+		if !upgradeManager.versionGates {
+			log.Printf("Cluster upgrade type: y-stream. Skip applying cluster version gate agreement: %v", spi.NewNotSupportedError(clusterProvider.Type(), spi.CapabilityVersionGates))
+			return nil
+		}
 		log.Println("Cluster upgrade type: y-stream. Attempting to apply cluster version gate agreement.")
 
 		majorMinor := common.CreateMajorMinorStringFromSemver(upgradeVersion)
-		versionGateID, err := clusterProvider.GetVersionGateID(majorMinor, clusterProvider.VersionGateLabel())
+		versionGateID, err := clusterProvider.GetVersionGateID(ctx, majorMinor, clusterProvider.VersionGateLabel())
 		if err != nil {
 			log.Printf("Warning: version gate lookup failed: '%v'. Carrying on with the upgrade..", err)
 			return nil
 		}
 
 		log.Printf("Applying version gate id: %s to cluster", versionGateID)
-		err = clusterProvider.AddGateAgreement(clusterID, versionGateID)
+		err = clusterProvider.AddGateAgreement(ctx, clusterID, versionGateID)
 		if err != nil {
 			return err
 		}
@@ -570,13 +583,13 @@ func applyVersionGateAgreement(upgradeManager *upgradeManager) error {
 	return nil
 }
 
-func scheduleOCMUpgrade() error {
+func scheduleOCMUpgrade(ctx context.Context) error {
 	upgradeManager, err := initUpgradeManager()
 	if err != nil {
 		return err
 	}
 
-	err = applyVersionGateAgreement(upgradeManager)
+	err = applyVersionGateAgreement(ctx, upgradeManager)
 	if err != nil {
 		return err
 	}
@@ -584,7 +597,7 @@ func scheduleOCMUpgrade() error {
 	// Our time will be as closely allowed as possible by the provider (now + 7 min)
 	t := time.Now().UTC().Add(7 * time.Minute)
 
-	err = upgradeManager.clusterProvider.Upgrade(upgradeManager.clusterID, upgradeManager.upgradeVersion.String(), t)
+	err = upgradeManager.clusterProvider.Upgrade(ctx, upgradeManager.clusterID, upgradeManager.upgradeVersion.String(), t)
 	if err != nil {
 		return fmt.Errorf("error initiating upgrade from provider: %w", err)
 	}
@@ -592,7 +605,7 @@ func scheduleOCMUpgrade() error {
 	return nil
 }
 
-func scheduleLocalUpgrade(h *helper.H) error {
+func scheduleLocalUpgrade(ctx context.Context, h *helper.H) error {
 	// Validate the upgrade type is supported
 	var upgradeType upgradev1alpha1.UpgradeType
 	switch viper.GetString(config.Upgrade.Type) {
@@ -609,7 +622,7 @@ func scheduleLocalUpgrade(h *helper.H) error {
 		return err
 	}
 
-	err = applyVersionGateAgreement(upgradeManager)
+	err = applyVersionGateAgreement(ctx, upgradeManager)
 	if err != nil {
 		return err
 	}
@@ -645,7 +658,7 @@ func scheduleLocalUpgrade(h *helper.H) error {
 		Group:    "upgrade.managed.openshift.io",
 		Version:  "v1alpha1",
 		Resource: "upgradeconfigs",
-	}).Namespace(muoNamespace).Create(context.TODO(), &uobj, metav1.CreateOptions{})
+	}).Namespace(muoNamespace).Create(ctx, &uobj, metav1.CreateOptions{})
 
 	return err
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/spi"
 	"github.com/openshift/osde2e/pkg/common/util"
 )
 
@@ -40,7 +41,8 @@ var (
 )
 
 // RunUpgrade uses the OpenShift extended suite to upgrade a cluster to the image provided in cfg.
-func RunUpgrade(h *helper.H) error {
+// It returns an error matching spi.ErrNotSupported if the provider can't upgrade clusters.
+func RunUpgrade(ctx context.Context, h *helper.H) error {
 	var done bool
 	var msg string
 	var err error
@@ -62,11 +64,22 @@ func RunUpgrade(h *helper.H) error {
 	if err != nil {
 		return fmt.Errorf("can't determine provider for managed upgrade: %s", err)
 	}
+	if _, ok := spi.As[spi.Upgrader](provider); !ok {
+		return spi.NewNotSupportedError(provider.Type(), spi.CapabilityUpgrades)
+	}
+	// managed is whether the managed-upgrade-operator performs the upgrade
+	managed := true
 	switch provider.Type() {
 	case "rosa":
 		fallthrough
 	case "ocm":
-		desiredUpdate, err = TriggerManagedUpgrade(h)
+		desiredUpdate, err = TriggerManagedUpgrade(ctx, h)
+		if err != nil {
+			return fmt.Errorf("failed triggering upgrade: %v", err)
+		}
+	case "aro", "hypershift":
+		managed = false
+		desiredUpdate, err = triggerProviderUpgrade(ctx, provider)
 		if err != nil {
 			return fmt.Errorf("failed triggering upgrade: %v", err)
 		}
@@ -75,9 +88,11 @@ func RunUpgrade(h *helper.H) error {
 	}
 
 	// When the upgrade being rescheduled, we should expect that the upgrade will not be triggered
-	if viper.GetBool(config.Upgrade.ManagedUpgradeRescheduled) {
-		time.Sleep(10 * time.Minute)
-		triggered, err := isUpgradeTriggered(h, desiredUpdate)
+	if managed && viper.GetBool(config.Upgrade.ManagedUpgradeRescheduled) {
+		if err := util.Sleep(ctx, 10*time.Minute); err != nil {
+			return err
+		}
+		triggered, err := isUpgradeTriggered(ctx, h, desiredUpdate)
 		if triggered {
 			return fmt.Errorf("the upgrade was triggered unexpectly: %v", err)
 		} else {
@@ -90,15 +105,23 @@ func RunUpgrade(h *helper.H) error {
 
 	log.Println("Upgrading...")
 	done = false
-	if err = wait.PollUntilContextTimeout(context.TODO(), 10*time.Second, MaxDuration, true, func(ctx context.Context) (bool, error) {
+	if err = wait.PollUntilContextTimeout(ctx, 10*time.Second, MaxDuration, true, func(ctx context.Context) (bool, error) {
+		if !managed {
+			done, msg, err = isClusterVersionUpgradeDone(ctx, h.Cfg(), desiredUpdate)
+			if !done {
+				log.Printf("Upgrade in progress: %s", msg)
+			}
+			return done, err
+		}
+
 		// Keep the managed upgrade's configuration overrides in place, in case Hive has replaced them
-		err = overrideOperatorConfig(h)
+		err = overrideOperatorConfig(ctx, h)
 		// Log if it errored, but don't cancel the upgrade because of it
 		if err != nil {
 			log.Printf("problem overriding managed upgrade config: %v", err)
 		}
 		// If performing a managed upgrade, check if we want to wait for workers to fully upgrade too
-		done, msg, err = isManagedUpgradeDone(h)
+		done, msg, err = isManagedUpgradeDone(ctx, h)
 
 		if !done {
 			log.Printf("Upgrade in progress: %s", msg)
@@ -114,13 +137,13 @@ func RunUpgrade(h *helper.H) error {
 
 	log.Printf("Finished upgrading in %s, waiting for cluster to be ready", time.Since(upgradeStarted))
 
-	if err = cluster.WaitForClusterReadyPostUpgrade(viper.GetString(config.Cluster.ID), nil); err != nil {
+	if err = cluster.WaitForClusterReadyPostUpgrade(ctx, viper.GetString(config.Cluster.ID), nil); err != nil {
 		return fmt.Errorf("failed waiting for cluster ready: %v", err)
 	}
 
 	log.Println("Upgrade complete!")
 	if viper.GetBool(config.Upgrade.ManagedUpgradeTestNodeDrain) {
-		list, err := h.Kube().CoreV1().Pods(h.CurrentProject()).List(context.TODO(), metav1.ListOptions{LabelSelector: "app=node-drain-test"})
+		list, err := h.Kube().CoreV1().Pods(h.CurrentProject()).List(ctx, metav1.ListOptions{LabelSelector: "app=node-drain-test"})
 		if err != nil {
 			return fmt.Errorf("error listing pods: %s", err.Error())
 		}
@@ -128,12 +151,12 @@ func RunUpgrade(h *helper.H) error {
 			for _, item := range list.Items {
 				log.Printf("Removing finalizers from %s", item.Name)
 				item.Finalizers = []string{}
-				_, err = h.Kube().CoreV1().Pods(h.CurrentProject()).Update(context.TODO(), &item, metav1.UpdateOptions{})
+				_, err = h.Kube().CoreV1().Pods(h.CurrentProject()).Update(ctx, &item, metav1.UpdateOptions{})
 				if err != nil {
 					return err
 				}
 				log.Printf("Deleting pod %s", item.Name)
-				err = h.Kube().CoreV1().Pods(h.CurrentProject()).Delete(context.TODO(), item.Name, metav1.DeleteOptions{})
+				err = h.Kube().CoreV1().Pods(h.CurrentProject()).Delete(ctx, item.Name, metav1.DeleteOptions{})
 				if err != nil {
 					return err
 				}
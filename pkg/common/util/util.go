@@ -1,8 +1,10 @@
 package util
 
 import (
+	"context"
 	"math/rand"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 )
@@ -42,3 +44,15 @@ func ContainsErrorMarker(line string) bool {
 func ContainsFailureMarker(line string) bool {
 	return strings.Contains(line, "[FAILED]") || strings.Contains(line, "• [FAILED]")
 }
+
+// Sleep pauses for the given duration, returning early with the context error if ctx is done first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
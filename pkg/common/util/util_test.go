@@ -1,6 +1,11 @@
 package util
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestContainsErrorMarker(t *testing.T) {
 	tests := []struct {
@@ -112,3 +117,15 @@ func TestContainsFailureMarker(t *testing.T) {
 		})
 	}
 }
+
+func TestSleep(t *testing.T) {
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the sleep to be interrupted, got %v", err)
+	}
+}
@@ -27,7 +27,7 @@ type VersionSelector struct {
 
 // SelectClusterVersions sets versions in cfg if not set based on defaults and upgrade options.
 // If a release stream is set for an upgrade the previous available version is used and it's image is used for upgrade.
-func (v *VersionSelector) SelectClusterVersions() error {
+func (v *VersionSelector) SelectClusterVersions(ctx context.Context) error {
 	var err error
 	var versionSelector string
 
@@ -38,8 +38,8 @@ func (v *VersionSelector) SelectClusterVersions() error {
 			viper.Set(config.Cluster.Channel, "nightly")
 		}
 
-		err = wait.PollUntilContextTimeout(context.Background(), 1*time.Minute, 60*time.Minute, true, func(ctx context.Context) (bool, error) {
-			v.versionList, err = v.Provider.Versions()
+		err = wait.PollUntilContextTimeout(ctx, 1*time.Minute, 60*time.Minute, true, func(ctx context.Context) (bool, error) {
+			v.versionList, err = spi.WithContext(v.Provider).Versions(ctx)
 			if err != nil {
 				return false, fmt.Errorf("error getting versions: %v", err)
 			}
@@ -98,6 +98,7 @@ func (v *VersionSelector) setInstallVersion() (*semver.Version, string, error) {
 		log.Printf("Unable to select a cluster version.")
 	} else {
 		log.Printf("Using the %s '%s'", versionType, selectedVersion.Original())
+		viper.Set(config.Cluster.VersionSelector, versionType)
 	}
 
 	return selectedVersion, versionType, nil
@@ -107,6 +108,7 @@ func (v *VersionSelector) setInstallVersion() (*semver.Version, string, error) {
 func (v *VersionSelector) setUpgradeVersion() error {
 	if viper.GetString(config.Upgrade.ReleaseName) != "" || viper.GetString(config.Upgrade.Image) != "" {
 		log.Printf("Using user supplied upgrade state.")
+		viper.Set(config.Upgrade.VersionSelector, "user supplied upgrade")
 		return nil
 	}
 
@@ -116,7 +118,7 @@ func (v *VersionSelector) setUpgradeVersion() error {
 	}
 
 	upgradeSource := v.Provider.UpgradeSource()
-	releaseName, err := v.getUpgradeVersion()
+	releaseName, selector, err := v.getUpgradeVersion()
 	if err != nil {
 		return fmt.Errorf("error selecting an upgrade version: %v", err)
 	}
@@ -128,6 +130,7 @@ func (v *VersionSelector) setUpgradeVersion() error {
 
 	viper.Set(config.Upgrade.ReleaseName, releaseName)
 	viper.Set(config.Upgrade.Image, "")
+	viper.Set(config.Upgrade.VersionSelector, selector)
 
 	// set upgrade image
 	log.Printf("Selecting version '%s' to be able to upgrade to '%s' using upgrade source '%s'",
@@ -174,7 +177,7 @@ func (v *VersionSelector) getInstallVersion() (*semver.Version, string, error) {
 }
 
 // getUpgradeVersion will get a version based upon available configuration options.
-func (v *VersionSelector) getUpgradeVersion() (string, error) {
+func (v *VersionSelector) getUpgradeVersion() (string, string, error) {
 	var selectedVersionSelector upgradeselectors.Interface = nil
 
 	curPriority := math.MinInt32
@@ -190,7 +193,7 @@ func (v *VersionSelector) getUpgradeVersion() (string, error) {
 
 	// If no version selector has been found for an upgrade, assume that an upgrade is not being asked for.
 	if selectedVersionSelector == nil {
-		return "", nil
+		return "", "", nil
 	}
 
 	release, selector, err := selectedVersionSelector.SelectVersion(spi.NewVersionBuilder().Version(v.clusterVersion).Build(), v.versionList)
@@ -199,12 +202,12 @@ func (v *VersionSelector) getUpgradeVersion() (string, error) {
 		if err != nil {
 			log.Printf("Error selecting version: %s", err.Error())
 		}
-		return util.NoVersionFound, err
+		return util.NoVersionFound, selector, err
 	}
 
 	openshiftRelease := fmt.Sprintf("openshift-v%s", release.Version().Original())
 
 	log.Printf("Selected %s using selector `%s`", openshiftRelease, selector)
 
-	return openshiftRelease, err
+	return openshiftRelease, selector, err
 }
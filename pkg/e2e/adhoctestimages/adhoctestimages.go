@@ -3,7 +3,9 @@ package adhoctestimages
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
@@ -18,6 +20,8 @@ import (
 	"github.com/openshift/osde2e/pkg/common/executor"
 	"github.com/openshift/osde2e/pkg/common/label"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -60,8 +64,14 @@ var _ = ginkgo.Describe("Ad Hoc Test Images", ginkgo.Ordered, ginkgo.ContinueOnF
 			Timeout:               viper.GetDuration(config.Tests.AdHocTestContainerTimeout),
 			MCSimulationEnabled:   viper.GetBool(config.MCSimulation.Enable),
 			MCSimulationSkipInfra: viper.GetBool(config.MCSimulation.SkipInfraCheck),
+			Pod: executor.PodOverrides{
+				ArtifactImage: viper.GetString(config.Tests.AdHocTestArtifactImage),
+				RBAC:          executor.RBACProfile{Name: viper.GetString(config.Tests.AdHocTestRBACProfile)},
+			},
 		}
 		exe *executor.Executor
+		// parallelResults holds the results of all suites, in configuration order, when they are run in parallel.
+		parallelResults []executor.SuiteResult
 	)
 
 	// Get test suites using the new structured format
@@ -71,8 +81,8 @@ var _ = ginkgo.Describe("Ad Hoc Test Images", ginkgo.Ordered, ginkgo.ContinueOnF
 		ginkgo.Fail(fmt.Sprintf("Failed to get test suites configuration: %v", err))
 	}
 
-	for _, testSuite := range testSuites {
-		testImageEntries = append(testImageEntries, ginkgo.Entry(testSuite.Image+" should pass", testSuite))
+	for i, testSuite := range testSuites {
+		testImageEntries = append(testImageEntries, ginkgo.Entry(testSuite.Image+" should pass", i, testSuite))
 	}
 
 	ginkgo.BeforeAll(func(ctx context.Context) {
@@ -89,38 +99,68 @@ var _ = ginkgo.Describe("Ad Hoc Test Images", ginkgo.Ordered, ginkgo.ContinueOnF
 			imageNames[i] = suite.Image
 		}
 		logger.Info("executing test suites", "suites", imageNames)
+
+		// Run all suites up front when parallelism is requested. The table entries below then
+		// report the results one by one, so JUnit entries and notifications keep the configured order.
+		if parallelism := viper.GetInt(config.Tests.AdHocTestParallelism); parallelism > 1 && len(testSuites) > 1 {
+			suites := make([]executor.Suite, len(testSuites))
+			for i, testSuite := range testSuites {
+				suites[i], err = executorSuite(testSuite)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			logger.Info("running test suites in parallel", "parallelism", parallelism, "timeout", exeConfig.Timeout)
+			parallelResults = exe.ExecuteAll(ctx, suites, parallelism)
+
+			total := executor.Aggregate(parallelResults)
+			logger.Info("aggregated results", "total", total.TotalTests, "passed", total.PassedTests,
+				"failed", total.FailedTests, "skipped", total.SkippedTests, "errors", total.ErrorTests)
+		}
 	})
 
 	ginkgo.DescribeTable("execution",
-		func(ctx context.Context, testSuite config.TestSuite) {
+		func(ctx context.Context, index int, testSuite config.TestSuite) {
 			testImage := testSuite.Image
-			// Use image name + tag as dir name so suites from the same repo
-			// but different tags get separate artifact directories.
-			dirName := strings.ReplaceAll(testImage[strings.LastIndex(testImage, "/")+1:], ":", "-")
-			exeConfig.OutputDir = filepath.Join(viper.GetString(config.ReportDir), viper.GetString(config.Phase), dirName)
-
-			logger.Info("running test suite", "suite", testImage, "timeout", exeConfig.Timeout)
-			results, err := exe.Execute(ctx, testImage)
-			if err != nil {
-				logger.Error(err, "execution failed", "suite", testImage)
+			outputDir := suiteOutputDir(testImage)
+
+			var result executor.SuiteResult
+			if parallelResults != nil {
+				result = parallelResults[index]
+			} else {
+				suite, err := executorSuite(testSuite)
+				Expect(err).NotTo(HaveOccurred())
+				result = exe.ExecuteSuite(ctx, suite)
 			}
 
 			var allFailures []string
-			if err != nil {
-				allFailures = append(allFailures, fmt.Sprintf("execution failure: %v", err))
+			if result.Err != nil {
+				allFailures = append(allFailures, fmt.Sprintf("execution failure: %v", result.Err))
 			}
-			if results != nil {
-				for _, suite := range results.Suites {
+			allFailures = append(allFailures, result.Failures...)
+
+			if result.Results != nil {
+				for _, suite := range result.Results.Suites {
 					var failed int
 					for _, test := range suite.Tests {
 						if test.Error != nil {
 							failed++
-							allFailures = append(allFailures, fmt.Sprintf("test case failure: %q - %v", test.Name, test.Error))
 						}
 					}
 					logger.Info("suite results", "suite", suite.Name, "total", len(suite.Tests), "failed", failed)
 				}
 			}
+			if result.Attempts > 1 {
+				logger.Info("test suite was retried", "suite", testImage, "attempts", result.Attempts, "flaky", result.Flaky)
+			}
+
+			// Quarantined and flaky tests are reported separately and don't fail the suite.
+			if len(result.Informing) > 0 {
+				logger.Info("quarantined tests failed", "suite", testImage, "failures", result.Informing)
+				ginkgo.AddReportEntry("informing failures", strings.Join(result.Informing, "\n"))
+			}
+			if len(result.Flaky) > 0 {
+				ginkgo.AddReportEntry("flaky tests", strings.Join(result.Flaky, "\n"))
+			}
 
 			defer func() {
 				if len(allFailures) > 0 {
@@ -128,18 +168,107 @@ var _ = ginkgo.Describe("Ad Hoc Test Images", ginkgo.Ordered, ginkgo.ContinueOnF
 				}
 			}()
 
+			var analysisStatus string
 			if len(allFailures) > 0 {
 				combinedErr := fmt.Errorf("failures in %s: %s", testImage, strings.Join(allFailures, "; "))
 				var analysisContent string
 				if viper.GetBool(config.LogAnalysis.EnableAnalysis) {
-					analysisContent = runLogAnalysisForAdHocTestImage(ctx, logger, testSuite, combinedErr, exeConfig.OutputDir)
+					analysisContent = runLogAnalysisForAdHocTestImage(ctx, logger, testSuite, combinedErr, outputDir)
+					analysisStatus = "completed"
+					if analysisContent == "" {
+						analysisStatus = "failed"
+					}
 				}
-				queueNotification(testSuite, analysisContent, exeConfig.OutputDir)
+				queueNotification(testSuite, analysisContent, outputDir)
 			}
+			recordSuite(testImage, result, allFailures, analysisStatus)
 		},
 		testImageEntries)
 })
 
+// recordSuite records the result of a test suite for the run manifest.
+func recordSuite(testImage string, result executor.SuiteResult, failures []string, analysisStatus string) {
+	suite := runmanifest.Suite{
+		Name:            testImage,
+		Phase:           viper.GetString(config.Phase),
+		Image:           testImage,
+		Passed:          len(failures) == 0,
+		Attempts:        result.Attempts,
+		DurationSeconds: result.Duration.Seconds(),
+		Failures:        failures,
+		Flaky:           result.Flaky,
+		Informing:       result.Informing,
+		Analysis:        analysisStatus,
+	}
+	if result.Results != nil {
+		suite.Tests = result.Results.TotalTests
+		suite.PassedTests = result.Results.PassedTests
+		suite.FailedTests = result.Results.FailedTests
+		suite.SkippedTests = result.Results.SkippedTests
+		suite.ErrorTests = result.Results.ErrorTests
+	}
+	runmanifest.RecordSuite(suite)
+}
+
+// executorSuite converts a configured test suite into a suite for the executor.
+func executorSuite(testSuite config.TestSuite) (executor.Suite, error) {
+	pod, err := podOverrides(testSuite.Pod)
+	if err != nil {
+		return executor.Suite{}, fmt.Errorf("test suite %s: %w", testSuite.Image, err)
+	}
+	pod.RBAC = executor.RBACProfile{Name: testSuite.RBAC.Profile, ClusterRoles: testSuite.RBAC.ClusterRoles}
+
+	return executor.Suite{
+		Image:       testSuite.Image,
+		OutputDir:   suiteOutputDir(testSuite.Image),
+		MaxAttempts: testSuite.Retry.MaxAttempts,
+		RetryMode:   testSuite.Retry.Mode,
+		Quarantine:  testSuite.Quarantine,
+		Pod:         pod,
+	}, nil
+}
+
+// podOverrides converts the configured pod overrides of a test suite into executor pod overrides.
+func podOverrides(pod config.PodOverrides) (executor.PodOverrides, error) {
+	resources, err := executor.ParseResources(pod.Requests, pod.Limits)
+	if err != nil {
+		return executor.PodOverrides{}, err
+	}
+
+	overrides := executor.PodOverrides{
+		Resources:      resources,
+		NodeSelector:   pod.NodeSelector,
+		ServiceAccount: pod.ServiceAccount,
+		ArtifactImage:  pod.ArtifactImage,
+	}
+	for _, toleration := range pod.Tolerations {
+		overrides.Tolerations = append(overrides.Tolerations, corev1.Toleration{
+			Key:      toleration.Key,
+			Operator: corev1.TolerationOperator(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   corev1.TaintEffect(toleration.Effect),
+		})
+	}
+	// Sort the env so the pod spec is the same on every run.
+	for _, name := range slices.Sorted(maps.Keys(pod.Env)) {
+		overrides.Env = append(overrides.Env, corev1.EnvVar{Name: name, Value: pod.Env[name]})
+	}
+	for _, mount := range pod.ConfigMaps {
+		overrides.ConfigMaps = append(overrides.ConfigMaps, executor.Mount(mount))
+	}
+	for _, mount := range pod.Secrets {
+		overrides.Secrets = append(overrides.Secrets, executor.Mount(mount))
+	}
+	return overrides, nil
+}
+
+// suiteOutputDir returns the artifact directory for a test suite image. The image name and tag
+// are used as the dir name so suites from the same repo but different tags get separate directories.
+func suiteOutputDir(testImage string) string {
+	dirName := strings.ReplaceAll(testImage[strings.LastIndex(testImage, "/")+1:], ":", "-")
+	return filepath.Join(viper.GetString(config.ReportDir), viper.GetString(config.Phase), dirName)
+}
+
 // queueNotification adds a PendingNotification for deferred Slack delivery.
 // Called directly when log analysis is disabled so notifications are still sent.
 func queueNotification(testSuite config.TestSuite, analysisContent, outputDir string) {
@@ -28,6 +28,8 @@ import (
 	"github.com/openshift/osde2e/pkg/common/phase"
 	"github.com/openshift/osde2e/pkg/common/providers"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/prow"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
 	"github.com/openshift/osde2e/pkg/common/runner"
 	"github.com/openshift/osde2e/pkg/common/slack"
 	"github.com/openshift/osde2e/pkg/common/spi"
@@ -43,6 +45,9 @@ import (
 
 const tektonurl = "https://console-openshift-console.apps.rosa.appsrep09ue1.03r5.p3.openshiftapps.com/pipelines/ns/%s-pipelines"
 
+// runSpecs runs the ginkgo suite. It is replaced in tests to drive the orchestrator without running specs.
+var runSpecs = ginkgo.RunSpecs
+
 // RunTests initializes the orchestrator and runs the complete e2e test lifecycle.
 // This includes provisioning, test execution, log analysis (on failure), and reporting.
 func RunTests(ctx context.Context) int {
@@ -52,26 +57,44 @@ func RunTests(ctx context.Context) int {
 		log.Printf("Failed to create orchestrator: %v", err)
 		return config.Failure
 	}
+	orch = orchestrator.Timed(orch)
+	defer func() { orchestrator.WriteConfiguredMetricsFile(orch.Result()) }()
+
+	// Reporting and cleanup still run once the run has been canceled, so they get a context
+	// that isn't canceled along with ctx.
+	finalizeCtx := context.WithoutCancel(ctx)
+
+	if err := orch.PreProcess(ctx); err != nil {
+		log.Printf("Pre-processing failed: %v", err)
+		orch.Result().ExitCode = config.Failure
+		orch.Result().MarkAborted(ctx)
+		return orch.Result().ExitCode
+	}
 
 	// Provision cluster
 	if err := orch.Provision(ctx); err != nil {
 		log.Printf("Provision failed: %v", err)
-		if viper.GetBool(config.LogAnalysis.EnableAnalysis) {
+		aborted := orch.Result().MarkAborted(ctx)
+		if viper.GetBool(config.LogAnalysis.EnableAnalysis) && !aborted {
 			if err := orch.AnalyzeLogs(ctx, err); err != nil {
 				log.Printf("Log analysis failed: %v", err)
 			}
 		}
-		if err := orch.Report(ctx); err != nil {
+		if err := orch.Report(finalizeCtx); err != nil {
 			log.Printf("Report errors: %v", err)
 		}
+		if aborted {
+			return config.Aborted
+		}
 		return config.Failure
 	}
 
 	// Execute tests
 	testErr := orch.Execute(ctx)
+	aborted := orch.Result().MarkAborted(ctx)
 
 	// On failure: analyze logs (results are cached for Report)
-	if testErr != nil {
+	if testErr != nil && !aborted {
 		log.Printf("Tests failed: %v", testErr)
 
 		if viper.GetBool(config.LogAnalysis.EnableAnalysis) && viper.GetString(config.Tests.TestSuites) == "" && viper.GetString(config.Tests.AdHocTestImages) == "" {
@@ -84,17 +107,17 @@ func RunTests(ctx context.Context) int {
 	// Post-process cluster: must-gather, cluster state inspection, property
 	// updates. Runs before Report so diagnostic data is included in the
 	// S3 artifact upload.
-	if err := orch.PostProcessCluster(ctx); err != nil {
+	if err := orch.PostProcessCluster(finalizeCtx); err != nil {
 		log.Printf("Cluster post-processing errors: %v", err)
 	}
 
 	// Report: upload artifacts, send notifications, generate reports
-	if err := orch.Report(ctx); err != nil {
+	if err := orch.Report(finalizeCtx); err != nil {
 		log.Printf("Report errors: %v", err)
 	}
 
 	// Cleanup resources and delete cluster
-	if err := orch.Cleanup(ctx); err != nil {
+	if err := orch.Cleanup(finalizeCtx); err != nil {
 		log.Printf("Cleanup errors: %v", err)
 	}
 
@@ -111,6 +134,10 @@ type E2EOrchestrator struct {
 	s3Results         []aws.S3UploadResult
 	perSuiteS3Results map[string][]aws.S3UploadResult // keyed by test image
 	analysisResult    *analysisengine.Result
+	startedAt         time.Time
+	phases            []runmanifest.Phase
+	// healthChecks is the number of health checks recorded in the result so far
+	healthChecks int
 }
 
 // NewOrchestrator creates a new E2E orchestrator instance.
@@ -130,21 +157,23 @@ func NewOrchestrator(ctx context.Context) (orchestrator.Orchestrator, error) {
 		suiteConfig:       suiteConfig,
 		reporterConfig:    reporterConfig,
 		perSuiteS3Results: make(map[string][]aws.S3UploadResult),
+		startedAt:         time.Now(),
 		result: &orchestrator.Result{
 			ExitCode: config.Success,
 		},
 	}, nil
 }
 
-// PreProcess is a no-op for the E2E orchestrator; validation is handled
-// by the existing config loading pipeline.
+// PreProcess validates what the config loading pipeline can't, i.e. the health checks named in
+// the config, which are only known once they are registered.
 func (o *E2EOrchestrator) PreProcess(ctx context.Context) error {
-	return nil
+	return cluster.ValidateHealthChecks()
 }
 
 // Provision prepares the cluster environment.
 func (o *E2EOrchestrator) Provision(ctx context.Context) error {
 	ctrlog.SetLogger(ginkgo.GinkgoLogr)
+	defer o.recordHealthChecks()
 
 	// Load cluster context (kubeconfig and cluster ID)
 	if err := cluster.LoadClusterContext(); err != nil {
@@ -153,7 +182,7 @@ func (o *E2EOrchestrator) Provision(ctx context.Context) error {
 	}
 
 	// Provision or reuse cluster
-	cl, err := cluster.ProvisionOrReuseCluster(o.provider)
+	cl, err := cluster.ProvisionOrReuseCluster(ctx, o.provider)
 	runner.ReportClusterInstallLogs(o.provider)
 	if err != nil {
 		o.result.ExitCode = config.Failure
@@ -163,7 +192,7 @@ func (o *E2EOrchestrator) Provision(ctx context.Context) error {
 	o.result.ClusterID = cl.ID()
 
 	// Install addons if configured
-	if _, err := cluster.InstallAddonsIfConfigured(o.provider, cl.ID()); err != nil {
+	if _, err := cluster.InstallAddonsIfConfigured(ctx, o.provider, cl.ID()); err != nil {
 		runner.ReportClusterInstallLogs(o.provider)
 		o.result.ExitCode = config.Failure
 		return fmt.Errorf("addon installation failed: %w", err)
@@ -232,6 +261,15 @@ func (o *E2EOrchestrator) Execute(ctx context.Context) error {
 	// Determine test execution plan
 	runInstallTests := true
 	upgradeCluster := viper.GetString(config.Upgrade.Image) != "" || viper.GetString(config.Upgrade.ReleaseName) != ""
+	o.result.UpgradePassed = true
+	if _, ok := spi.As[spi.Upgrader](o.provider); upgradeCluster && !ok {
+		// An upgrade was asked for, so fail it up front and run the install tests as if there were none
+		err := spi.NewNotSupportedError(o.provider.Type(), spi.CapabilityUpgrades)
+		o.result.RecordStep(orchestrator.StepUpgrade, "", time.Now(), err)
+		o.result.Errors = append(o.result.Errors, fmt.Errorf("upgrade failed: %w", err))
+		o.result.UpgradePassed = false
+		upgradeCluster = false
+	}
 
 	if upgradeCluster {
 		runInstallTests = viper.GetBool(config.Upgrade.RunPreUpgradeTests)
@@ -246,16 +284,18 @@ func (o *E2EOrchestrator) Execute(ctx context.Context) error {
 	}
 
 	// Run upgrade and post-upgrade tests
-	o.result.UpgradePassed = true
 	if upgradeCluster {
-		if err := o.runUpgrade(); err != nil {
+		if err := o.runUpgrade(ctx); err != nil {
 			o.result.Errors = append(o.result.Errors, err)
 			o.result.UpgradePassed = false
 		}
 	}
 
+	// Hold the cluster to its SLOs once all tests ran
+	sloPassed := o.checkSLOGate(ctx)
+
 	// Set final result
-	if !o.result.TestsPassed || !o.result.UpgradePassed {
+	if !o.result.TestsPassed || !o.result.UpgradePassed || !sloPassed {
 		o.result.ExitCode = config.Failure
 		return fmt.Errorf("tests failed")
 	}
@@ -264,21 +304,26 @@ func (o *E2EOrchestrator) Execute(ctx context.Context) error {
 }
 
 // cleanStaleJunitFiles removes junit XML files from previous runs in the report directory,
-// keeping only the file matching the current run suffix.
+// keeping only the files of the current run: the test results and the cluster health checks.
 func cleanStaleJunitFiles() {
 	reportDir := viper.GetString(config.ReportDir)
 	if reportDir == "" {
 		return
 	}
 	suffix := viper.GetString(config.Suffix)
-	currentJunit := "junit_" + suffix + ".xml"
+	currentJunit := map[string]bool{
+		"junit_" + suffix + ".xml":     true,
+		cluster.HealthJUnitFile(false): true,
+		cluster.HealthJUnitFile(true):  true,
+		cluster.SLOJUnitFile():         true,
+	}
 
 	walkFn := func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
 		name := info.Name()
-		if strings.HasPrefix(name, "junit_") && strings.HasSuffix(name, ".xml") && name != currentJunit {
+		if strings.HasPrefix(name, "junit_") && strings.HasSuffix(name, ".xml") && !currentJunit[name] {
 			os.Remove(path)
 		}
 		return nil
@@ -368,9 +413,89 @@ func (o *E2EOrchestrator) Report(ctx context.Context) error {
 	}
 
 	runner.ReportClusterInstallLogs(o.provider)
+
+	if err := o.writeRunManifest(); err != nil {
+		log.Printf("Failed to write run manifest: %v", err)
+	}
+	return nil
+}
+
+// writeRunManifest writes run.json to the report directory. It runs after the S3 upload so the
+// manifest can link to the uploaded artifacts.
+func (o *E2EOrchestrator) writeRunManifest() error {
+	reportDir := viper.GetString(config.ReportDir)
+	if reportDir == "" {
+		return fmt.Errorf("no report directory configured")
+	}
+
+	manifest := &runmanifest.Manifest{
+		JobName:    viper.GetString(config.JobName),
+		StartedAt:  o.startedAt,
+		FinishedAt: time.Now(),
+		ExitCode:   o.result.ExitCode,
+		Passed:     o.result.ExitCode == config.Success,
+		Cluster: runmanifest.Cluster{
+			ID:            viper.GetString(config.Cluster.ID),
+			Name:          viper.GetString(config.Cluster.Name),
+			Provider:      viper.GetString(config.Provider),
+			CloudProvider: viper.GetString(config.CloudProvider.CloudProviderID),
+			Region:        viper.GetString(config.CloudProvider.Region),
+			Version:       viper.GetString(config.Cluster.Version),
+			Hypershift:    viper.GetBool(config.Hypershift),
+		},
+		Versions: runmanifest.Versions{
+			Install:         viper.GetString(config.Cluster.Version),
+			InstallSelector: viper.GetString(config.Cluster.VersionSelector),
+			Upgrade:         viper.GetString(config.Upgrade.ReleaseName),
+			UpgradeSelector: viper.GetString(config.Upgrade.VersionSelector),
+		},
+		Phases:       o.phases,
+		HealthChecks: runmanifest.HealthChecks(),
+		Provisions:   runmanifest.Provisions(),
+		Analysis:     runmanifest.Analysis{Status: "skipped"},
+	}
+	if jobID := viper.GetString(config.JobID); jobID != "-1" {
+		manifest.JobID = jobID
+	}
+	if url, ok := prow.JobURL(); ok {
+		manifest.JobURL = url
+	}
+	if manifest.Versions.Upgrade == "" {
+		manifest.Versions.Upgrade = viper.GetString(config.Upgrade.Image)
+	}
+
+	for _, suite := range runmanifest.Suites() {
+		for _, r := range o.perSuiteS3Results[suite.Image] {
+			suite.Artifacts = append(suite.Artifacts, toManifestArtifact(r))
+		}
+		manifest.Suites = append(manifest.Suites, suite)
+	}
+	for _, r := range o.s3Results {
+		manifest.Artifacts = append(manifest.Artifacts, toManifestArtifact(r))
+	}
+	if o.analysisResult != nil {
+		manifest.Analysis = runmanifest.Analysis{Status: o.analysisResult.Status, Error: o.analysisResult.Error}
+	}
+	for _, err := range o.result.Errors {
+		manifest.Errors = append(manifest.Errors, err.Error())
+	}
+
+	if err := runmanifest.Write(reportDir, manifest); err != nil {
+		return err
+	}
+	log.Printf("Run manifest written to %s", filepath.Join(reportDir, runmanifest.FileName))
 	return nil
 }
 
+func toManifestArtifact(r aws.S3UploadResult) runmanifest.Artifact {
+	return runmanifest.Artifact{
+		Name: filepath.Base(r.Key),
+		URI:  r.S3URI,
+		URL:  r.PresignedURL,
+		Size: r.Size,
+	}
+}
+
 // sendFailureNotification sends a test failure notification via Slack.
 // If LLM analysis results are available they are included; otherwise a
 // basic failure notice is sent. Called by Report after S3 upload so that
@@ -733,9 +858,24 @@ func (o *E2EOrchestrator) Result() *orchestrator.Result {
 }
 
 // runTestsInPhase executes tests for a specific phase.
-func (o *E2EOrchestrator) runTestsInPhase(phaseName, description string) bool {
+func (o *E2EOrchestrator) runTestsInPhase(phaseName, description string) (passed bool) {
 	viper.Set(config.Phase, phaseName)
 
+	startedAt := time.Now()
+	defer func() {
+		o.phases = append(o.phases, runmanifest.Phase{
+			Name:            phaseName,
+			StartedAt:       startedAt,
+			DurationSeconds: time.Since(startedAt).Seconds(),
+			Passed:          passed,
+		})
+		var err error
+		if !passed {
+			err = fmt.Errorf("%s tests failed", phaseName)
+		}
+		o.result.RecordStep(orchestrator.StepTests, phaseName, startedAt, err)
+	}()
+
 	reportDir := viper.GetString(config.ReportDir)
 	phaseDir := filepath.Join(reportDir, phaseName)
 	if err := os.MkdirAll(phaseDir, 0o755); err != nil {
@@ -756,6 +896,7 @@ func (o *E2EOrchestrator) runTestsInPhase(phaseName, description string) bool {
 		if err != nil {
 			log.Printf("Error creating junit report: %v", err)
 		}
+		recordGinkgoSuite(phaseName, description, report)
 
 		// Log failure details so they appear in test_output.log.
 		// Ginkgo's output interceptor is inactive during ReportAfterSuite,
@@ -787,10 +928,9 @@ func (o *E2EOrchestrator) runTestsInPhase(phaseName, description string) bool {
 	}
 
 	// Run tests
-	var passed bool
 	func() {
 		defer ginkgo.GinkgoRecover()
-		passed = ginkgo.RunSpecs(ginkgo.GinkgoT(), description, o.suiteConfig, o.reporterConfig)
+		passed = runSpecs(ginkgo.GinkgoT(), description, o.suiteConfig, o.reporterConfig)
 	}()
 
 	// Generate dependencies for periodic jobs
@@ -799,8 +939,73 @@ func (o *E2EOrchestrator) runTestsInPhase(phaseName, description string) bool {
 	return passed
 }
 
+// checkSLOGate evaluates the SLO assertions of the gate against the cluster and reports whether it met them.
+func (o *E2EOrchestrator) checkSLOGate(ctx context.Context) bool {
+	if o.suiteConfig.DryRun || !viper.GetBool(config.Tests.SLOGate) || viper.GetBool(config.Tests.SkipSLOGate) {
+		return true
+	}
+
+	startedAt := time.Now()
+	passed, err := cluster.CheckSLOGate(ctx, viper.GetString(config.Cluster.ID), nil)
+	if err != nil {
+		log.Printf("SLO gate failed: %v", err)
+		o.result.Errors = append(o.result.Errors, err)
+	}
+	o.result.RecordStep(orchestrator.StepSLOGate, "", startedAt, err)
+	return passed
+}
+
+// recordHealthChecks adds the cluster health checks that ran since the last call to the steps of the result.
+func (o *E2EOrchestrator) recordHealthChecks() {
+	checks := runmanifest.HealthChecks()
+	for _, check := range checks[min(o.healthChecks, len(checks)):] {
+		var err error
+		if !check.Passed {
+			err = errors.New(check.Error)
+		}
+		o.result.RecordStepAt(orchestrator.Step{
+			Name:       orchestrator.StepHealthCheck,
+			Phase:      check.Phase,
+			StartedAt:  check.StartedAt,
+			FinishedAt: check.StartedAt.Add(time.Duration(check.DurationSeconds * float64(time.Second))),
+			Err:        err,
+		})
+	}
+	o.healthChecks = len(checks)
+}
+
+// recordGinkgoSuite records the result of the Ginkgo suite for the run manifest.
+func recordGinkgoSuite(phaseName, description string, report ginkgo.Report) {
+	suite := runmanifest.Suite{
+		Name:            description,
+		Phase:           phaseName,
+		Passed:          report.SuiteSucceeded,
+		Attempts:        1,
+		DurationSeconds: report.RunTime.Seconds(),
+	}
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt {
+			continue
+		}
+		suite.Tests++
+		switch {
+		case spec.State.Is(types.SpecStatePassed):
+			suite.PassedTests++
+		case spec.State.Is(types.SpecStateSkipped | types.SpecStatePending):
+			suite.SkippedTests++
+		case spec.State.Is(types.SpecStateFailed):
+			suite.FailedTests++
+			suite.Failures = append(suite.Failures, spec.FullText())
+		default:
+			suite.ErrorTests++
+			suite.Failures = append(suite.Failures, spec.FullText())
+		}
+	}
+	runmanifest.RecordSuite(suite)
+}
+
 // runUpgrade performs cluster upgrade and runs post-upgrade tests.
-func (o *E2EOrchestrator) runUpgrade() error {
+func (o *E2EOrchestrator) runUpgrade(ctx context.Context) error {
 	if viper.GetString(config.Kubeconfig.Contents) == "" {
 		return fmt.Errorf("unable to perform upgrade: no kubeconfig found")
 	}
@@ -810,7 +1015,11 @@ func (o *E2EOrchestrator) runUpgrade() error {
 		return fmt.Errorf("failed to generate helper for upgrade: %w", err)
 	}
 
-	if err := upgrade.RunUpgrade(h); err != nil {
+	startedAt := time.Now()
+	err = upgrade.RunUpgrade(ctx, h)
+	o.result.RecordStep(orchestrator.StepUpgrade, "", startedAt, err)
+	o.recordHealthChecks()
+	if err != nil {
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
 
@@ -0,0 +1,202 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/fakeprovider"
+	"github.com/openshift/osde2e/pkg/common/runmanifest"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// harness drives RunTests against the fake provider with the ginkgo suite stubbed out.
+type harness struct {
+	provider *fakeprovider.FakeProvider
+	passed   bool
+	suites   []string
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	runmanifest.Reset()
+	t.Cleanup(runmanifest.Reset)
+	viper.Set(config.ReportDir, t.TempDir())
+	viper.Set(config.Suffix, "test")
+	viper.Set(config.Tests.SuiteTimeout, 1)
+	viper.Set(config.Tests.GinkgoLogLevel, "succinct")
+	viper.Set(config.Tests.SkipClusterHealthChecks, true)
+	viper.Set(config.SkipMustGather, true)
+	viper.Set(config.Provider, fakeprovider.Name)
+	viper.Set(config.Cluster.InstallTimeout, 1)
+	viper.Set(config.Cluster.EnoughVersionsForOldestOrMiddleTest, true)
+	viper.Set(config.Cluster.PreviousVersionFromDefaultFound, true)
+
+	h := &harness{
+		// Clusters are ready on the first poll so provisioning doesn't wait.
+		provider: fakeprovider.New().WithLaunchScript(spi.ClusterStateReady),
+		passed:   true,
+	}
+	fakeprovider.Install(h.provider)
+
+	original := runSpecs
+	runSpecs = func(_ ginkgo.GinkgoTestingT, description string, args ...interface{}) bool {
+		h.suites = append(h.suites, description)
+		return h.passed
+	}
+	t.Cleanup(func() { runSpecs = original })
+
+	return h
+}
+
+func (h *harness) property(t *testing.T, key string) string {
+	t.Helper()
+	value, err := h.provider.GetProperty(viper.GetString(config.Cluster.ID), key)
+	if err != nil {
+		t.Fatalf("Failed to get property %s: %v", key, err)
+	}
+	return value
+}
+
+func (h *harness) manifest(t *testing.T) *runmanifest.Manifest {
+	t.Helper()
+	manifest, err := runmanifest.Read(filepath.Join(viper.GetString(config.ReportDir), runmanifest.FileName))
+	if err != nil {
+		t.Fatalf("Failed to read run manifest: %v", err)
+	}
+	return manifest
+}
+
+func TestRunTests_Passing(t *testing.T) {
+	h := newHarness(t)
+
+	if exitCode := RunTests(context.Background()); exitCode != config.Success {
+		t.Errorf("Expected exit code %d, got %d", config.Success, exitCode)
+	}
+
+	if len(h.suites) != 1 || h.suites[0] != "OSD e2e suite" {
+		t.Errorf("Expected the install suite to run once, got %v", h.suites)
+	}
+	if !h.provider.Called("LaunchCluster") {
+		t.Error("Expected a cluster to be launched")
+	}
+	if status := h.property(t, clusterproperties.Status); status != clusterproperties.StatusCompletedPassing {
+		t.Errorf("Expected status %s, got %s", clusterproperties.StatusCompletedPassing, status)
+	}
+	if availability := h.property(t, clusterproperties.Availability); availability != clusterproperties.Used {
+		t.Errorf("Expected availability %s, got %s", clusterproperties.Used, availability)
+	}
+	if !h.provider.Called("DeleteCluster") {
+		t.Error("Expected the cluster to be deleted")
+	}
+
+	manifest := h.manifest(t)
+	if !manifest.Passed || manifest.Cluster.ID != viper.GetString(config.Cluster.ID) {
+		t.Errorf("Expected a passing manifest for the cluster, got %+v", manifest)
+	}
+	if len(manifest.Phases) != 1 || manifest.Phases[0].Name != "install" || !manifest.Phases[0].Passed {
+		t.Errorf("Expected a passing install phase, got %+v", manifest.Phases)
+	}
+}
+
+func TestRunTests_StepMetrics(t *testing.T) {
+	h := newHarness(t)
+	h.passed = false
+	metricsFile := filepath.Join(t.TempDir(), "osde2e.prom")
+	viper.Set(config.MetricsFile, metricsFile)
+
+	RunTests(context.Background())
+
+	data, err := os.ReadFile(metricsFile)
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	for _, want := range []string{
+		`osde2e_step_duration_seconds{job="",phase="",provider="fake",step="provision"}`,
+		`osde2e_step_success{job="",phase="install",provider="fake",step="tests"} 0`,
+		`osde2e_step_success{job="",phase="",provider="fake",step="cleanup"} 1`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in:\n%s", want, data)
+		}
+	}
+}
+
+func TestRunTests_Failing(t *testing.T) {
+	h := newHarness(t)
+	h.passed = false
+
+	if exitCode := RunTests(context.Background()); exitCode != config.Failure {
+		t.Errorf("Expected exit code %d, got %d", config.Failure, exitCode)
+	}
+
+	if status := h.property(t, clusterproperties.Status); status != clusterproperties.StatusCompletedFailing {
+		t.Errorf("Expected status %s, got %s", clusterproperties.StatusCompletedFailing, status)
+	}
+	if !h.provider.Called("DeleteCluster") {
+		t.Error("Expected the cluster to be deleted after failing tests")
+	}
+	if manifest := h.manifest(t); manifest.Passed || manifest.ExitCode != config.Failure {
+		t.Errorf("Expected a failing manifest, got %+v", manifest)
+	}
+}
+
+func TestRunTests_ProvisionFailure(t *testing.T) {
+	h := newHarness(t)
+	h.provider.FailOn("LaunchCluster", errors.New("no capacity"))
+
+	if exitCode := RunTests(context.Background()); exitCode != config.Failure {
+		t.Errorf("Expected exit code %d, got %d", config.Failure, exitCode)
+	}
+
+	if len(h.suites) != 0 {
+		t.Errorf("Expected no suites to run, got %v", h.suites)
+	}
+	if h.provider.Called("DeleteCluster") {
+		t.Error("Expected no cluster to be deleted")
+	}
+}
+
+func TestRunTests_SkipDestroyCluster(t *testing.T) {
+	h := newHarness(t)
+	viper.Set(config.Cluster.SkipDestroyCluster, true)
+
+	if exitCode := RunTests(context.Background()); exitCode != config.Success {
+		t.Errorf("Expected exit code %d, got %d", config.Success, exitCode)
+	}
+
+	if h.provider.Called("DeleteCluster") {
+		t.Error("Expected the cluster to be preserved")
+	}
+	if !h.provider.Called("ExtendExpiry") {
+		t.Error("Expected the expiry of the preserved cluster to be extended")
+	}
+}
+
+func TestRunTests_ReuseCluster(t *testing.T) {
+	h := newHarness(t)
+	h.provider.AddCluster("existing", "existing", "4.16.0", spi.ClusterStateReady)
+	viper.Set(config.Cluster.ID, "existing")
+	viper.Set(config.Kubeconfig.Contents, fakeprovider.DefaultKubeconfig)
+
+	if exitCode := RunTests(context.Background()); exitCode != config.Success {
+		t.Errorf("Expected exit code %d, got %d", config.Success, exitCode)
+	}
+
+	if h.provider.Called("LaunchCluster") {
+		t.Error("Expected the existing cluster to be reused")
+	}
+	if viper.GetString(config.Cluster.Version) != "4.16.0" {
+		t.Errorf("Expected version of the existing cluster, got %s", viper.GetString(config.Cluster.Version))
+	}
+}
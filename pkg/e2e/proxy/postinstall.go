@@ -21,6 +21,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/logging"
 	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/spi"
 )
 
 var (
@@ -50,16 +51,20 @@ func testAddProxy() {
 		clusterID := viper.GetString(config.Cluster.ID)
 		clusterProvider, err := providers.ClusterProvider()
 		Expect(err).NotTo(HaveOccurred())
+		proxyManager, ok := spi.As[spi.ProxyManager](clusterProvider)
+		if !ok {
+			ginkgo.Skip(spi.NewNotSupportedError(clusterProvider.Type(), spi.CapabilityClusterProxies).Error())
+		}
 
 		httpsProxy := viper.GetString(config.Proxy.HttpsProxy)
 		httpProxy := viper.GetString(config.Proxy.HttpProxy)
 		userCABundle := viper.GetString(config.Proxy.UserCABundle)
-		userCABundleData, err := clusterProvider.LoadUserCaBundleData(userCABundle)
+		userCABundleData, err := proxyManager.LoadUserCaBundleData(userCABundle)
 		Expect(err).NotTo(HaveOccurred())
 
 		logger.Printf("Setting cluster-wide proxy to httpsProxy=%v,httpProxy=%v,settingCA=%v",
 			httpsProxy, httpProxy, userCABundle != "")
-		err = clusterProvider.AddClusterProxy(clusterID, httpsProxy, httpProxy, userCABundle)
+		err = proxyManager.AddClusterProxy(clusterID, httpsProxy, httpProxy, userCABundle)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Wait to see proxy reflected on the cluster
@@ -111,7 +116,7 @@ func testAddProxy() {
 		// So now, is the cluster still healthy?
 		logger.Printf("Verifying cluster health after proxy addition..")
 		err = wait.PollUntilContextTimeout(ctx, 30*time.Second, proxyHealthCheckWaitDuration, true, func(ctx context.Context) (bool, error) {
-			isHealthy, failures, _ := cluster.PollClusterHealth(clusterID, logger)
+			isHealthy, failures, _ := cluster.PollClusterHealth(ctx, clusterID, logger)
 			if isHealthy {
 				logger.Printf("cluster is healthy after proxy addition\n")
 				return true, nil
@@ -134,8 +139,12 @@ func testRemoveProxy() {
 		clusterID := viper.GetString(config.Cluster.ID)
 		clusterProvider, err := providers.ClusterProvider()
 		Expect(err).NotTo(HaveOccurred())
+		proxyManager, ok := spi.As[spi.ProxyManager](clusterProvider)
+		if !ok {
+			ginkgo.Skip(spi.NewNotSupportedError(clusterProvider.Type(), spi.CapabilityClusterProxies).Error())
+		}
 
-		err = clusterProvider.RemoveClusterProxy(clusterID)
+		err = proxyManager.RemoveClusterProxy(clusterID)
 		Expect(err).NotTo(HaveOccurred())
 
 		// Wait to see proxy reflected on the cluster
@@ -169,7 +178,7 @@ func testRemoveProxy() {
 		// So now, is the cluster still healthy?
 		logger.Printf("Verifying cluster health after proxy removed..")
 		err = wait.PollUntilContextTimeout(ctx, 30*time.Second, proxyHealthCheckWaitDuration, true, func(ctx context.Context) (bool, error) {
-			isHealthy, failures, _ := cluster.PollClusterHealth(clusterID, logger)
+			isHealthy, failures, _ := cluster.PollClusterHealth(ctx, clusterID, logger)
 			if isHealthy {
 				logger.Printf("cluster is healthy after proxy removed\n")
 				return true, nil
@@ -14,16 +14,10 @@ import (
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/label"
 	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/slo"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-// A mapping of alerts to ignore by cluster provider and environment.
-var ignoreAlerts = map[string]map[string][]string{
-	"ocm": {
-		"int": {"MetricsClientSendFailingSRE"},
-	},
-}
-
 var clusterStateTestName string = "[Suite: e2e] Cluster state"
 
 var _ = ginkgo.Describe(clusterStateTestName, ginkgo.Ordered, label.E2E, func() {
@@ -69,31 +63,28 @@ var _ = ginkgo.Describe(clusterStateTestName, ginkgo.Ordered, label.E2E, func()
 		clusterProvider, err := providers.ClusterProvider()
 		Expect(err).NotTo(HaveOccurred(), "error retrieving cluster provider")
 
-		Expect(!findCriticalAlerts(queryJSON, viper.GetString(config.Provider), clusterProvider.Environment())).Should(BeTrue(), "never able to find zero alerts")
+		// The alerts allowed to fire by provider and environment are part of the SLO config
+		sloConfig, err := slo.Load(viper.GetString(config.Tests.SLOConfig))
+		Expect(err).NotTo(HaveOccurred(), "error loading the SLO config")
+		policy := sloConfig.Alerts
+		if policy == nil {
+			policy = &slo.AlertPolicy{}
+		}
+		cluster := slo.Cluster{Provider: viper.GetString(config.Provider), Environment: clusterProvider.Environment()}
+
+		Expect(!findCriticalAlerts(queryJSON, policy, cluster)).Should(BeTrue(), "never able to find zero alerts")
 	})
 })
 
-func findCriticalAlerts(results []result, provider, environment string) bool {
+func findCriticalAlerts(results []result, policy *slo.AlertPolicy, cluster slo.Cluster) bool {
 	foundCritical := false
 	for _, result := range results {
 		ignoredCritical := false
-		if result.Metric.Severity == "critical" {
-			// If there alerts to ignore for this provider, let's look through them.
-			if ignoreForEnv, ok := ignoreAlerts[provider]; ok {
-				// If we can find alerts to ignore for this environment, let's look through those, too.
-				if ignoreAlertList, ok := ignoreForEnv[environment]; ok {
-					for _, alertToIgnore := range ignoreAlertList {
-						// If we find an alert in our ignore alert list, set this flag. This will indicate that the presence
-						// of this alert will not fail this test.
-						if alertToIgnore == result.Metric.AlertName {
-							ignoredCritical = true
-							break
-						}
-					}
-				}
-			}
-
-			if !ignoredCritical {
+		if policy.Fails(result.Metric.Severity) {
+			// Alerts allowed to fire for this provider and environment don't fail this test.
+			if policy.Allowed(cluster, result.Metric.AlertName) {
+				ignoredCritical = true
+			} else {
 				foundCritical = true
 			}
 		}
@@ -1,6 +1,10 @@
 package state
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/openshift/osde2e/pkg/common/slo"
+)
 
 func TestFindCriticalAlerts(t *testing.T) {
 	tests := []struct {
@@ -142,8 +146,14 @@ func TestFindCriticalAlerts(t *testing.T) {
 		},
 	}
 
+	sloConfig, err := slo.Load("")
+	if err != nil {
+		t.Fatalf("Unable to load the default SLO config: %v", err)
+	}
+
 	for _, test := range tests {
-		if findCriticalAlerts(test.Results, test.Provider, test.Environment) != test.Expected {
+		cluster := slo.Cluster{Provider: test.Provider, Environment: test.Environment}
+		if findCriticalAlerts(test.Results, sloConfig.Alerts, cluster) != test.Expected {
 			t.Errorf("Test %s did not produce expected result (%t) for finding critical alerts", test.Name, test.Expected)
 		}
 	}
@@ -149,7 +149,7 @@ func (k *KrknAI) Provision(ctx context.Context) error {
 	}
 
 	// Provision or reuse cluster
-	cl, err := cluster.ProvisionOrReuseCluster(k.provider)
+	cl, err := cluster.ProvisionOrReuseCluster(ctx, k.provider)
 	if err != nil {
 		return fmt.Errorf("failed to provision cluster: %w", err)
 	}
@@ -305,6 +305,11 @@ func (k *KrknAI) ensureClusterExpiryForKrknRun() error {
 	if clusterID == "" {
 		return nil
 	}
+	expiry, ok := spi.As[spi.ExpiryManager](k.provider)
+	if !ok {
+		log.Printf("krkn-ai: not extending cluster expiration: %v", spi.NewNotSupportedError(k.provider.Type(), spi.CapabilityExpiry))
+		return nil
+	}
 
 	duration := krknPrometheusTokenDuration(
 		viper.GetInt(config.KrknAI.Generations),
@@ -321,7 +326,7 @@ func (k *KrknAI) ensureClusterExpiryForKrknRun() error {
 	}
 
 	log.Printf("krkn-ai: setting cluster %s expiration to now + %v (pre-run)", clusterID, duration)
-	if err := k.provider.Expire(clusterID, duration); err != nil {
+	if err := expiry.Expire(clusterID, duration); err != nil {
 		return fmt.Errorf("extend cluster expiry: %w", err)
 	}
 	k.krknExtendedClusterExpiry = true
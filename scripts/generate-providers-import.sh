@@ -13,6 +13,10 @@ echo "// This import list is necessary due to the statically linked nature of go
 echo "import ("
 find "$PROVIDERS_DIR" -mindepth 1 -type d -print0 | sort -z | while read -r -d $'\0' provider; do
 PROVIDER_NAME="$(basename "$provider")"
+# The fake provider is only for tests, which import it themselves
+if [ "$PROVIDER_NAME" == "fakeprovider" ]; then
+continue
+fi
 echo -e "\t_ \"github.com/openshift/osde2e/pkg/common/providers/$PROVIDER_NAME\""
 done
 echo ")"